@@ -7,9 +7,18 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/pandorasNox/lettr/pkg/account"
+	"github.com/pandorasNox/lettr/pkg/clock"
+	"github.com/pandorasNox/lettr/pkg/leaderboard"
 	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/random"
+	"github.com/pandorasNox/lettr/pkg/redis"
 	"github.com/pandorasNox/lettr/pkg/router"
+	"github.com/pandorasNox/lettr/pkg/router/routes"
 	"github.com/pandorasNox/lettr/pkg/server"
 	"github.com/pandorasNox/lettr/pkg/session"
 )
@@ -27,6 +36,22 @@ type env struct {
 	port        string
 	githubToken string
 	imprintUrl  string
+	// challengeSecret keeps challenge links valid across restarts
+	challengeSecret string
+	speedTimeLimit  time.Duration
+	// mailFile receives login mails until a real mail service is plugged in
+	mailFile string
+	// sessionSecrets keep sessions in encrypted cookies instead of the
+	// memory and sign the session cookies, the first one seals and signs,
+	// the others are still accepted
+	sessionSecrets []string
+	// redisURL shares the sessions, leaderboards and accounts between
+	// instances, it takes precedence over sessionSecrets
+	redisURL     string
+	cookiePolicy session.CookiePolicy
+	// trustedProxyHeader holds the client address set by the proxy in
+	// front, e.g. Fly-Client-IP, the remote address is used without it
+	trustedProxyHeader string
 }
 
 func (e env) String() string {
@@ -39,6 +64,33 @@ func (e env) String() string {
 	if e.imprintUrl != "" {
 		s = fmt.Sprintf("%s\nimprint: %s", s, e.imprintUrl)
 	}
+
+	if e.speedTimeLimit != 0 {
+		s = fmt.Sprintf("%s\nspeed time limit: %s", s, e.speedTimeLimit)
+	}
+
+	if e.mailFile != "" {
+		s = fmt.Sprintf("%s\nmail file: %s", s, e.mailFile)
+	}
+
+	if e.challengeSecret != "" {
+		s = fmt.Sprintf("%s\nchallenge secret (length): %d", s, len(e.challengeSecret))
+	}
+
+	if len(e.sessionSecrets) > 0 {
+		s = fmt.Sprintf("%s\nsession cookie secrets: %d", s, len(e.sessionSecrets))
+	}
+
+	if e.redisURL != "" {
+		s = fmt.Sprintf("%s\nsession redis (length): %d", s, len(e.redisURL))
+	}
+	if e.trustedProxyHeader != "" {
+		s = fmt.Sprintf("%s\ntrusted proxy header: %s", s, e.trustedProxyHeader)
+	}
+	s = fmt.Sprintf(
+		"%s\nsession cookie: %s (domain '%s', secure %t, max age %s)",
+		s, e.cookiePolicy.Name, e.cookiePolicy.Domain, e.cookiePolicy.Secure, e.cookiePolicy.MaxAge,
+	)
 	// s = s + fmt.Sprintf("foo: %s\n", e.port)
 	return s
 }
@@ -48,13 +100,47 @@ func main() {
 
 	envCfg := envConfig()
 	server := server.Server{}
-	sessions := session.NewSessions()
+	sessions := session.NewSessions(clock.Real{}, random.New(time.Now().UnixNano()))
+	err := sessions.SetCookiePolicy(envCfg.cookiePolicy)
+	if err != nil {
+		log.Fatalf("session cookie: %s", err)
+	}
+	sessions.SetTrustedProxyHeader(envCfg.trustedProxyHeader)
+	if len(envCfg.sessionSecrets) > 0 {
+		err = sessions.SetTokenSecrets(envCfg.sessionSecrets...)
+		if err != nil {
+			log.Fatalf("session cookie: %s", err)
+		}
+	}
+	var leaderboardStore leaderboard.Store
+	var accounts account.Accounts
+	switch {
+	case envCfg.redisURL != "":
+		if len(envCfg.sessionSecrets) == 0 {
+			log.Fatalf("REDIS_URL requires SESSION_SECRETS, instances have to verify the session cookies signed by each other")
+		}
+		client, err := redis.ParseURL(envCfg.redisURL)
+		if err != nil {
+			log.Fatalf("session redis: %s", err)
+		}
+		_, err = client.Do("PING")
+		if err != nil {
+			log.Fatalf("session redis not reachable: %s", err)
+		}
+		sessions.SetStore(session.NewRedisStore(client))
+		leaderboardStore = leaderboard.NewRedisStore(client)
+		accounts = account.NewRedisStore(clock.Real{}, client)
+	case len(envCfg.sessionSecrets) > 0:
+		sessions.SetStore(session.MustNewCookieStore(envCfg.sessionSecrets...))
+	}
 
 	wordDb := puzzle.WordDatabase{}
-	err := wordDb.Init(embedFs, puzzle.FilePathsByLang())
+	err = wordDb.Init(embedFs, puzzle.FilePathsByLang())
 	if err != nil {
 		log.Fatalf("init wordDatabase failed: %s", err)
 	}
+	// win rates are kept in memory, each instance observes its own players
+	wordDb.Stats = puzzle.NewWordStats()
 
 	log.Printf("env conf:\n%s", envCfg)
 
@@ -63,7 +149,21 @@ func main() {
 		log.Fatalf("subtree for 'static' dir of embed fs failed: %s", err) //TODO
 	}
 
-	router := router.New(staticFS, &server, &sessions, wordDb, envCfg.imprintUrl, envCfg.githubToken, Revision, FaviconPath)
+	app := routes.NewApp(&sessions, wordDb, routes.Config{
+		ImprintUrl:  envCfg.imprintUrl,
+		GithubToken: envCfg.githubToken,
+		Revision:    Revision,
+		FaviconPath: FaviconPath,
+
+		ChallengeSecret: envCfg.challengeSecret,
+		SpeedTimeLimit:  envCfg.speedTimeLimit,
+		MailFile:        envCfg.mailFile,
+
+		LeaderboardStore: leaderboardStore,
+		Accounts:         accounts,
+	}, &server)
+
+	router := router.New(staticFS, app)
 
 	// v1 := http.NewServeMux()
 	// v1.Handle("/v1/", http.StripPrefix("/v1", muxWithMiddlewares))
@@ -87,5 +187,77 @@ func envConfig() env {
 		log.Printf("(optional) environment variable IMPRINT_URL not set")
 	}
 
-	return env{port: port, githubToken: gt, imprintUrl: imprintUrl}
+	challengeSecret, ok := os.LookupEnv("CHALLENGE_SECRET")
+	if !ok {
+		log.Printf("(optional) environment variable CHALLENGE_SECRET not set, challenge links break on restart")
+	}
+
+	var speedTimeLimit time.Duration
+	if v, ok := os.LookupEnv("SPEED_TIME_LIMIT"); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatalf("environment variable SPEED_TIME_LIMIT must be a positive duration like '3m': %s", v)
+		}
+		speedTimeLimit = d
+	}
+
+	mailFile, ok := os.LookupEnv("MAIL_FILE")
+	if !ok {
+		log.Printf("(optional) environment variable MAIL_FILE not set, login mails are written to the log")
+	}
+
+	var sessionSecrets []string
+	if v, ok := os.LookupEnv("SESSION_SECRETS"); ok && v != "" {
+		sessionSecrets = strings.Split(v, ",")
+	} else {
+		log.Printf("(optional) environment variable SESSION_SECRETS not set, sessions are kept in memory")
+	}
+
+	redisURL, ok := os.LookupEnv("REDIS_URL")
+	if !ok {
+		log.Printf("(optional) environment variable REDIS_URL not set, sessions, leaderboards and accounts are not shared between instances")
+	}
+
+	trustedProxyHeader, ok := os.LookupEnv("TRUSTED_PROXY_HEADER")
+	if !ok {
+		log.Printf("(optional) environment variable TRUSTED_PROXY_HEADER not set, sessions are limited per remote address")
+	}
+
+	profile, ok := os.LookupEnv("SESSION_COOKIE_PROFILE")
+	if !ok {
+		log.Printf("(optional) environment variable SESSION_COOKIE_PROFILE not set, using '%s', set '%s' for plain http", session.COOKIE_PROFILE_PROD, session.COOKIE_PROFILE_DEV)
+	}
+	cookiePolicy, err := session.CookiePolicyByProfile(profile)
+	if err != nil {
+		log.Fatalf("environment variable SESSION_COOKIE_PROFILE: %s", err)
+	}
+	if v, ok := os.LookupEnv("SESSION_COOKIE_NAME"); ok {
+		cookiePolicy.Name = v
+	}
+	if v, ok := os.LookupEnv("SESSION_COOKIE_DOMAIN"); ok {
+		cookiePolicy.Domain = v
+	}
+	if v, ok := os.LookupEnv("SESSION_COOKIE_SECURE"); ok {
+		secure, err := strconv.ParseBool(v)
+		if err != nil {
+			log.Fatalf("environment variable SESSION_COOKIE_SECURE must be 'true' or 'false': %s", v)
+		}
+		cookiePolicy.Secure = secure
+	}
+	if v, ok := os.LookupEnv("SESSION_COOKIE_SAMESITE"); ok {
+		sameSite, err := session.ParseSameSite(v)
+		if err != nil {
+			log.Fatalf("environment variable SESSION_COOKIE_SAMESITE: %s", err)
+		}
+		cookiePolicy.SameSite = sameSite
+	}
+	if v, ok := os.LookupEnv("SESSION_COOKIE_MAX_AGE"); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("environment variable SESSION_COOKIE_MAX_AGE must be a duration like '24h': %s", v)
+		}
+		cookiePolicy.MaxAge = d
+	}
+
+	return env{port: port, githubToken: gt, imprintUrl: imprintUrl, challengeSecret: challengeSecret, speedTimeLimit: speedTimeLimit, mailFile: mailFile, sessionSecrets: sessionSecrets, redisURL: redisURL, cookiePolicy: cookiePolicy, trustedProxyHeader: trustedProxyHeader}
 }
@@ -4,6 +4,10 @@ import (
 	iofs "io/fs"
 	"slices"
 	"testing"
+
+	"github.com/pandorasNox/lettr/pkg/i18n"
+	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/puzzle"
 )
 
 // todo: test for ???:
@@ -29,6 +33,40 @@ func Test_ExpectedEmbededFiles(t *testing.T) {
 
 }
 
+func Test_RegisteredWordListsAreEmbeded(t *testing.T) {
+	wordDb := puzzle.WordDatabase{}
+	err := wordDb.Init(embedFs, puzzle.FilePathsByLang())
+	if err != nil {
+		t.Fatalf("init wordDatabase from embeded word lists failed: %s", err)
+	}
+
+	for _, d := range language.DefaultRegistry.Definitions() {
+		if len(wordDb.Db[d.Code][puzzle.WC_ALL]) == 0 {
+			t.Errorf("expected words for language '%s', got none", d.Code)
+		}
+
+		// themed collections are offered by their translated names
+		for _, c := range wordDb.Collections(d.Code) {
+			// themes pick from the dictionaries only
+			for w := range wordDb.Db[d.Code][c] {
+				if !wordDb.Exists(d.Code, w) {
+					t.Errorf("themed word '%s' of collection '%s' is not in the word lists of language '%s'", w, c, d.Code)
+				}
+			}
+
+			key := "collection." + string(c)
+			if i18n.T(d.Code, key) == key {
+				t.Errorf("themed collection '%s' of language '%s' misses its name '%s' in the catalogs", c, d.Code, key)
+			}
+			for _, tag := range wordDb.CollectionTags(d.Code, c) {
+				if key := "tag." + tag; i18n.T(d.Code, key) == key {
+					t.Errorf("tag '%s' of collection '%s' misses its name '%s' in the catalogs", tag, c, key)
+				}
+			}
+		}
+	}
+}
+
 func getAllFilenames(efs iofs.FS) (files []string, err error) {
 	if err := iofs.WalkDir(efs, ".", func(path string, d iofs.DirEntry, err error) error {
 		if d.IsDir() {
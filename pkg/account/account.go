@@ -0,0 +1,187 @@
+// Package account lets players claim their session with an email address
+// and continue on another device. There are no passwords, a login is
+// confirmed with a single use link sent by a Mailer.
+package account
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pandorasNox/lettr/pkg/clock"
+	"github.com/pandorasNox/lettr/pkg/random"
+	"github.com/pandorasNox/lettr/pkg/session"
+)
+
+const LOGIN_TOKEN_LIFETIME = 15 * time.Minute
+
+var ErrInvalidEmail = errors.New("invalid email address")
+var ErrInvalidLoginToken = errors.New("invalid or expired login token")
+
+type Account struct {
+	ID        string
+	Email     string
+	CreatedAt time.Time
+	Progress  session.Progress
+}
+
+type loginToken struct {
+	email     string
+	expiresAt time.Time
+}
+
+// Accounts are kept by Store in memory or by RedisStore shared between
+// instances.
+type Accounts interface {
+	session.ProgressStore
+	// RequestLogin returns a token which logs in to the account of email
+	// once redeemed.
+	RequestLogin(email string) (string, error)
+	// Redeem consumes the token and returns the account it belongs to.
+	Redeem(token string) (Account, error)
+	Get(id string) (Account, bool)
+	// Delete removes the account and all its data.
+	Delete(id string)
+}
+
+// Store keeps accounts and pending logins in memory, they are lost on
+// restart and not shared between instances. It implements
+// session.ProgressStore.
+type Store struct {
+	mutex    sync.Mutex
+	accounts map[string]*Account
+	tokens   map[string]loginToken
+	clock    clock.Clock
+}
+
+var _ Accounts = (*Store)(nil)
+
+func NewStore(c clock.Clock) *Store {
+	return &Store{accounts: map[string]*Account{}, tokens: map[string]loginToken{}, clock: c}
+}
+
+// NormalizeEmail validates a plain address without display name and lower
+// cases it, so the same player always ends up in the same account.
+func NormalizeEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || len(email) > 254 {
+		return "", ErrInvalidEmail
+	}
+
+	return strings.ToLower(email), nil
+}
+
+// RequestLogin returns a token which logs in to the account of email once
+// redeemed. The account is created on the first login.
+func (s *Store) RequestLogin(email string) (string, error) {
+	email, err := NormalizeEmail(email)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := random.SecureString(32)
+	if err != nil {
+		return "", fmt.Errorf("account store: creating login token failed: %s", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.expire()
+	s.tokens[token] = loginToken{email: email, expiresAt: s.clock.Now().Add(LOGIN_TOKEN_LIFETIME)}
+
+	return token, nil
+}
+
+// Redeem consumes the token and returns the account it belongs to.
+func (s *Store) Redeem(token string) (Account, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.expire()
+	t, ok := s.tokens[token]
+	if !ok {
+		return Account{}, ErrInvalidLoginToken
+	}
+	delete(s.tokens, token)
+
+	for _, a := range s.accounts {
+		if a.Email == t.email {
+			return a.clone(), nil
+		}
+	}
+
+	a := &Account{
+		ID:        uuid.NewString(),
+		Email:     t.email,
+		CreatedAt: s.clock.Now(),
+		Progress:  session.Progress{},
+	}
+	s.accounts[a.ID] = a
+
+	return a.clone(), nil
+}
+
+func (s *Store) Get(id string) (Account, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	a, ok := s.accounts[id]
+	if !ok {
+		return Account{}, false
+	}
+
+	return a.clone(), true
+}
+
+// Delete removes the account and all its data, sessions claimed by it become
+// anonymous on their next request.
+func (s *Store) Delete(id string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.accounts, id)
+}
+
+func (s *Store) LoadProgress(accountID string) (session.Progress, bool, error) {
+	a, ok := s.Get(accountID)
+	return a.Progress, ok, nil
+}
+
+// SaveProgress ignores unknown accounts, a deleted account is not brought
+// back by a device that did not notice yet.
+func (s *Store) SaveProgress(accountID string, p session.Progress) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	a, ok := s.accounts[accountID]
+	if !ok {
+		return
+	}
+
+	a.Progress = p
+	a.Progress.PastWords = slices.Clone(p.PastWords)
+	a.Progress.History = slices.Clone(p.History)
+}
+
+func (s *Store) expire() {
+	now := s.clock.Now()
+	for token, t := range s.tokens {
+		if now.After(t.expiresAt) {
+			delete(s.tokens, token)
+		}
+	}
+}
+
+func (a Account) clone() Account {
+	a.Progress.PastWords = slices.Clone(a.Progress.PastWords)
+	a.Progress.History = slices.Clone(a.Progress.History)
+	return a
+}
@@ -0,0 +1,85 @@
+package account
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/clock"
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/session"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		email   string
+		want    string
+		wantErr error
+	}{
+		{email: "player@example.com", want: "player@example.com"},
+		{email: " Player@Example.com ", want: "player@example.com"},
+		{email: "player", wantErr: ErrInvalidEmail},
+		{email: "Player <player@example.com>", wantErr: ErrInvalidEmail},
+		{email: "", wantErr: ErrInvalidEmail},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeEmail(tt.email)
+		if err != tt.wantErr || got != tt.want {
+			t.Errorf("NormalizeEmail(%q) = %q, %v, want %q, %v", tt.email, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestStore_login(t *testing.T) {
+	c := clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	s := NewStore(c)
+
+	token, err := s.RequestLogin("player@example.com")
+	if err != nil {
+		t.Fatalf("RequestLogin() error = %s", err)
+	}
+
+	first, err := s.Redeem(token)
+	if err != nil {
+		t.Fatalf("Redeem() error = %s", err)
+	}
+	if _, err := s.Redeem(token); !errors.Is(err, ErrInvalidLoginToken) {
+		t.Errorf("Redeem() twice error = %v, want %v", err, ErrInvalidLoginToken)
+	}
+
+	token, _ = s.RequestLogin("PLAYER@example.com")
+	second, err := s.Redeem(token)
+	if err != nil {
+		t.Fatalf("Redeem() error = %s", err)
+	}
+	if second.ID != first.ID {
+		t.Errorf("second login created account %s, want %s", second.ID, first.ID)
+	}
+
+	token, _ = s.RequestLogin("player@example.com")
+	c.Advance(LOGIN_TOKEN_LIFETIME + time.Second)
+	if _, err := s.Redeem(token); !errors.Is(err, ErrInvalidLoginToken) {
+		t.Errorf("Redeem() expired error = %v, want %v", err, ErrInvalidLoginToken)
+	}
+}
+
+func TestStore_progress(t *testing.T) {
+	s := NewStore(clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)))
+	token, _ := s.RequestLogin("player@example.com")
+	a, _ := s.Redeem(token)
+
+	words := []puzzle.Word{{'c', 'r', 'i', 'e', 'd'}}
+	s.SaveProgress(a.ID, session.Progress{PastWords: words, SurvivalBest: 50})
+	words[0] = puzzle.Word{'t', 'r', 'i', 'e', 'd'}
+
+	p, ok, _ := s.LoadProgress(a.ID)
+	if !ok || p.SurvivalBest != 50 || len(p.PastWords) != 1 || p.PastWords[0].String() != "cried" {
+		t.Errorf("LoadProgress() = %+v, %t, want the saved progress", p, ok)
+	}
+
+	s.Delete(a.ID)
+	s.SaveProgress(a.ID, session.Progress{SurvivalBest: 60})
+	if _, ok, _ := s.LoadProgress(a.ID); ok {
+		t.Errorf("SaveProgress() brought back a deleted account")
+	}
+}
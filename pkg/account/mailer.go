@@ -0,0 +1,46 @@
+package account
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+)
+
+// Mailer delivers login links. Production setups plug in a real mail
+// service, LogMailer and FileMailer are local stand-ins.
+type Mailer interface {
+	Send(to string, subject string, body string) error
+}
+
+// LogMailer writes every mail to the standard logger.
+type LogMailer struct{}
+
+func (LogMailer) Send(to string, subject string, body string) error {
+	log.Printf("mail to '%s': %s\n%s", to, subject, body)
+	return nil
+}
+
+// FileMailer appends every mail to the file at Path.
+type FileMailer struct {
+	mutex sync.Mutex
+	Path  string
+}
+
+func (m *FileMailer) Send(to string, subject string, body string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	f, err := os.OpenFile(m.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("file mailer: opening '%s' failed: %s", m.Path, err)
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "To: %s\nSubject: %s\n\n%s\n\n", to, subject, body)
+	if err != nil {
+		return fmt.Errorf("file mailer: writing '%s' failed: %s", m.Path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package account
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pandorasNox/lettr/pkg/clock"
+	"github.com/pandorasNox/lettr/pkg/random"
+	"github.com/pandorasNox/lettr/pkg/redis"
+	"github.com/pandorasNox/lettr/pkg/session"
+)
+
+const (
+	REDIS_KEY_PREFIX       = "lettr:account:"
+	REDIS_EMAIL_KEY_PREFIX = "lettr:account-email:"
+	REDIS_LOGIN_KEY_PREFIX = "lettr:account-login:"
+)
+
+// RedisStore keeps accounts and pending logins on a Redis protocol server,
+// so they survive restarts and several instances share them. Every account
+// is a hash of its email, creation time and progress, an index maps the
+// emails to the accounts and login tokens expire on their own.
+type RedisStore struct {
+	client *redis.Client
+	clock  clock.Clock
+}
+
+var _ Accounts = (*RedisStore)(nil)
+
+func NewRedisStore(c clock.Clock, client *redis.Client) *RedisStore {
+	return &RedisStore{client: client, clock: c}
+}
+
+// takeScript returns and removes a login token, so it is redeemed once.
+//
+// KEYS[1] login key
+var takeScript = redis.NewScript(`
+local email = redis.call('GET', KEYS[1])
+redis.call('DEL', KEYS[1])
+return email
+`)
+
+// createScript returns the id of the account of the email, the account is
+// created unless another login got there first.
+//
+// KEYS[1] email key, KEYS[2] key of the new account
+// ARGV[1] id of the new account, ARGV[2] email, ARGV[3] creation time,
+// ARGV[4] progress
+var createScript = redis.NewScript(`
+local id = redis.call('GET', KEYS[1])
+if id then
+	return id
+end
+redis.call('SET', KEYS[1], ARGV[1])
+redis.call('HSET', KEYS[2], 'email', ARGV[2], 'created', ARGV[3], 'progress', ARGV[4])
+return ARGV[1]
+`)
+
+// saveProgressScript stores the progress of existing accounts only.
+//
+// KEYS[1] account key
+// ARGV[1] progress
+var saveProgressScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'progress', ARGV[1])
+return 1
+`)
+
+func (rs *RedisStore) RequestLogin(email string) (string, error) {
+	email, err := NormalizeEmail(email)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := random.SecureString(32)
+	if err != nil {
+		return "", fmt.Errorf("account store: creating login token failed: %s", err)
+	}
+
+	ttl := strconv.Itoa(int(LOGIN_TOKEN_LIFETIME.Seconds()))
+	_, err = rs.client.Do("SET", REDIS_LOGIN_KEY_PREFIX+token, email, "EX", ttl)
+	if err != nil {
+		return "", fmt.Errorf("account store: saving login token failed: %s", err)
+	}
+
+	return token, nil
+}
+
+func (rs *RedisStore) Redeem(token string) (Account, error) {
+	reply, err := takeScript.Run(rs.client, []string{REDIS_LOGIN_KEY_PREFIX + token})
+	if errors.Is(err, redis.ErrNil) {
+		return Account{}, ErrInvalidLoginToken
+	}
+	if err != nil {
+		return Account{}, fmt.Errorf("account store: redeeming login token failed: %s", err)
+	}
+
+	email, ok := reply.(string)
+	if !ok {
+		return Account{}, ErrInvalidLoginToken
+	}
+
+	progress, err := json.Marshal(session.Progress{})
+	if err != nil {
+		return Account{}, fmt.Errorf("account store: marshal progress failed: %s", err)
+	}
+
+	id := uuid.NewString()
+	reply, err = createScript.Run(
+		rs.client,
+		[]string{REDIS_EMAIL_KEY_PREFIX + email, REDIS_KEY_PREFIX + id},
+		id, email, rs.clock.Now().Format(time.RFC3339Nano), string(progress),
+	)
+	if err != nil {
+		return Account{}, fmt.Errorf("account store: creating account failed: %s", err)
+	}
+
+	id, _ = reply.(string)
+	a, ok, err := rs.get(id)
+	if err != nil {
+		return Account{}, err
+	}
+	if !ok {
+		return Account{}, fmt.Errorf("account store: account '%s' of '%s' is missing", id, email)
+	}
+
+	return a, nil
+}
+
+func (rs *RedisStore) Get(id string) (Account, bool) {
+	a, ok, err := rs.get(id)
+	if err != nil {
+		log.Printf("%s", err)
+		return Account{}, false
+	}
+
+	return a, ok
+}
+
+func (rs *RedisStore) get(id string) (Account, bool, error) {
+	reply, err := rs.client.Do("HMGET", REDIS_KEY_PREFIX+id, "email", "created", "progress")
+	if err != nil {
+		return Account{}, false, fmt.Errorf("account store: loading account failed: %s", err)
+	}
+
+	fields, _ := reply.([]any)
+	if len(fields) != 3 {
+		return Account{}, false, nil
+	}
+
+	email, okEmail := fields[0].(string)
+	created, okCreated := fields[1].(string)
+	progress, okProgress := fields[2].(string)
+	if !okEmail || !okCreated || !okProgress {
+		return Account{}, false, nil
+	}
+
+	a := Account{ID: id, Email: email}
+	a.CreatedAt, err = time.Parse(time.RFC3339Nano, created)
+	if err != nil {
+		return Account{}, false, fmt.Errorf("account store: invalid creation time of account '%s': %s", id, err)
+	}
+
+	err = json.Unmarshal([]byte(progress), &a.Progress)
+	if err != nil {
+		return Account{}, false, fmt.Errorf("account store: unmarshal progress of account '%s' failed: %s", id, err)
+	}
+
+	return a, true, nil
+}
+
+// Delete removes the account and all its data, sessions claimed by it become
+// anonymous on their next request.
+func (rs *RedisStore) Delete(id string) {
+	a, ok := rs.Get(id)
+	if !ok {
+		return
+	}
+
+	_, err := rs.client.Do("DEL", REDIS_KEY_PREFIX+id, REDIS_EMAIL_KEY_PREFIX+a.Email)
+	if err != nil {
+		log.Printf("account store: deleting account failed: %s", err)
+	}
+}
+
+func (rs *RedisStore) LoadProgress(accountID string) (session.Progress, bool, error) {
+	a, ok, err := rs.get(accountID)
+	return a.Progress, ok, err
+}
+
+// SaveProgress ignores unknown accounts, a deleted account is not brought
+// back by a device that did not notice yet.
+func (rs *RedisStore) SaveProgress(accountID string, p session.Progress) {
+	progress, err := json.Marshal(p)
+	if err != nil {
+		log.Printf("account store: marshal progress failed: %s", err)
+		return
+	}
+
+	_, err = saveProgressScript.Run(rs.client, []string{REDIS_KEY_PREFIX + accountID}, string(progress))
+	if err != nil {
+		log.Printf("account store: saving progress failed: %s", err)
+	}
+}
@@ -0,0 +1,79 @@
+package account
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/pandorasNox/lettr/pkg/clock"
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/redis"
+	"github.com/pandorasNox/lettr/pkg/session"
+)
+
+func TestRedisStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c := clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	// every instance has its own store and connections
+	instance := func() *RedisStore {
+		client := redis.NewClient(mr.Addr())
+		t.Cleanup(func() { client.Close() })
+
+		return NewRedisStore(c, client)
+	}
+	first, second := instance(), instance()
+
+	token, err := first.RequestLogin("player@example.com")
+	if err != nil {
+		t.Fatalf("RequestLogin() error = %s", err)
+	}
+	a, err := second.Redeem(token)
+	if err != nil {
+		t.Fatalf("Redeem() on another instance error = %s", err)
+	}
+	if a.Email != "player@example.com" || !a.CreatedAt.Equal(c.Now()) {
+		t.Errorf("Redeem() = %+v, want a new account of the email", a)
+	}
+	if _, err := first.Redeem(token); !errors.Is(err, ErrInvalidLoginToken) {
+		t.Errorf("Redeem() twice error = %v, want %v", err, ErrInvalidLoginToken)
+	}
+
+	token, _ = first.RequestLogin("PLAYER@example.com")
+	if again, _ := first.Redeem(token); again.ID != a.ID {
+		t.Errorf("second login created account %s, want %s", again.ID, a.ID)
+	}
+
+	token, _ = first.RequestLogin("player@example.com")
+	mr.FastForward(LOGIN_TOKEN_LIFETIME + time.Second)
+	if _, err := first.Redeem(token); !errors.Is(err, ErrInvalidLoginToken) {
+		t.Errorf("Redeem() expired error = %v, want %v", err, ErrInvalidLoginToken)
+	}
+
+	first.SaveProgress(a.ID, session.Progress{PastWords: []puzzle.Word{{'c', 'r', 'i', 'e', 'd'}}, SurvivalBest: 50})
+	p, ok, err := second.LoadProgress(a.ID)
+	if err != nil || !ok || p.SurvivalBest != 50 || len(p.PastWords) != 1 || p.PastWords[0].String() != "cried" {
+		t.Errorf("LoadProgress() = %+v, %t, %v, want the saved progress", p, ok, err)
+	}
+
+	second.Delete(a.ID)
+	first.SaveProgress(a.ID, session.Progress{SurvivalBest: 60})
+	if _, ok, err := first.LoadProgress(a.ID); ok || err != nil {
+		t.Errorf("LoadProgress() = %t, %v after deleting, want the account gone", ok, err)
+	}
+
+	token, _ = first.RequestLogin("player@example.com")
+	if fresh, _ := first.Redeem(token); fresh.ID == a.ID || fresh.Progress.SurvivalBest != 0 {
+		t.Errorf("Redeem() after deleting = %+v, want a new account", fresh)
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		s := NewRedisStore(c, redis.NewClient(mr.Addr()))
+		mr.Close()
+
+		if _, _, err := s.LoadProgress(a.ID); err == nil {
+			t.Errorf("LoadProgress() without server succeeded")
+		}
+	})
+}
@@ -0,0 +1,53 @@
+// Package bot tells crawlers, link previews and uptime probes apart from
+// players, so they can be served without a session.
+package bot
+
+import "strings"
+
+// markers are lower case fragments of user agents of well known bots and
+// http libraries, players' browsers contain none of them.
+var markers = []string{
+	"bot",
+	"crawl",
+	"spider",
+	"slurp",
+	"preview",
+	"facebookexternalhit",
+	"embedly",
+	"whatsapp",
+	"telegram",
+	"discord",
+	"skype",
+	"curl/",
+	"wget/",
+	"httpie/",
+	"python-requests",
+	"python-urllib",
+	"go-http-client",
+	"okhttp",
+	"axios/",
+	"node-fetch",
+	"uptime",
+	"monitor",
+	"pingdom",
+	"statuscake",
+	"headlesschrome",
+	"lighthouse",
+}
+
+// IsBot reports whether userAgent belongs to a bot, requests without user
+// agent count as bots as every browser sends one.
+func IsBot(userAgent string) bool {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		return true
+	}
+
+	for _, m := range markers {
+		if strings.Contains(ua, m) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,270 @@
+// Package challenge lets a player pick a solution word for somebody else.
+// The word travels inside an encrypted token, so it never shows up in a URL
+// in clear text, and the results of everyone playing it are kept in memory
+// for the challenger.
+package challenge
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/clock"
+	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/random"
+)
+
+const (
+	// CHALLENGE_TTL is how long a challenge collects results, its link
+	// stays playable afterwards.
+	CHALLENGE_TTL = 7 * 24 * time.Hour
+	// MAX_CHALLENGES_PER_CHALLENGER are kept per session, creating another
+	// one drops the oldest.
+	MAX_CHALLENGES_PER_CHALLENGER = 20
+)
+
+var ErrInvalidToken = errors.New("invalid challenge token")
+
+type Result struct {
+	PlayerID   string
+	Number     int
+	Puzzle     puzzle.Puzzle
+	FinishedAt time.Time
+}
+
+type Challenge struct {
+	ID           string
+	Token        string
+	ChallengerID string
+	Language     language.Language
+	Word         puzzle.Word
+	CreatedAt    time.Time
+	Results      []Result
+}
+
+// payload is what gets sealed into a token.
+type payload struct {
+	ID       string            `json:"i"`
+	Language language.Language `json:"l"`
+	Word     string            `json:"w"`
+}
+
+type Store struct {
+	mutex      sync.Mutex
+	challenges map[string]*Challenge
+	aead       cipher.AEAD
+	clock      clock.Clock
+}
+
+// NewStore returns a Store sealing tokens with a key derived from secret.
+// Tokens stay valid across restarts as long as the secret does not change.
+func NewStore(c clock.Clock, secret string) (*Store, error) {
+	key := sha256.Sum256([]byte(secret))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("challenge store: creating cipher failed: %s", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("challenge store: creating gcm failed: %s", err)
+	}
+
+	return &Store{challenges: map[string]*Challenge{}, aead: aead, clock: c}, nil
+}
+
+// MustNewStore is like NewStore but panics on error.
+func MustNewStore(c clock.Clock, secret string) *Store {
+	s, err := NewStore(c, secret)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// Create registers a challenge for word w and returns it including its
+// token. The caller is responsible for checking that w is a valid word.
+func (s *Store) Create(challengerID string, l language.Language, w puzzle.Word) (Challenge, error) {
+	id, err := random.SecureString(16)
+	if err != nil {
+		return Challenge{}, fmt.Errorf("challenge store: creating id failed: %s", err)
+	}
+
+	token, err := s.seal(payload{ID: id, Language: l, Word: w.String()})
+	if err != nil {
+		return Challenge{}, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.removeExpired()
+	s.limit(challengerID, MAX_CHALLENGES_PER_CHALLENGER-1)
+
+	c := &Challenge{
+		ID:           id,
+		Token:        token,
+		ChallengerID: challengerID,
+		Language:     l,
+		Word:         w,
+		CreatedAt:    s.clock.Now(),
+		Results:      []Result{},
+	}
+	s.challenges[id] = c
+
+	return c.clone(), nil
+}
+
+// Open decrypts the token. Challenges unknown to the store (e.g. created
+// before a restart) can still be played, they just don't collect results.
+func (s *Store) Open(token string) (Challenge, error) {
+	p, err := s.open(token)
+	if err != nil {
+		return Challenge{}, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if c, ok := s.challenges[p.ID]; ok {
+		return c.clone(), nil
+	}
+
+	w := puzzle.Word{}
+	copy(w[:], []rune(p.Word))
+
+	return Challenge{ID: p.ID, Token: token, Language: p.Language, Word: w, Results: []Result{}}, nil
+}
+
+// AddResult records the finished puzzle of a player, only the first result
+// of every player counts.
+func (s *Store) AddResult(id string, playerID string, p puzzle.Puzzle) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	c, ok := s.challenges[id]
+	if !ok {
+		return fmt.Errorf("challenge store: unknown challenge '%s'", id)
+	}
+
+	if slices.ContainsFunc(c.Results, func(r Result) bool { return r.PlayerID == playerID }) {
+		return nil
+	}
+
+	c.Results = append(c.Results, Result{PlayerID: playerID, Number: len(c.Results) + 1, Puzzle: p, FinishedAt: s.clock.Now()})
+
+	return nil
+}
+
+// ByChallenger returns all challenges created by the given session, newest
+// first.
+func (s *Store) ByChallenger(challengerID string) []Challenge {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	cs := []Challenge{}
+	for _, c := range s.challenges {
+		if c.ChallengerID == challengerID {
+			cs = append(cs, c.clone())
+		}
+	}
+
+	slices.SortFunc(cs, func(a, b Challenge) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return cs
+}
+
+// RemoveExpired drops the challenges older than CHALLENGE_TTL.
+func (s *Store) RemoveExpired() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.removeExpired()
+}
+
+// removeExpired is RemoveExpired for callers holding the mutex.
+func (s *Store) removeExpired() {
+	now := s.clock.Now()
+	for id, c := range s.challenges {
+		if now.Sub(c.CreatedAt) > CHALLENGE_TTL {
+			delete(s.challenges, id)
+		}
+	}
+}
+
+// limit drops the oldest challenges of the challenger until at most max are
+// left. Callers must hold the mutex.
+func (s *Store) limit(challengerID string, max int) {
+	own := []*Challenge{}
+	for _, c := range s.challenges {
+		if c.ChallengerID == challengerID {
+			own = append(own, c)
+		}
+	}
+	if len(own) <= max {
+		return
+	}
+
+	slices.SortFunc(own, func(a, b *Challenge) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	for _, c := range own[:len(own)-max] {
+		delete(s.challenges, c.ID)
+	}
+}
+
+func (s *Store) seal(p payload) (string, error) {
+	plain, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("challenge store: marshal payload failed: %s", err)
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	_, err = crand.Read(nonce)
+	if err != nil {
+		return "", fmt.Errorf("challenge store: creating nonce failed: %s", err)
+	}
+
+	sealed := s.aead.Seal(nonce, nonce, plain, nil)
+
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+func (s *Store) open(token string) (payload, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(sealed) < s.aead.NonceSize() {
+		return payload{}, ErrInvalidToken
+	}
+
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	plain, err := s.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return payload{}, ErrInvalidToken
+	}
+
+	p := payload{}
+	err = json.Unmarshal(plain, &p)
+	if err != nil || len([]rune(p.Word)) != len(puzzle.Word{}) {
+		return payload{}, ErrInvalidToken
+	}
+
+	return p, nil
+}
+
+func (c Challenge) clone() Challenge {
+	c.Results = slices.Clone(c.Results)
+	return c
+}
@@ -0,0 +1,146 @@
+package challenge
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/clock"
+	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+)
+
+var baeren = puzzle.Word{'b', 'ä', 'r', 'e', 'n'}
+
+func newTestStore(t *testing.T, secret string) *Store {
+	t.Helper()
+
+	s, err := NewStore(clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)), secret)
+	if err != nil {
+		t.Fatalf("NewStore() error = %s", err)
+	}
+
+	return s
+}
+
+func TestStore_token(t *testing.T) {
+	s := newTestStore(t, "secret")
+
+	c, err := s.Create("challenger", language.LANG_DE, baeren)
+	if err != nil {
+		t.Fatalf("Create() error = %s", err)
+	}
+
+	for _, fragment := range []string{"bären", "baren", "b%C3%A4ren"} {
+		if strings.Contains(strings.ToLower(c.Token), fragment) {
+			t.Errorf("token %q contains the word in clear text", c.Token)
+		}
+	}
+
+	opened, err := s.Open(c.Token)
+	if err != nil {
+		t.Fatalf("Open() error = %s", err)
+	}
+	if opened.ID != c.ID || opened.Word != baeren || opened.Language != language.LANG_DE {
+		t.Errorf("Open() = %+v, want %+v", opened, c)
+	}
+
+	t.Run("tampered token", func(t *testing.T) {
+		tampered := []byte(c.Token)
+		tampered[len(tampered)/2] ^= 1
+		if _, err := s.Open(string(tampered)); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("Open() error = %v, want %v", err, ErrInvalidToken)
+		}
+	})
+
+	t.Run("other secret", func(t *testing.T) {
+		if _, err := newTestStore(t, "other").Open(c.Token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("Open() error = %v, want %v", err, ErrInvalidToken)
+		}
+	})
+
+	t.Run("same secret after restart", func(t *testing.T) {
+		opened, err := newTestStore(t, "secret").Open(c.Token)
+		if err != nil {
+			t.Fatalf("Open() error = %s", err)
+		}
+		if opened.Word != baeren {
+			t.Errorf("Open() word = %s, want %s", opened.Word, baeren)
+		}
+	})
+
+	t.Run("garbage", func(t *testing.T) {
+		if _, err := s.Open("not-a-token"); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("Open() error = %v, want %v", err, ErrInvalidToken)
+		}
+	})
+}
+
+func TestStore_results(t *testing.T) {
+	s := newTestStore(t, "secret")
+
+	c, _ := s.Create("challenger", language.LANG_DE, baeren)
+	solved := puzzle.Puzzle{Guesses: [6]puzzle.WordGuess{puzzle.EvaluateGuessedWord(baeren, baeren)}}
+
+	if err := s.AddResult(c.ID, "recipient", solved); err != nil {
+		t.Fatalf("AddResult() error = %s", err)
+	}
+	if err := s.AddResult(c.ID, "recipient", puzzle.Puzzle{}); err != nil {
+		t.Fatalf("AddResult() error = %s", err)
+	}
+	if err := s.AddResult("unknown", "recipient", solved); err == nil {
+		t.Errorf("AddResult() expected error for unknown challenge")
+	}
+
+	cs := s.ByChallenger("challenger")
+	if len(cs) != 1 || len(cs[0].Results) != 1 {
+		t.Fatalf("ByChallenger() = %+v, want one challenge with one result", cs)
+	}
+	if !cs[0].Results[0].Puzzle.IsSolved() || cs[0].Results[0].Number != 1 {
+		t.Errorf("ByChallenger() result is not the first one recorded")
+	}
+
+	if cs := s.ByChallenger("recipient"); len(cs) != 0 {
+		t.Errorf("ByChallenger() = %+v, want none", cs)
+	}
+}
+
+func TestStore_limits(t *testing.T) {
+	c := clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	s, err := NewStore(c, "secret")
+	if err != nil {
+		t.Fatalf("NewStore() error = %s", err)
+	}
+
+	old, _ := s.Create("other", language.LANG_DE, baeren)
+
+	first, _ := s.Create("challenger", language.LANG_DE, baeren)
+	for range MAX_CHALLENGES_PER_CHALLENGER {
+		c.Advance(time.Minute)
+		s.Create("challenger", language.LANG_DE, baeren)
+	}
+
+	cs := s.ByChallenger("challenger")
+	if len(cs) != MAX_CHALLENGES_PER_CHALLENGER {
+		t.Fatalf("ByChallenger() = %d challenges, want %d", len(cs), MAX_CHALLENGES_PER_CHALLENGER)
+	}
+	if cs[len(cs)-1].ID == first.ID {
+		t.Errorf("ByChallenger() kept the oldest challenge above the limit")
+	}
+	if len(s.ByChallenger("other")) != 1 {
+		t.Errorf("ByChallenger() of another session lost its challenge to the limit")
+	}
+
+	c.Advance(CHALLENGE_TTL)
+	s.RemoveExpired()
+	if err := s.AddResult(old.ID, "player", puzzle.Puzzle{}); err == nil {
+		t.Errorf("AddResult() to an expired challenge succeeded")
+	}
+	if len(s.ByChallenger("challenger")) == 0 {
+		t.Errorf("RemoveExpired() dropped challenges younger than %s", CHALLENGE_TTL)
+	}
+	if _, err := s.Open(old.Token); err != nil {
+		t.Errorf("Open() of an expired challenge error = %s, want it still playable", err)
+	}
+}
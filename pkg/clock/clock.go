@@ -0,0 +1,98 @@
+package clock
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Clock is the source of the current time for everything that expires or
+// gets timestamped, so it can be replaced by a Fake in tests.
+type Clock interface {
+	Now() time.Time
+	// AfterFunc calls f once d passed, f must not block the caller.
+	AfterFunc(d time.Duration, f func())
+}
+
+// Real is the Clock backed by time.Now.
+type Real struct{}
+
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+func (Real) AfterFunc(d time.Duration, f func()) {
+	time.AfterFunc(d, f)
+}
+
+// Fake is a Clock that only moves when told to.
+type Fake struct {
+	mutex  sync.Mutex
+	now    time.Time
+	timers []timer
+}
+
+type timer struct {
+	at time.Time
+	f  func()
+}
+
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.now
+}
+
+// AfterFunc calls fn once the fake time got moved past d from now, on the
+// goroutine moving it.
+func (f *Fake) AfterFunc(d time.Duration, fn func()) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.timers = append(f.timers, timer{at: f.now.Add(d), f: fn})
+}
+
+func (f *Fake) Advance(d time.Duration) {
+	f.mutex.Lock()
+	f.now = f.now.Add(d)
+	f.mutex.Unlock()
+
+	f.fire()
+}
+
+func (f *Fake) Set(now time.Time) {
+	f.mutex.Lock()
+	f.now = now
+	f.mutex.Unlock()
+
+	f.fire()
+}
+
+// fire calls the due timers in order, without holding the mutex so they may
+// use the clock.
+func (f *Fake) fire() {
+	f.mutex.Lock()
+	due := []timer{}
+	pending := []timer{}
+	for _, t := range f.timers {
+		if t.at.After(f.now) {
+			pending = append(pending, t)
+		} else {
+			due = append(due, t)
+		}
+	}
+	f.timers = pending
+	f.mutex.Unlock()
+
+	slices.SortStableFunc(due, func(a, b timer) int {
+		return a.at.Compare(b.at)
+	})
+	for _, t := range due {
+		t.f()
+	}
+}
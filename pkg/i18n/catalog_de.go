@@ -0,0 +1,186 @@
+package i18n
+
+var catalogDe = map[string]string{
+	"game.status.solved":   "GELÖST",
+	"game.status.loose":    "VERLOREN",
+	"game.status.unsolved": "ungelöst",
+	"game.status.timeUp":   "ZEIT ABGELAUFEN",
+	"game.new":             "Neues Spiel",
+	"game.help":            "?",
+	"game.easy":            "leicht",
+	"game.easy.title":      "Akzente und Umlaute ignorieren, gilt ab dem nächsten Spiel",
+	"game.race":            "Rennen",
+	"game.challenge":       "Herausfordern",
+	"game.boards.single":   "1 Wort",
+	"game.boards":          "%d Wörter",
+	"game.boards.title":    "Anzahl der gleichzeitig zu lösenden Wörter, gilt ab dem nächsten Spiel",
+	"game.survival":        "Überleben",
+	"game.survival.title":  "jedes gelöste Wort führt direkt zum nächsten, die erste Niederlage beendet den Lauf",
+	"game.account":         "Konto",
+	"game.history":         "Verlauf",
+	"game.speed":           "Zeit",
+	"game.speed.title":     "gegen die Uhr spielen, gilt ab dem nächsten Spiel",
+
+	"game.collection":       "Alltagswörter",
+	"game.collection.title": "die Lösungen aus einer Themenliste wählen, gilt ab dem nächsten Spiel, geraten werden darf weiterhin jedes Wort",
+
+	"game.difficulty":        "jede Schwierigkeit",
+	"game.difficulty.title":  "wie schwer die Lösungen zu finden sind, gilt ab dem nächsten Spiel",
+	"game.difficulty.easy":   "leichte Wörter",
+	"game.difficulty.medium": "mittlere Wörter",
+	"game.difficulty.hard":   "schwere Wörter",
+
+	"collection.animals": "Tiere",
+	"collection.food":    "Essen",
+	"collection.tech":    "Technik-Jargon",
+
+	"tag.animals":       "Tiere",
+	"tag.food":          "Essen",
+	"tag.tech":          "Technik",
+	"tag.kids-friendly": "kinderfreundlich",
+
+	"boards.attempts": "noch %d Versuche",
+
+	"survival.score":       "Punkte: %d (%d Wörter)",
+	"survival.best":        "Bestwert: %d",
+	"survival.over":        "Lauf beendet! Endstand: %d",
+	"survival.leaderboard": "Bestenliste",
+
+	"leaderboard.title":         "Bestenlisten",
+	"leaderboard.entry":         "%d Punkte, %d Wörter",
+	"leaderboard.you":           "(du)",
+	"leaderboard.empty":         "Noch niemand hier.",
+	"leaderboard.survival":      "Die besten Survival-Läufe",
+	"leaderboard.streak":        "Die längsten Siegesserien",
+	"leaderboard.streak.entry":  "%d Siege in Folge",
+	"leaderboard.daily":         "Heute die meisten Rätsel gelöst",
+	"leaderboard.daily.entry":   "%d gelöst",
+	"leaderboard.nickname":      "Spitzname",
+	"leaderboard.nickname.save": "Speichern",
+	"leaderboard.anonymous":     "anonym",
+
+	"account.title":          "Konto",
+	"account.intro":          "Melde dich mit deiner E-Mail an, um deinen Fortschritt zu behalten und auf anderen Geräten weiterzuspielen. Kein Passwort nötig, wir schicken dir einen Link.",
+	"account.email":          "E-Mail",
+	"account.sendLink":       "Anmeldelink senden",
+	"account.loggedInAs":     "Angemeldet als %s",
+	"account.export":         "Daten exportieren",
+	"account.logout":         "Abmelden",
+	"account.delete":         "Konto löschen",
+	"account.delete.confirm": "Konto und alle Daten löschen?",
+	"account.confirm":        "Bei lettr anmelden",
+	"account.linkInvalid":    "Dieser Anmeldelink ist ungültig oder abgelaufen.",
+	"account.mail.subject":   "Dein lettr Anmeldelink",
+	"account.mail.body":      "Öffne diesen Link, um dich anzumelden, er ist 15 Minuten gültig:\n%s",
+
+	"history.title":     "beendete Spiele",
+	"history.export":    "Als JSON exportieren",
+	"history.empty":     "Noch keine beendeten Spiele.",
+	"history.won":       "gelöst in %d",
+	"history.lost":      "nicht gelöst",
+	"history.abandoned": "aufgegeben",
+	"history.replay":    "Zeile %d von %d",
+	"history.hints":     "Tipps:",
+	"history.first":     "Anfang",
+	"history.prev":      "Zurück",
+	"history.next":      "Weiter",
+	"history.last":      "Ende",
+
+	"speed.remaining":  "Verbleibende Zeit:",
+	"speed.solveTime":  "Gelöst in %s",
+	"speed.guessTimes": "Zeit pro Versuch:",
+
+	"keyboard.enter":  "Enter",
+	"keyboard.layout": "Tastaturlayout",
+	"keyboard.delete": "Löschen",
+
+	"footer.slogan":     "lettr macht Wörter!",
+	"footer.suggest":    "Wort vorschlagen",
+	"footer.madeWith":   "Gemacht mit HTMX",
+	"footer.revision":   "Rev:",
+	"footer.imprint":    "Impressum",
+	"nav.back":          "< Zurück",
+	"help.title":        "Hilfe",
+	"help.duplicates":   "Zeigen, ob das Wort doppelte Buchstaben hat",
+	"help.hasDuplicate": "doppelte Buchstaben?:",
+	"help.yes":          "ja",
+	"help.no":           "nein",
+	"help.revealLetter": "Nächsten Buchstaben aufdecken",
+	"help.hint":         "Hinweis:",
+	"help.getLetter":    "Buchstaben holen",
+	"help.showSolution": "Lösung zeigen",
+	"help.solution":     "Lösung:",
+	"help.pastWords":    "Bisherige Wörter",
+
+	"suggest.title":              "neuer Vorschlag",
+	"suggest.pickLanguage":       "Sprache wählen:",
+	"suggest.word":               "Wort vorschlagen:",
+	"suggest.wordPlaceholder":    "MAX 5 Buchstaben erlaubt",
+	"suggest.actionAdd":          "Wort hinzufügen",
+	"suggest.actionRemove":       "Wort entfernen",
+	"suggest.message":            "Deine Nachricht:",
+	"suggest.messagePlaceholder": "Hinterlasse einen Kommentar... (Begründung, Bedeutung des Wortes, weitere Infos/Anmerkungen... MAX 280 Zeichen erlaubt)",
+	"suggest.send":               "Vorschlag senden",
+
+	"challenge.title":     "Freunde herausfordern",
+	"challenge.word":      "Dein Wort:",
+	"challenge.create":    "Herausforderung erstellen",
+	"challenge.share":     "Teile diesen Link:",
+	"challenge.list":      "Deine Herausforderungen",
+	"challenge.noResults": "Noch hat niemand gespielt.",
+	"challenge.player":    "Spieler %d",
+
+	"room.title":     "Rennen gegen Freunde",
+	"room.create":    "Raum erstellen",
+	"room.timeLimit": "Zeitlimit:",
+	"room.minutes":   "%d Min.",
+	"room.code":      "Raumcode:",
+	"room.join":      "Raum beitreten",
+	"room.name":      "Raum %s",
+	"room.waiting":   "Warte auf den Start durch den Gastgeber. Teile den Raumcode mit deinen Freunden!",
+	"room.start":     "Rennen starten",
+	"room.running":   "Das Rennen läuft!",
+	"room.finished":  "Das Rennen ist vorbei",
+	"room.done":      "Fertig, warte auf die anderen...",
+	"room.spectator": "Das Rennen hat ohne dich begonnen.",
+	"room.player":    "Spieler %d",
+	"room.you":       "(du)",
+	"room.results":   "Ergebnis",
+	"room.solution":  "Das Wort war: %s",
+	"room.rows":      "%d Zeilen",
+	"room.notSolved": "nicht gelöst",
+
+	"msg.formParseFailed":       "Formulardaten konnten nicht gelesen werden",
+	"msg.resynced":              "Dein Spiel war nicht aktuell und wurde neu geladen.",
+	"msg.tooManySessions":       "Zu viele neue Spiele aus deinem Netzwerk, bitte versuche es später noch einmal.",
+	"msg.notInWordList":         "Wort nicht in der Wortliste",
+	"msg.noMoreHints":           "Keine weiteren Hinweise verfügbar",
+	"msg.gameOver":              "Dieses Spiel ist bereits vorbei.",
+	"msg.suggestionFailed":      "Vorschlag konnte nicht gesendet werden.",
+	"msg.suggestionSent":        "Vorschlag gesendet, danke!",
+	"msg.validation.word":       "Validierung fehlgeschlagen: Wort ist zu lang, zu kurz oder enthält unerlaubte Zeichen",
+	"msg.validation.message":    "Validierung fehlgeschlagen: Nachricht enthält ungültige Daten",
+	"msg.validation.action":     "Validierung fehlgeschlagen: Aktion ungültig",
+	"msg.validation.language":   "Validierung fehlgeschlagen: Sprache ungültig",
+	"msg.validation.nickname":   "Validierung fehlgeschlagen: Spitzname braucht 3 bis 20 Buchstaben, Ziffern, Leerzeichen, - oder _",
+	"msg.validation.email":      "Validierung fehlgeschlagen: E-Mail-Adresse ungültig",
+	"msg.account.failed":        "Anmeldelink konnte nicht gesendet werden.",
+	"msg.account.linkSent":      "Anmeldelink gesendet, schau in dein Postfach.",
+	"msg.account.notLoggedIn":   "Du bist nicht angemeldet.",
+	"msg.account.deleted":       "Dein Konto wurde gelöscht.",
+	"msg.history.unknownGame":   "Dieses Spiel ist nicht in deinem Verlauf.",
+	"msg.challenge.word":        "Gib ein Wort mit fünf Buchstaben ein",
+	"msg.challenge.failed":      "Herausforderung konnte nicht erstellt werden",
+	"msg.survival.solved":       "Gelöst! +%d Punkte",
+	"msg.survival.personalBest": "Neuer persönlicher Bestwert: %d",
+	"msg.survival.rank":         "Dein Lauf hat Platz %d der Bestenliste erreicht",
+	"msg.room.notFound":         "Raum nicht gefunden",
+	"msg.room.full":             "Raum ist voll",
+	"msg.room.alreadyStarted":   "Das Rennen hat schon begonnen",
+	"msg.room.notRunning":       "Das Rennen läuft nicht",
+	"msg.room.notHost":          "Nur der Gastgeber kann das Rennen starten",
+	"msg.room.notAPlayer":       "Du nimmst an diesem Rennen nicht teil",
+	"msg.room.finished":         "Du bist schon fertig",
+	"msg.room.timeLimit":        "Ungültiges Zeitlimit",
+	"msg.room.failed":           "Raum konnte nicht erstellt werden",
+}
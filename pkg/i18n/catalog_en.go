@@ -0,0 +1,186 @@
+package i18n
+
+var catalogEn = map[string]string{
+	"game.status.solved":   "SOLVED",
+	"game.status.loose":    "YOU LOOSE",
+	"game.status.unsolved": "unsolved",
+	"game.status.timeUp":   "TIME'S UP",
+	"game.new":             "New Game",
+	"game.help":            "?",
+	"game.easy":            "easy",
+	"game.easy.title":      "ignore accents and umlauts, applies to the next game",
+	"game.race":            "Race",
+	"game.challenge":       "Challenge",
+	"game.boards.single":   "1 word",
+	"game.boards":          "%d words",
+	"game.boards.title":    "number of words to solve at once, applies to the next game",
+	"game.survival":        "survival",
+	"game.survival.title":  "every solved word leads straight to the next, the first loss ends the run",
+	"game.account":         "Account",
+	"game.history":         "History",
+	"game.speed":           "speed",
+	"game.speed.title":     "play against the clock, applies to the next game",
+
+	"game.collection":       "everyday words",
+	"game.collection.title": "pick the solutions from a themed word list, applies to the next game, guesses may still be any word",
+
+	"game.difficulty":        "any difficulty",
+	"game.difficulty.title":  "how hard the solutions are to find, applies to the next game",
+	"game.difficulty.easy":   "easy words",
+	"game.difficulty.medium": "medium words",
+	"game.difficulty.hard":   "hard words",
+
+	"collection.animals": "Animals",
+	"collection.food":    "Food",
+	"collection.tech":    "Tech jargon",
+
+	"tag.animals":       "animals",
+	"tag.food":          "food",
+	"tag.tech":          "tech",
+	"tag.kids-friendly": "kids friendly",
+
+	"boards.attempts": "%d guesses left",
+
+	"survival.score":       "Score: %d (%d words)",
+	"survival.best":        "Best: %d",
+	"survival.over":        "Run over! Final score: %d",
+	"survival.leaderboard": "Leaderboard",
+
+	"leaderboard.title":         "leaderboards",
+	"leaderboard.entry":         "%d points, %d words",
+	"leaderboard.you":           "(you)",
+	"leaderboard.empty":         "Nobody here yet.",
+	"leaderboard.survival":      "Top survival runs",
+	"leaderboard.streak":        "Longest win streaks",
+	"leaderboard.streak.entry":  "%d wins in a row",
+	"leaderboard.daily":         "Most puzzles solved today",
+	"leaderboard.daily.entry":   "%d solved",
+	"leaderboard.nickname":      "Nickname",
+	"leaderboard.nickname.save": "Save",
+	"leaderboard.anonymous":     "anonymous",
+
+	"account.title":          "account",
+	"account.intro":          "Sign in with your email to keep your progress and continue on other devices. No password needed, we send you a link.",
+	"account.email":          "Email",
+	"account.sendLink":       "Send login link",
+	"account.loggedInAs":     "Signed in as %s",
+	"account.export":         "Export data",
+	"account.logout":         "Sign out",
+	"account.delete":         "Delete account",
+	"account.delete.confirm": "Delete your account and all its data?",
+	"account.confirm":        "Sign in to lettr",
+	"account.linkInvalid":    "This login link is invalid or expired.",
+	"account.mail.subject":   "Your lettr login link",
+	"account.mail.body":      "Open this link to sign in, it is valid for 15 minutes:\n%s",
+
+	"history.title":     "finished games",
+	"history.export":    "Export as JSON",
+	"history.empty":     "No finished games yet.",
+	"history.won":       "solved in %d",
+	"history.lost":      "not solved",
+	"history.abandoned": "given up",
+	"history.replay":    "Row %d of %d",
+	"history.hints":     "Hints:",
+	"history.first":     "First",
+	"history.prev":      "Back",
+	"history.next":      "Next",
+	"history.last":      "Last",
+
+	"speed.remaining":  "Time left:",
+	"speed.solveTime":  "Solved in %s",
+	"speed.guessTimes": "Time per guess:",
+
+	"keyboard.enter":  "Enter",
+	"keyboard.layout": "Keyboard layout",
+	"keyboard.delete": "Delete",
+
+	"footer.slogan":     "lettr's making words!",
+	"footer.suggest":    "Suggest a word",
+	"footer.madeWith":   "Made with HTMX",
+	"footer.revision":   "Rev:",
+	"footer.imprint":    "Imprint",
+	"nav.back":          "< Back",
+	"help.title":        "help",
+	"help.duplicates":   "Show if word has duplicates",
+	"help.hasDuplicate": "has duplicates?:",
+	"help.yes":          "yes",
+	"help.no":           "no",
+	"help.revealLetter": "Reveal next letter",
+	"help.hint":         "hint:",
+	"help.getLetter":    "get a letter",
+	"help.showSolution": "Show solution",
+	"help.solution":     "solution:",
+	"help.pastWords":    "Past Words",
+
+	"suggest.title":              "new suggestion",
+	"suggest.pickLanguage":       "Pick language:",
+	"suggest.word":               "Suggest a word:",
+	"suggest.wordPlaceholder":    "MAX 5 letters allowed",
+	"suggest.actionAdd":          "add word",
+	"suggest.actionRemove":       "remove word",
+	"suggest.message":            "Your message:",
+	"suggest.messagePlaceholder": "Leave a comment... (reason why, meaning of the word, other related infos/remarks... MAX 280 characters allowed)",
+	"suggest.send":               "send suggestion",
+
+	"challenge.title":     "challenge a friend",
+	"challenge.word":      "Your word:",
+	"challenge.create":    "Create challenge",
+	"challenge.share":     "Share this link:",
+	"challenge.list":      "Your challenges",
+	"challenge.noResults": "Nobody played it yet.",
+	"challenge.player":    "Player %d",
+
+	"room.title":     "race a friend",
+	"room.create":    "Create room",
+	"room.timeLimit": "Time limit:",
+	"room.minutes":   "%d min",
+	"room.code":      "Room code:",
+	"room.join":      "Join room",
+	"room.name":      "Room %s",
+	"room.waiting":   "Waiting for the host to start the race. Share the room code with your friends!",
+	"room.start":     "Start race",
+	"room.running":   "Race is on!",
+	"room.finished":  "Race is over",
+	"room.done":      "Done, waiting for the others...",
+	"room.spectator": "The race started without you.",
+	"room.player":    "Player %d",
+	"room.you":       "(you)",
+	"room.results":   "Results",
+	"room.solution":  "The word was: %s",
+	"room.rows":      "%d rows",
+	"room.notSolved": "not solved",
+
+	"msg.formParseFailed":       "cannot parse form data",
+	"msg.resynced":              "Your game was out of sync and has been reloaded.",
+	"msg.tooManySessions":       "Too many new games from your network, please try again later.",
+	"msg.notInWordList":         "word not in word list",
+	"msg.noMoreHints":           "No more hints to provide",
+	"msg.gameOver":              "This game is already over.",
+	"msg.suggestionFailed":      "Could not send suggestion.",
+	"msg.suggestionSent":        "Suggestion send, thank you!",
+	"msg.validation.word":       "validation failed: word is either to long, to short or contains forbidden characters",
+	"msg.validation.message":    "validation failed: message contains invalid data",
+	"msg.validation.action":     "validation failed: action invalid",
+	"msg.validation.language":   "validation failed: language invalid",
+	"msg.validation.nickname":   "validation failed: nickname must have 3 to 20 letters, digits, spaces, - or _",
+	"msg.validation.email":      "validation failed: email address invalid",
+	"msg.account.failed":        "Could not send the login link.",
+	"msg.account.linkSent":      "Login link sent, check your inbox.",
+	"msg.account.notLoggedIn":   "You are not signed in.",
+	"msg.account.deleted":       "Your account was deleted.",
+	"msg.history.unknownGame":   "This game is not in your history.",
+	"msg.challenge.word":        "Enter a word with five letters",
+	"msg.challenge.failed":      "Challenge could not be created",
+	"msg.survival.solved":       "Solved! +%d points",
+	"msg.survival.personalBest": "New personal best: %d",
+	"msg.survival.rank":         "Your run made it to rank %d on the leaderboard",
+	"msg.room.notFound":         "Room not found",
+	"msg.room.full":             "Room is full",
+	"msg.room.alreadyStarted":   "Race already started",
+	"msg.room.notRunning":       "Race is not running",
+	"msg.room.notHost":          "Only the host can start the race",
+	"msg.room.notAPlayer":       "You are not part of this race",
+	"msg.room.finished":         "You already finished",
+	"msg.room.timeLimit":        "Invalid time limit",
+	"msg.room.failed":           "Room could not be created",
+}
@@ -0,0 +1,79 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pandorasNox/lettr/pkg/language"
+)
+
+const FALLBACK_LANGUAGE = language.LANG_EN
+
+// catalogs maps every supported language to its messages. Messages may
+// contain fmt verbs which are filled with the params passed to T.
+var catalogs = map[language.Language]map[string]string{
+	language.LANG_EN: catalogEn,
+	language.LANG_DE: catalogDe,
+}
+
+// T returns the message for key in language l. Missing translations fall back
+// to the english catalog and finally to the key itself.
+func T(l language.Language, key string, params ...any) string {
+	msg, ok := catalogs[l][key]
+	if !ok {
+		msg, ok = catalogs[FALLBACK_LANGUAGE][key]
+	}
+	if !ok {
+		msg = key
+	}
+
+	if len(params) == 0 {
+		return msg
+	}
+
+	return fmt.Sprintf(msg, params...)
+}
+
+// Negotiate picks the supported language with the highest quality from an
+// Accept-Language header value, e.g. "de-DE,de;q=0.9,en;q=0.8".
+func Negotiate(acceptLanguage string) language.Language {
+	type candidate struct {
+		lang    language.Language
+		quality float64
+	}
+
+	candidates := []candidate{}
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+
+		l, err := language.NewLang(base)
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			quality, err = strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+		}
+
+		if quality > 0 {
+			candidates = append(candidates, candidate{l, quality})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return FALLBACK_LANGUAGE
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+
+	return candidates[0].lang
+}
@@ -0,0 +1,64 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/pandorasNox/lettr/pkg/language"
+)
+
+func TestCatalogs_areComplete(t *testing.T) {
+	for l, catalog := range catalogs {
+		for key := range catalogs[FALLBACK_LANGUAGE] {
+			if _, ok := catalog[key]; !ok {
+				t.Errorf("catalog '%s' misses key '%s'", l, key)
+			}
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	tests := []struct {
+		name   string
+		lang   language.Language
+		key    string
+		params []any
+		want   string
+	}{
+		{"english", language.LANG_EN, "game.new", nil, "New Game"},
+		{"german", language.LANG_DE, "game.new", nil, "Neues Spiel"},
+		{"unknown language falls back to english", language.Language("xx"), "game.new", nil, "New Game"},
+		{"unknown key falls back to key", language.LANG_DE, "does.not.exist", nil, "does.not.exist"},
+		{"params are applied", language.LANG_EN, "%d of %s", []any{3, "x"}, "3 of x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := T(tt.lang, tt.key, tt.params...); got != tt.want {
+				t.Errorf("T() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		want           language.Language
+	}{
+		{"", language.LANG_EN},
+		{"de", language.LANG_DE},
+		{"de-DE,de;q=0.9,en;q=0.8", language.LANG_DE},
+		{"en-US,en;q=0.9,de;q=0.8", language.LANG_EN},
+		{"fr-FR,fr;q=0.9,de;q=0.5,en;q=0.4", language.LANG_DE},
+		{"fr-FR,en;q=0.2,de;q=0.7", language.LANG_DE},
+		{"de;q=0,en;q=0.1", language.LANG_EN},
+		{"fr,es", language.LANG_EN},
+		{"DE-at", language.LANG_DE},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			if got := Negotiate(tt.acceptLanguage); got != tt.want {
+				t.Errorf("Negotiate(%q) = %v, want %v", tt.acceptLanguage, got, tt.want)
+			}
+		})
+	}
+}
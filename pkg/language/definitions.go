@@ -0,0 +1,53 @@
+package language
+
+// To add a language, append its Definition here and ship its word lists in
+// the configs folder.
+var DefaultRegistry = MustNewRegistry(
+	Definition{
+		Code:           LANG_EN,
+		DisplayName:    "English (US)",
+		Alphabet:       []rune("abcdefghijklmnopqrstuvwxyz"),
+		KeyboardLayout: "qwerty",
+		WordLists: map[string][]string{
+			"wc_all": {
+				"configs/corpora-eng_news_2023_10K-export.txt",
+				"configs/en-en.words.v2.txt",
+			},
+			"wc_common": {
+				"configs/corpora-eng_news_2023_10K-export.txt",
+				"configs/github.com.ajeetdsouza.clidle.words.go.txt",
+			},
+			"wc_guess_only": {
+				"configs/valid-guesses.nyt.txt",
+			},
+			"animals": {"configs/theme-en-animals.txt"},
+			"food":    {"configs/theme-en-food.txt"},
+			"tech":    {"configs/theme-en-tech.txt"},
+		},
+	},
+	Definition{
+		Code:           LANG_DE,
+		DisplayName:    "Deutsch",
+		Alphabet:       []rune("abcdefghijklmnopqrstuvwxyzäöüß"),
+		KeyboardLayout: "qwertz",
+		ExtraKeys:      "äöüß",
+		// keyboards without umlauts and the Swiss spelling write them out
+		Transliteration: []Replacement{
+			{From: "ae", To: "ä"},
+			{From: "oe", To: "ö"},
+			{From: "ue", To: "ü"},
+			{From: "ss", To: "ß"},
+		},
+		WordLists: map[string][]string{
+			"wc_all": {
+				"configs/corpora-deu_news_2023_10K-export.txt",
+				"configs/de-de.words.v2.txt",
+			},
+			"wc_common": {
+				"configs/corpora-deu_news_2023_10K-export.txt",
+			},
+			"animals": {"configs/theme-de-tiere.txt"},
+			"food":    {"configs/theme-de-essen.txt"},
+		},
+	},
+)
@@ -0,0 +1,80 @@
+package language
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	KEY_ENTER  = "Enter"
+	KEY_DELETE = "Delete"
+)
+
+// KeyboardLayout describes the letter rows of an on-screen keyboard. Rows
+// hold lowercase letters only, the special keys are added when building the
+// grid.
+type KeyboardLayout struct {
+	Name        string
+	DisplayName string
+	Rows        []string
+}
+
+var KeyboardLayouts = []KeyboardLayout{
+	{Name: "qwerty", DisplayName: "QWERTY", Rows: []string{"qwertyuiop", "asdfghjkl", "zxcvbnm"}},
+	{Name: "qwertz", DisplayName: "QWERTZ", Rows: []string{"qwertzuiop", "asdfghjkl", "yxcvbnm"}},
+	{Name: "azerty", DisplayName: "AZERTY", Rows: []string{"azertyuiop", "qsdfghjklm", "wxcvbn"}},
+	{Name: "dvorak", DisplayName: "Dvorak", Rows: []string{"pyfgcrl", "aoeuidhtns", "qjkxbmwvz"}},
+}
+
+func LookupKeyboardLayout(name string) (KeyboardLayout, bool) {
+	i := slices.IndexFunc(KeyboardLayouts, func(kl KeyboardLayout) bool {
+		return kl.Name == name
+	})
+	if i == -1 {
+		return KeyboardLayout{}, false
+	}
+
+	return KeyboardLayouts[i], true
+}
+
+// Keyboard returns the key rows for the layout with the given name, falling
+// back to the default layout of the language. Delete closes the first row,
+// Enter the second one and the language specific extra keys form a last row.
+func (d Definition) Keyboard(layoutName string) [][]string {
+	layout, ok := LookupKeyboardLayout(layoutName)
+	if !ok {
+		layout, ok = LookupKeyboardLayout(d.KeyboardLayout)
+	}
+	if !ok {
+		layout = KeyboardLayouts[0]
+	}
+
+	grid := [][]string{}
+	for ri, row := range layout.Rows {
+		keys := upperKeys(row)
+
+		switch ri {
+		case 0:
+			keys = append(keys, KEY_DELETE)
+		case 1:
+			keys = append(keys, KEY_ENTER)
+		}
+
+		grid = append(grid, keys)
+	}
+
+	if d.ExtraKeys != "" {
+		grid = append(grid, upperKeys(d.ExtraKeys))
+	}
+
+	return grid
+}
+
+func upperKeys(letters string) []string {
+	keys := []string{}
+	for _, l := range letters {
+		keys = append(keys, strings.ToUpper(string(l)))
+	}
+
+	return keys
+}
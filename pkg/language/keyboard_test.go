@@ -0,0 +1,67 @@
+package language
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDefinition_Keyboard(t *testing.T) {
+	de, _ := DefaultRegistry.Lookup("de")
+	en, _ := DefaultRegistry.Lookup("en")
+
+	tests := []struct {
+		name       string
+		definition Definition
+		layoutName string
+		want       [][]string
+	}{
+		{
+			name:       "german defaults to qwertz with umlaut row",
+			definition: de,
+			layoutName: "",
+			want: [][]string{
+				{"Q", "W", "E", "R", "T", "Z", "U", "I", "O", "P", KEY_DELETE},
+				{"A", "S", "D", "F", "G", "H", "J", "K", "L", KEY_ENTER},
+				{"Y", "X", "C", "V", "B", "N", "M"},
+				{"Ä", "Ö", "Ü", "ß"},
+			},
+		},
+		{
+			name:       "english defaults to qwerty",
+			definition: en,
+			layoutName: "",
+			want: [][]string{
+				{"Q", "W", "E", "R", "T", "Y", "U", "I", "O", "P", KEY_DELETE},
+				{"A", "S", "D", "F", "G", "H", "J", "K", "L", KEY_ENTER},
+				{"Z", "X", "C", "V", "B", "N", "M"},
+			},
+		},
+		{
+			name:       "preference overrides default",
+			definition: en,
+			layoutName: "dvorak",
+			want: [][]string{
+				{"P", "Y", "F", "G", "C", "R", "L", KEY_DELETE},
+				{"A", "O", "E", "U", "I", "D", "H", "T", "N", "S", KEY_ENTER},
+				{"Q", "J", "K", "X", "B", "M", "W", "V", "Z"},
+			},
+		},
+		{
+			name:       "unknown preference falls back to default",
+			definition: en,
+			layoutName: "colemak",
+			want: [][]string{
+				{"Q", "W", "E", "R", "T", "Y", "U", "I", "O", "P", KEY_DELETE},
+				{"A", "S", "D", "F", "G", "H", "J", "K", "L", KEY_ENTER},
+				{"Z", "X", "C", "V", "B", "N", "M"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.definition.Keyboard(tt.layoutName); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Definition.Keyboard() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,10 +10,10 @@ const (
 )
 
 func NewLang(maybeLang string) (Language, error) {
-	switch Language(maybeLang) {
-	case LANG_EN, LANG_DE:
-		return Language(maybeLang), nil
-	default:
+	_, ok := DefaultRegistry.Lookup(Language(maybeLang))
+	if !ok {
 		return LANG_EN, fmt.Errorf("couldn't create new language from given value: '%s'", maybeLang)
 	}
+
+	return Language(maybeLang), nil
 }
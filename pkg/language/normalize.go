@@ -0,0 +1,83 @@
+package language
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalize brings s into the canonical form words are stored and compared
+// in: NFC composed, lowercased (so 'ẞ' becomes 'ß') and rewritten by the
+// normalization rules of the language.
+func (d Definition) Normalize(s string) string {
+	s = strings.ToLower(norm.NFC.String(s))
+	for _, r := range d.Normalization {
+		s = strings.ReplaceAll(s, norm.NFC.String(r.From), norm.NFC.String(r.To))
+	}
+
+	return s
+}
+
+// Fold normalizes s and additionally drops all diacritics after applying the
+// folding rules of the language, e.g. "Schön" becomes "schon". Folding keeps
+// the letter count, so folded words can be compared letter by letter.
+func (d Definition) Fold(s string) string {
+	s = d.Normalize(s)
+	for _, r := range d.Folding {
+		s = strings.ReplaceAll(s, r.From, r.To)
+	}
+
+	out := strings.Builder{}
+	for _, l := range s {
+		out.WriteRune(foldLetter(l))
+	}
+
+	return out.String()
+}
+
+// Transliterate normalizes s and additionally applies the transliteration
+// rules of the language, e.g. "Baeren" becomes "bären" in German. The
+// spellings also occur in real words (e.g. "ue" in "feuer"), so callers only
+// transliterate input which is no word as typed.
+func (d Definition) Transliterate(s string) string {
+	s = d.Normalize(s)
+	for _, r := range d.Transliteration {
+		s = strings.ReplaceAll(s, norm.NFC.String(r.From), norm.NFC.String(r.To))
+	}
+
+	return s
+}
+
+// foldLetter strips the combining marks of l, letters which do not decompose
+// into a base letter plus marks (e.g. 'ß') are returned unchanged.
+func foldLetter(l rune) rune {
+	decomposed := []rune(norm.NFD.String(string(l)))
+	for _, m := range decomposed[1:] {
+		if !unicode.Is(unicode.Mn, m) {
+			return l
+		}
+	}
+
+	return decomposed[0]
+}
+
+// Normalize normalizes s according to the definition of l in the
+// DefaultRegistry. Unknown languages only get NFC composed and lowercased.
+func Normalize(l Language, s string) string {
+	d, _ := DefaultRegistry.Lookup(l)
+	return d.Normalize(s)
+}
+
+// Fold folds s according to the definition of l in the DefaultRegistry.
+func Fold(l Language, s string) string {
+	d, _ := DefaultRegistry.Lookup(l)
+	return d.Fold(s)
+}
+
+// Transliterate transliterates s according to the definition of l in the
+// DefaultRegistry.
+func Transliterate(l Language, s string) string {
+	d, _ := DefaultRegistry.Lookup(l)
+	return d.Transliterate(s)
+}
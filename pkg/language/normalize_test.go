@@ -0,0 +1,89 @@
+package language
+
+import "testing"
+
+func TestDefinition_Normalize(t *testing.T) {
+	de, _ := DefaultRegistry.Lookup(LANG_DE)
+	transliterating := Definition{
+		Code:          "de",
+		Normalization: []Replacement{{From: "ae", To: "ä"}, {From: "oe", To: "ö"}, {From: "ue", To: "ü"}},
+	}
+
+	tests := []struct {
+		name       string
+		definition Definition
+		in         string
+		want       string
+	}{
+		{name: "precomposed umlaut", definition: de, in: "Bären", want: "bären"},
+		{name: "decomposed umlaut gets composed", definition: de, in: "Ba\u0308ren", want: "bären"},
+		{name: "uppercase sharp s", definition: de, in: "GRÜẞE", want: "grüße"},
+		{name: "several combining marks get composed", definition: de, in: "u\u0308\u0304", want: "ǖ"},
+		{name: "combining marks get reordered", definition: de, in: "a\u0301\u0323", want: "\u1ea1\u0301"},
+		{name: "non latin letters get composed", definition: Definition{}, in: "\u03b1\u0301", want: "\u03ac"},
+		{name: "ss stays ss", definition: de, in: "Masse", want: "masse"},
+		{name: "ß stays ß", definition: de, in: "Maße", want: "maße"},
+		{name: "transliteration rule", definition: transliterating, in: "Baeren", want: "bären"},
+		{name: "transliteration rule with decomposed target", definition: Definition{Normalization: []Replacement{{From: "ae", To: "a\u0308"}}}, in: "baeren", want: "bären"},
+		{name: "unknown language only lowercases", definition: Definition{}, in: "Ba\u0308ren", want: "bären"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.definition.Normalize(tt.in); got != tt.want {
+				t.Errorf("Definition.Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefinition_Fold(t *testing.T) {
+	de, _ := DefaultRegistry.Lookup(LANG_DE)
+	nordic := Definition{Code: "da", Folding: []Replacement{{From: "ø", To: "o"}}}
+
+	tests := []struct {
+		name       string
+		definition Definition
+		in         string
+		want       string
+	}{
+		{name: "umlauts", definition: de, in: "Schön", want: "schon"},
+		{name: "decomposed umlauts", definition: de, in: "Scho\u0308n", want: "schon"},
+		{name: "sharp s has no base letter", definition: de, in: "GRÜẞE", want: "gruße"},
+		{name: "accents", definition: de, in: "Café", want: "cafe"},
+		{name: "several marks", definition: de, in: "Ǖ", want: "u"},
+		{name: "folding rule for non decomposable letter", definition: nordic, in: "Søren", want: "soren"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.definition.Fold(tt.in); got != tt.want {
+				t.Errorf("Definition.Fold(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefinition_Transliterate(t *testing.T) {
+	de, _ := DefaultRegistry.Lookup(LANG_DE)
+	en, _ := DefaultRegistry.Lookup(LANG_EN)
+
+	tests := []struct {
+		name       string
+		definition Definition
+		in         string
+		want       string
+	}{
+		{name: "ae", definition: de, in: "Baeren", want: "bären"},
+		{name: "oe", definition: de, in: "HOEHE", want: "höhe"},
+		{name: "ue", definition: de, in: "Fuesse", want: "füße"},
+		{name: "ss", definition: de, in: "Grüsse", want: "grüße"},
+		{name: "umlauts typed as letters stay", definition: de, in: "Bären", want: "bären"},
+		{name: "english has no transliteration", definition: en, in: "Baeren", want: "baeren"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.definition.Transliterate(tt.in); got != tt.want {
+				t.Errorf("Definition.Transliterate(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package language
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Replacement rewrites From to To, see Definition.Normalization and
+// Definition.Folding.
+type Replacement struct {
+	From string
+	To   string
+}
+
+// Definition describes everything lettr needs to offer a language.
+//
+// WordLists maps a word collection name (see puzzle.WordCollection) to the
+// files feeding it, i.e. the role of the words: "wc_common" solutions,
+// "wc_all" further valid guesses and solutions if there are no common words,
+// "wc_guess_only" guesses never picked as solution, any other name a themed
+// collection players may pick their solutions from, made of words of the
+// other lists. KeyboardLayout names the
+// default entry of KeyboardLayouts, ExtraKeys holds letters not covered by it
+// (e.g. umlauts). Normalization rules are always applied to word lists and
+// input, Folding rules only in easy mode and Transliteration rules only to
+// typed words which are no words as typed (see Normalize, Fold and
+// Transliterate).
+type Definition struct {
+	Code            Language
+	DisplayName     string
+	Alphabet        []rune
+	KeyboardLayout  string
+	ExtraKeys       string
+	Normalization   []Replacement
+	Folding         []Replacement
+	Transliteration []Replacement
+	WordLists       map[string][]string
+}
+
+// AlphabetPattern returns the alphabet in upper and lower case, to be used
+// inside a character class of an html input pattern.
+func (d Definition) AlphabetPattern() string {
+	return string(d.Alphabet) + strings.ToUpper(string(d.Alphabet))
+}
+
+// InAlphabet reports whether every letter of the normalized s is part of the
+// alphabet of the language.
+func (d Definition) InAlphabet(s string) bool {
+	for _, l := range d.Normalize(s) {
+		if !slices.Contains(d.Alphabet, l) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Registry holds the playable languages in the order they are offered.
+type Registry struct {
+	definitions []Definition
+}
+
+func NewRegistry(definitions ...Definition) (*Registry, error) {
+	r := &Registry{}
+	for _, d := range definitions {
+		err := r.Register(d)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+func MustNewRegistry(definitions ...Definition) *Registry {
+	r, err := NewRegistry(definitions...)
+	if err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+func (r *Registry) Register(d Definition) error {
+	if d.Code == "" {
+		return fmt.Errorf("language registry: definition without code")
+	}
+
+	if _, ok := r.Lookup(d.Code); ok {
+		return fmt.Errorf("language registry: language '%s' already registered", d.Code)
+	}
+
+	if len(d.Alphabet) == 0 {
+		return fmt.Errorf("language registry: language '%s' has an empty alphabet", d.Code)
+	}
+
+	for _, l := range d.Alphabet {
+		if !unicode.IsLetter(l) || unicode.ToLower(l) != l {
+			return fmt.Errorf("language registry: language '%s' alphabet contains non lowercase letter '%c'", d.Code, l)
+		}
+	}
+
+	for _, f := range d.Folding {
+		if utf8.RuneCountInString(f.From) != 1 || utf8.RuneCountInString(f.To) != 1 {
+			return fmt.Errorf("language registry: language '%s' folding rule '%s' -> '%s' must map a single letter", d.Code, f.From, f.To)
+		}
+	}
+
+	if _, ok := LookupKeyboardLayout(d.KeyboardLayout); !ok {
+		return fmt.Errorf("language registry: language '%s' has unknown keyboard layout '%s'", d.Code, d.KeyboardLayout)
+	}
+
+	r.definitions = append(r.definitions, d)
+
+	return nil
+}
+
+func (r *Registry) Lookup(l Language) (Definition, bool) {
+	i := slices.IndexFunc(r.definitions, func(d Definition) bool {
+		return d.Code == l
+	})
+	if i == -1 {
+		return Definition{}, false
+	}
+
+	return r.definitions[i], true
+}
+
+func (r *Registry) Definitions() []Definition {
+	return slices.Clone(r.definitions)
+}
+
+// DisplayName returns the name of l as shown to players, or the code itself
+// for unknown languages.
+func (r *Registry) DisplayName(l Language) string {
+	d, ok := r.Lookup(l)
+	if !ok {
+		return string(l)
+	}
+
+	return d.DisplayName
+}
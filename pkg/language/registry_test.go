@@ -0,0 +1,112 @@
+package language
+
+import (
+	"testing"
+)
+
+func TestNewRegistry(t *testing.T) {
+	tests := []struct {
+		name        string
+		definitions []Definition
+		wantErr     bool
+	}{
+		{
+			name: "valid definitions",
+			definitions: []Definition{
+				{Code: "en", Alphabet: []rune("abc"), KeyboardLayout: "qwerty"},
+				{Code: "fr", Alphabet: []rune("abcéè"), KeyboardLayout: "azerty"},
+			},
+			wantErr: false,
+		},
+		{name: "missing code", definitions: []Definition{{Alphabet: []rune("abc")}}, wantErr: true},
+		{name: "empty alphabet", definitions: []Definition{{Code: "en", KeyboardLayout: "qwerty"}}, wantErr: true},
+		{name: "unknown keyboard layout", definitions: []Definition{{Code: "en", Alphabet: []rune("abc"), KeyboardLayout: "colemak"}}, wantErr: true},
+		{name: "uppercase letter in alphabet", definitions: []Definition{{Code: "en", Alphabet: []rune("abC"), KeyboardLayout: "qwerty"}}, wantErr: true},
+		{name: "non letter in alphabet", definitions: []Definition{{Code: "en", Alphabet: []rune("ab1"), KeyboardLayout: "qwerty"}}, wantErr: true},
+		{name: "folding rule changes letter count", definitions: []Definition{{Code: "de", Alphabet: []rune("abc"), KeyboardLayout: "qwertz", Folding: []Replacement{{From: "ß", To: "ss"}}}}, wantErr: true},
+		{
+			name: "duplicate code",
+			definitions: []Definition{
+				{Code: "en", Alphabet: []rune("abc"), KeyboardLayout: "qwerty"},
+				{Code: "en", Alphabet: []rune("abc"), KeyboardLayout: "qwerty"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRegistry(tt.definitions...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewRegistry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegistry_Lookup(t *testing.T) {
+	r := MustNewRegistry(
+		Definition{Code: "en", DisplayName: "English", Alphabet: []rune("abc"), KeyboardLayout: "qwerty"},
+		Definition{Code: "nl", DisplayName: "Nederlands", Alphabet: []rune("abc"), KeyboardLayout: "qwerty"},
+	)
+
+	d, ok := r.Lookup("nl")
+	if !ok || d.DisplayName != "Nederlands" {
+		t.Errorf("Lookup(nl) = %v, %v", d, ok)
+	}
+
+	if _, ok := r.Lookup("de"); ok {
+		t.Errorf("Lookup(de) expected to fail for unregistered language")
+	}
+
+	if got := r.DisplayName("xx"); got != "xx" {
+		t.Errorf("DisplayName(xx) = %q, want code as fallback", got)
+	}
+
+	codes := []Language{}
+	for _, d := range r.Definitions() {
+		codes = append(codes, d.Code)
+	}
+	if len(codes) != 2 || codes[0] != "en" || codes[1] != "nl" {
+		t.Errorf("Definitions() order = %v, want registration order", codes)
+	}
+}
+
+func TestDefinition_InAlphabet(t *testing.T) {
+	d := Definition{
+		Code:          "de",
+		Alphabet:      []rune("abcdefghijklmnopqrstuvwxyzäöüß"),
+		Normalization: []Replacement{{From: "ae", To: "ä"}},
+	}
+
+	tests := []struct {
+		word string
+		want bool
+	}{
+		{"größe", true},
+		{"GRÖßE", true},
+		{"maedchen", true},
+		{"grôss", false},
+		{"gr0ss", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := d.InAlphabet(tt.word); got != tt.want {
+				t.Errorf("InAlphabet(%q) = %v, want %v", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLang(t *testing.T) {
+	for _, d := range DefaultRegistry.Definitions() {
+		l, err := NewLang(string(d.Code))
+		if err != nil || l != d.Code {
+			t.Errorf("NewLang(%q) = %v, %v", d.Code, l, err)
+		}
+	}
+
+	l, err := NewLang("xx")
+	if err == nil || l != LANG_EN {
+		t.Errorf("NewLang(xx) = %v, %v; want LANG_EN and an error", l, err)
+	}
+}
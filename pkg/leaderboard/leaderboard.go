@@ -0,0 +1,236 @@
+// Package leaderboard ranks players by survival score, win streak and
+// puzzles solved per day. Players are known by their session and an optional
+// nickname only.
+package leaderboard
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/clock"
+)
+
+const DEFAULT_SIZE = 10
+
+// MAX_SAVE_ATTEMPTS is how often a change is applied again after another
+// instance changed the board in the meantime.
+const MAX_SAVE_ATTEMPTS = 3
+
+type Kind string
+
+const (
+	// KIND_SURVIVAL ranks survival runs, a player can appear several times.
+	KIND_SURVIVAL Kind = "survival"
+	// KIND_STREAK ranks the best win streak of every player.
+	KIND_STREAK Kind = "streak"
+	// KIND_DAILY ranks the puzzles every player solved today.
+	KIND_DAILY Kind = "daily"
+)
+
+var Kinds = []Kind{KIND_SURVIVAL, KIND_STREAK, KIND_DAILY}
+
+type Entry struct {
+	SessionID string    `json:"-"`
+	Nickname  string    `json:"nickname"`
+	Score     int       `json:"score"`
+	Words     int       `json:"words,omitempty"`
+	At        time.Time `json:"at"`
+}
+
+type Board struct {
+	mutex   sync.Mutex
+	kind    Kind
+	entries []Entry
+	size    int
+	clock   clock.Clock
+	store   Store
+}
+
+// New returns a Board of the given kind keeping the top size entries. The
+// entries are loaded from and written through to s.
+func New(kind Kind, c clock.Clock, s Store, size int) (*Board, error) {
+	entries, err := s.Load(kind)
+	if err != nil {
+		return nil, fmt.Errorf("leaderboard '%s': loading entries failed: %s", kind, err)
+	}
+
+	return &Board{kind: kind, entries: entries, size: size, clock: c, store: s}, nil
+}
+
+// Boards bundles one Board of every Kind.
+type Boards struct {
+	Survival *Board
+	Streak   *Board
+	Daily    *Board
+}
+
+func NewBoards(c clock.Clock, s Store, size int) (Boards, error) {
+	bs := Boards{}
+	for _, kind := range Kinds {
+		b, err := New(kind, c, s, size)
+		if err != nil {
+			return Boards{}, err
+		}
+
+		switch kind {
+		case KIND_SURVIVAL:
+			bs.Survival = b
+		case KIND_STREAK:
+			bs.Streak = b
+		case KIND_DAILY:
+			bs.Daily = b
+		}
+	}
+
+	return bs, nil
+}
+
+// MustNewBoards is like NewBoards but panics on error.
+func MustNewBoards(c clock.Clock, s Store, size int) Boards {
+	bs, err := NewBoards(c, s, size)
+	if err != nil {
+		panic(err)
+	}
+
+	return bs
+}
+
+// Rename changes the nickname of the player on all boards.
+func (bs Boards) Rename(sessionID string, nickname string) {
+	for _, b := range []*Board{bs.Survival, bs.Streak, bs.Daily} {
+		b.Rename(sessionID, nickname)
+	}
+}
+
+func (b *Board) Kind() Kind {
+	return b.kind
+}
+
+// Add records a score and returns its rank starting at 1, or 0 if it did
+// not make it onto the board. Scores of zero or below are ignored. Except
+// for survival runs only the best score of every player is kept.
+func (b *Board) Add(sessionID string, nickname string, score int, words int) int {
+	if score <= 0 {
+		return 0
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	e := Entry{SessionID: sessionID, Nickname: nickname, Score: score, Words: words, At: b.clock.Now()}
+
+	rank := 0
+	b.update(func() {
+		rank = 0
+		if b.kind != KIND_SURVIVAL {
+			i := slices.IndexFunc(b.entries, func(e Entry) bool { return e.SessionID == sessionID })
+			if i != -1 && b.entries[i].Score >= score {
+				return
+			}
+			if i != -1 {
+				b.entries = slices.Delete(b.entries, i, i+1)
+			}
+		}
+
+		rank = b.insert(e)
+	})
+
+	return rank
+}
+
+// Rename changes the nickname shown for all entries of the player.
+func (b *Board) Rename(sessionID string, nickname string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.update(func() {
+		for i := range b.entries {
+			if b.entries[i].SessionID == sessionID {
+				b.entries[i].Nickname = nickname
+			}
+		}
+	})
+}
+
+// Top returns all entries, best first.
+func (b *Board) Top() []Entry {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.load()
+	b.expire()
+
+	return slices.Clone(b.entries)
+}
+
+// insert puts e at its place, higher scores first and on a tie the earlier
+// entry first. Entries falling off the board are dropped.
+func (b *Board) insert(e Entry) int {
+	i, _ := slices.BinarySearchFunc(b.entries, e, func(a, t Entry) int {
+		if a.Score > t.Score || (a.Score == t.Score && !a.At.After(t.At)) {
+			return -1
+		}
+		return 1
+	})
+	if i >= b.size {
+		return 0
+	}
+
+	b.entries = slices.Insert(b.entries, i, e)
+	if len(b.entries) > b.size {
+		b.entries = b.entries[:b.size]
+	}
+
+	return i + 1
+}
+
+// expire drops the entries of past days from a daily board.
+func (b *Board) expire() {
+	if b.kind != KIND_DAILY {
+		return
+	}
+
+	y, m, d := b.clock.Now().UTC().Date()
+	b.entries = slices.DeleteFunc(b.entries, func(e Entry) bool {
+		ey, em, ed := e.At.UTC().Date()
+		return ey != y || em != m || ed != d
+	})
+}
+
+// load replaces the entries by the stored ones, boards of several instances
+// share their store. Callers must hold the mutex.
+func (b *Board) load() {
+	entries, err := b.store.Load(b.kind)
+	if err != nil {
+		// the board in memory stays authoritative, the next save catches up
+		log.Printf("leaderboard '%s': loading entries failed: %s", b.kind, err)
+		return
+	}
+
+	b.entries = entries
+}
+
+// update applies change to the stored entries and saves them, it applies
+// change again if another instance saved in the meantime. Callers must hold
+// the mutex.
+func (b *Board) update(change func()) {
+	for attempt := 1; ; attempt++ {
+		b.load()
+		b.expire()
+		change()
+
+		err := b.store.Save(b.kind, slices.Clone(b.entries))
+		if errors.Is(err, ErrConflict) && attempt < MAX_SAVE_ATTEMPTS {
+			continue
+		}
+		if err != nil {
+			log.Printf("leaderboard '%s': saving entries failed: %s", b.kind, err)
+		}
+
+		return
+	}
+}
@@ -0,0 +1,108 @@
+package leaderboard
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/clock"
+)
+
+func newTestBoard(t *testing.T, kind Kind, c clock.Clock, s Store) *Board {
+	t.Helper()
+
+	b, err := New(kind, c, s, 3)
+	if err != nil {
+		t.Fatalf("New() error = %s", err)
+	}
+
+	return b
+}
+
+func sessionIDs(entries []Entry) []string {
+	ids := []string{}
+	for _, e := range entries {
+		ids = append(ids, e.SessionID)
+	}
+
+	return ids
+}
+
+func TestBoard_Add(t *testing.T) {
+	c := clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	b := newTestBoard(t, KIND_SURVIVAL, c, NewMemoryStore())
+
+	tests := []struct {
+		sid   string
+		score int
+		want  int
+	}{
+		{"a", 50, 1},
+		{"b", 80, 1},
+		{"c", 50, 3}, // same score as "a" but later
+		{"d", 0, 0},  // no score, no entry
+		{"e", 60, 2},
+		{"f", 10, 0}, // board is full
+		{"b", 70, 2}, // survival keeps every run
+	}
+	for _, tt := range tests {
+		c.Advance(time.Minute)
+		if got := b.Add(tt.sid, "", tt.score, tt.score/10); got != tt.want {
+			t.Errorf("Add(%s, %d) = %d, want %d", tt.sid, tt.score, got, tt.want)
+		}
+	}
+
+	if got, want := sessionIDs(b.Top()), []string{"b", "b", "e"}; !slices.Equal(got, want) {
+		t.Errorf("Top() = %v, want %v", got, want)
+	}
+}
+
+func TestBoard_Add_bestPerPlayer(t *testing.T) {
+	c := clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	b := newTestBoard(t, KIND_STREAK, c, NewMemoryStore())
+
+	b.Add("a", "", 3, 0)
+	b.Add("b", "", 2, 0)
+	if got := b.Add("a", "", 1, 0); got != 0 {
+		t.Errorf("Add() of a worse score = %d, want 0", got)
+	}
+	if got := b.Add("b", "", 4, 0); got != 1 {
+		t.Errorf("Add() of a better score = %d, want 1", got)
+	}
+
+	if got, want := sessionIDs(b.Top()), []string{"b", "a"}; !slices.Equal(got, want) {
+		t.Errorf("Top() = %v, want %v", got, want)
+	}
+}
+
+func TestBoard_daily(t *testing.T) {
+	c := clock.NewFake(time.Date(2024, 6, 1, 23, 0, 0, 0, time.UTC))
+	b := newTestBoard(t, KIND_DAILY, c, NewMemoryStore())
+
+	b.Add("a", "", 5, 0)
+	c.Advance(2 * time.Hour)
+	b.Add("b", "", 1, 0)
+
+	if got, want := sessionIDs(b.Top()), []string{"b"}; !slices.Equal(got, want) {
+		t.Errorf("Top() = %v, want %v", got, want)
+	}
+}
+
+func TestBoard_store(t *testing.T) {
+	c := clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	s := NewMemoryStore()
+
+	b := newTestBoard(t, KIND_SURVIVAL, c, s)
+	b.Add("a", "", 10, 1)
+	b.Rename("a", "nox")
+
+	restarted := newTestBoard(t, KIND_SURVIVAL, c, s)
+	top := restarted.Top()
+	if len(top) != 1 || top[0].Nickname != "nox" || top[0].Score != 10 {
+		t.Errorf("Top() after reload = %+v, want the renamed entry", top)
+	}
+
+	if other := newTestBoard(t, KIND_STREAK, c, s).Top(); len(other) != 0 {
+		t.Errorf("Top() of another kind = %+v, want none", other)
+	}
+}
@@ -0,0 +1,118 @@
+package leaderboard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/redis"
+)
+
+const REDIS_KEY_PREFIX = "lettr:leaderboard:"
+
+// RedisStore keeps the boards on a Redis protocol server, so several
+// instances share them. Every kind is one key holding all of its entries.
+type RedisStore struct {
+	client *redis.Client
+
+	mutex sync.Mutex
+	// loaded are the entries of every kind as of the last Load, saves
+	// only succeed if they are still stored
+	loaded map[Kind]string
+}
+
+var _ Store = (*RedisStore)(nil)
+
+func NewRedisStore(c *redis.Client) *RedisStore {
+	return &RedisStore{client: c, loaded: map[Kind]string{}}
+}
+
+// storedEntry is an Entry as stored, unlike the json of Entry it holds the
+// session id.
+type storedEntry struct {
+	SessionID string    `json:"s"`
+	Nickname  string    `json:"n"`
+	Score     int       `json:"p"`
+	Words     int       `json:"w"`
+	At        time.Time `json:"a"`
+}
+
+// saveScript stores the entries unless another instance saved since they
+// got loaded.
+//
+// KEYS[1] board key
+// ARGV[1] entries as loaded, ARGV[2] entries to save
+var saveScript = redis.NewScript(`
+local stored = redis.call('GET', KEYS[1]) or ''
+if stored ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2])
+return 1
+`)
+
+func (rs *RedisStore) Load(kind Kind) ([]Entry, error) {
+	data := ""
+	reply, err := rs.client.Do("GET", REDIS_KEY_PREFIX+string(kind))
+	switch {
+	case errors.Is(err, redis.ErrNil):
+	case err != nil:
+		return nil, fmt.Errorf("redis store: loading entries failed: %s", err)
+	default:
+		data, _ = reply.(string)
+	}
+
+	stored := []storedEntry{}
+	if data != "" {
+		err = json.Unmarshal([]byte(data), &stored)
+		if err != nil {
+			return nil, fmt.Errorf("redis store: unmarshal entries failed: %s", err)
+		}
+	}
+
+	rs.mutex.Lock()
+	rs.loaded[kind] = data
+	rs.mutex.Unlock()
+
+	entries := make([]Entry, 0, len(stored))
+	for _, e := range stored {
+		entries = append(entries, Entry(e))
+	}
+
+	return entries, nil
+}
+
+// Save stores the entries, it fails with ErrConflict if another instance
+// saved the kind since the last Load.
+func (rs *RedisStore) Save(kind Kind, entries []Entry) error {
+	stored := make([]storedEntry, 0, len(entries))
+	for _, e := range entries {
+		stored = append(stored, storedEntry(e))
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("redis store: marshal entries failed: %s", err)
+	}
+
+	rs.mutex.Lock()
+	loaded := rs.loaded[kind]
+	rs.mutex.Unlock()
+
+	reply, err := saveScript.Run(rs.client, []string{REDIS_KEY_PREFIX + string(kind)}, loaded, string(data))
+	if err != nil {
+		return fmt.Errorf("redis store: saving entries failed: %s", err)
+	}
+
+	if saved, _ := reply.(int64); saved != 1 {
+		return ErrConflict
+	}
+
+	rs.mutex.Lock()
+	rs.loaded[kind] = string(data)
+	rs.mutex.Unlock()
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package leaderboard
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/pandorasNox/lettr/pkg/clock"
+	"github.com/pandorasNox/lettr/pkg/redis"
+)
+
+func TestRedisStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c := clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	// every instance has its own board and connections
+	instance := func() (*Board, *RedisStore) {
+		client := redis.NewClient(mr.Addr())
+		t.Cleanup(func() { client.Close() })
+
+		s := NewRedisStore(client)
+		return newTestBoard(t, KIND_STREAK, c, s), s
+	}
+
+	first, firstStore := instance()
+	second, _ := instance()
+
+	first.Add("a", "nox", 3, 0)
+	if got := second.Add("b", "", 5, 0); got != 1 {
+		t.Errorf("Add() on the second instance = %d, want 1", got)
+	}
+	first.Rename("b", "pan")
+
+	for _, b := range []*Board{first, second} {
+		top := b.Top()
+		if got, want := sessionIDs(top), []string{"b", "a"}; !slices.Equal(got, want) {
+			t.Fatalf("Top() = %v, want %v", got, want)
+		}
+		if top[0].Nickname != "pan" || !top[1].At.Equal(c.Now()) {
+			t.Errorf("Top() = %+v, want the renamed entry and the time it was added", top)
+		}
+	}
+
+	t.Run("conflict", func(t *testing.T) {
+		firstStore.Load(KIND_STREAK)
+		second.Add("c", "", 4, 0)
+
+		if err := firstStore.Save(KIND_STREAK, []Entry{}); !errors.Is(err, ErrConflict) {
+			t.Errorf("Save() of outdated entries error = %v, want %s", err, ErrConflict)
+		}
+		if got, want := sessionIDs(first.Top()), []string{"b", "c", "a"}; !slices.Equal(got, want) {
+			t.Errorf("Top() = %v, want %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package leaderboard
+
+import (
+	"errors"
+	"slices"
+	"sync"
+)
+
+// ErrConflict is returned by stores shared by several instances for saves
+// of entries changed by another instance since they got loaded.
+var ErrConflict = errors.New("leaderboard changed by another instance")
+
+// Store persists the entries of every board kind.
+type Store interface {
+	Load(kind Kind) ([]Entry, error)
+	// Save stores the entries, shared stores fail with ErrConflict if
+	// the entries changed since the last Load.
+	Save(kind Kind, entries []Entry) error
+}
+
+// MemoryStore keeps the entries for the lifetime of the process.
+type MemoryStore struct {
+	mutex   sync.Mutex
+	entries map[Kind][]Entry
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: map[Kind][]Entry{}}
+}
+
+func (s *MemoryStore) Load(kind Kind) ([]Entry, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entries := slices.Clone(s.entries[kind])
+	if entries == nil {
+		entries = []Entry{}
+	}
+
+	return entries, nil
+}
+
+func (s *MemoryStore) Save(kind Kind, entries []Entry) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.entries[kind] = slices.Clone(entries)
+
+	return nil
+}
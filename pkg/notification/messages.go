@@ -1,13 +1,22 @@
 package notification
 
-import "slices"
+import (
+	"github.com/pandorasNox/lettr/pkg/i18n"
+	"github.com/pandorasNox/lettr/pkg/language"
+)
 
 type message string
 
+// entry is an untranslated message, a catalog key plus its params.
+type entry struct {
+	key    string
+	params []any
+}
+
 type Notifier struct {
-	errorMsgs   []message
-	infoMsgs    []message
-	successMsgs []message
+	errorMsgs   []entry
+	infoMsgs    []entry
+	successMsgs []entry
 }
 
 func NewNotifier() Notifier {
@@ -20,22 +29,32 @@ type TemplateDataMessages struct {
 	SuccessMsgs []message
 }
 
-func (n *Notifier) AddError(msg string) {
-	n.errorMsgs = append(n.errorMsgs, message(msg))
+func (n *Notifier) AddError(key string, params ...any) {
+	n.errorMsgs = append(n.errorMsgs, entry{key, params})
 }
 
-func (n *Notifier) AddInfo(msg string) {
-	n.infoMsgs = append(n.infoMsgs, message(msg))
+func (n *Notifier) AddInfo(key string, params ...any) {
+	n.infoMsgs = append(n.infoMsgs, entry{key, params})
 }
 
-func (n *Notifier) AddSuccess(msg string) {
-	n.successMsgs = append(n.successMsgs, message(msg))
+func (n *Notifier) AddSuccess(key string, params ...any) {
+	n.successMsgs = append(n.successMsgs, entry{key, params})
 }
 
-func (n *Notifier) ToTemplate() TemplateDataMessages {
+// ToTemplate translates all collected messages into language l.
+func (n *Notifier) ToTemplate(l language.Language) TemplateDataMessages {
 	return TemplateDataMessages{
-		slices.Clone(n.errorMsgs),
-		slices.Clone(n.infoMsgs),
-		slices.Clone(n.successMsgs),
+		translate(l, n.errorMsgs),
+		translate(l, n.infoMsgs),
+		translate(l, n.successMsgs),
+	}
+}
+
+func translate(l language.Language, entries []entry) []message {
+	msgs := make([]message, 0, len(entries))
+	for _, e := range entries {
+		msgs = append(msgs, message(i18n.T(l, e.key, e.params...)))
 	}
+
+	return msgs
 }
@@ -0,0 +1,903 @@
+package puzzle
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+	"unicode/utf8"
+)
+
+// CODEC_VERSION is the schema version the GameState encodings are written
+// with. Word, WordGuess and Puzzle are building blocks of these encodings,
+// changing their layout means a new version. Payloads of older versions are
+// upgraded by the migrations below before decoding.
+const CODEC_VERSION = 1
+
+// codecMagic starts every binary GameState payload.
+const codecMagic byte = 'L'
+
+var (
+	ErrInvalidEncoding     = errors.New("invalid encoding")
+	ErrUnknownCodecVersion = errors.New("unknown codec version")
+)
+
+// jsonMigrations upgrade a JSON GameState payload of version v (the key) to
+// version v+1, binaryMigrations do the same for the binary payload following
+// the header.
+var (
+	jsonMigrations   = map[int]func(json.RawMessage) (json.RawMessage, error){}
+	binaryMigrations = map[int]func([]byte) ([]byte, error){}
+)
+
+// limits for decoding, a payload claiming more is corrupted
+const (
+	codecMaxBoards      = 16
+	codecMaxRows        = 64
+	codecMaxTransitions = 16
+	codecMaxString      = 256
+)
+
+// statuses and moves are written by their index in the binary encoding,
+// only ever append to these lists
+var (
+	codecStatuses = []Status{STATUS_NOT_STARTED, STATUS_IN_PROGRESS, STATUS_WON, STATUS_LOST, STATUS_ABANDONED}
+	codecMoves    = []Move{MOVE_GUESS, MOVE_HINT, MOVE_TIME_OUT, MOVE_ABANDON}
+)
+
+var matchCodes = map[Match]byte{0: '-', MatchNone: 'n', MatchVague: 'v', MatchExact: 'e'}
+
+func invalid(format string, a ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidEncoding, fmt.Sprintf(format, a...))
+}
+
+// checkWord accepts complete words and the empty word only.
+func checkWord(w Word) error {
+	zeros := w.Count(0)
+	if zeros != 0 && zeros != len(w) {
+		return invalid("incomplete word %q", w.String())
+	}
+
+	for _, l := range w {
+		if l != 0 && !validLetter(l) {
+			return invalid("invalid letter %U", l)
+		}
+	}
+
+	return nil
+}
+
+func validLetter(l rune) bool {
+	return l > 0 && l != utf8.RuneError && utf8.ValidRune(l)
+}
+
+// checkWordGuess accepts evaluated words and the empty row.
+func checkWordGuess(wg WordGuess) error {
+	w := Word{}
+	for i, lg := range wg {
+		w[i] = lg.Letter
+	}
+
+	if err := checkWord(w); err != nil {
+		return err
+	}
+
+	for _, lg := range wg {
+		if (lg.Letter == 0) != (lg.Match == 0) || lg.Match > MatchExact {
+			return invalid("invalid match %d for letter %U", lg.Match, lg.Letter)
+		}
+	}
+
+	return nil
+}
+
+// Word is encoded as its letters, the empty word as "".
+func (w Word) MarshalJSON() ([]byte, error) {
+	if err := checkWord(w); err != nil {
+		return nil, err
+	}
+
+	if w == (Word{}) {
+		return json.Marshal("")
+	}
+
+	return json.Marshal(w.String())
+}
+
+func (w *Word) UnmarshalJSON(b []byte) error {
+	s := ""
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return invalid("word: %s", err)
+	}
+
+	if s == "" {
+		*w = Word{}
+		return nil
+	}
+
+	out, err := toWord(s)
+	if err != nil {
+		return invalid("word: %s", err)
+	}
+	if err := checkWord(out); err != nil {
+		return err
+	}
+
+	*w = out
+	return nil
+}
+
+type wordGuessJSON struct {
+	Word    Word   `json:"word"`
+	Matches string `json:"matches"`
+}
+
+// WordGuess is encoded as guessed word plus one match code per letter: 'n'
+// for none, 'v' for vague and 'e' for exact matches.
+func (wg WordGuess) MarshalJSON() ([]byte, error) {
+	if err := checkWordGuess(wg); err != nil {
+		return nil, err
+	}
+
+	out := wordGuessJSON{}
+	if wg.isFilled() {
+		for i, lg := range wg {
+			out.Word[i] = lg.Letter
+			out.Matches += string(matchCodes[lg.Match])
+		}
+	}
+
+	return json.Marshal(out)
+}
+
+func (wg *WordGuess) UnmarshalJSON(b []byte) error {
+	in := wordGuessJSON{}
+	err := json.Unmarshal(b, &in)
+	if err != nil {
+		return invalid("word guess: %s", err)
+	}
+
+	if in.Word == (Word{}) && in.Matches == "" {
+		*wg = WordGuess{}
+		return nil
+	}
+	if len(in.Matches) != len(in.Word) {
+		return invalid("word guess: %d matches for %d letters", len(in.Matches), len(in.Word))
+	}
+
+	out := WordGuess{}
+	for i, l := range in.Word {
+		out[i].Letter = l
+		for m, c := range matchCodes {
+			if m != 0 && c == in.Matches[i] {
+				out[i].Match = m
+			}
+		}
+	}
+	if err := checkWordGuess(out); err != nil {
+		return err
+	}
+
+	*wg = out
+	return nil
+}
+
+// Puzzle is encoded as list of its filled rows.
+func (p Puzzle) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.Guesses[:p.ActiveRow()])
+}
+
+func (p *Puzzle) UnmarshalJSON(b []byte) error {
+	rows := []WordGuess{}
+	err := json.Unmarshal(b, &rows)
+	if err != nil {
+		return err
+	}
+
+	out, err := puzzleFromRows(rows)
+	if err != nil {
+		return err
+	}
+
+	*p = out
+	return nil
+}
+
+func puzzleFromRows(rows []WordGuess) (Puzzle, error) {
+	p := Puzzle{}
+	if len(rows) > len(p.Guesses) {
+		return Puzzle{}, invalid("puzzle: %d rows", len(rows))
+	}
+
+	for i, wg := range rows {
+		if !wg.isFilled() {
+			return Puzzle{}, invalid("puzzle: empty row %d", i)
+		}
+		p.Guesses[i] = wg
+	}
+
+	return p, nil
+}
+
+type gameStateJSON struct {
+	Version      int          `json:"version"`
+	Solution     Word         `json:"solution"`
+	Hints        string       `json:"hints"`
+	Puzzle       Puzzle       `json:"puzzle"`
+	Easy         bool         `json:"easy,omitempty"`
+	ChallengeID  string       `json:"challengeId,omitempty"`
+	MultiBoard   *MultiBoard  `json:"multiBoard,omitempty"`
+	Survival     *SurvivalRun `json:"survival,omitempty"`
+	Speed        *speedJSON   `json:"speed,omitempty"`
+	FirstGuessAt time.Time    `json:"firstGuessAt"`
+	Status       Status       `json:"status"`
+	Transitions  []Transition `json:"transitions,omitempty"`
+}
+
+type speedJSON struct {
+	TimeLimit time.Duration `json:"timeLimit"`
+	StartedAt time.Time     `json:"startedAt"`
+	GuessedAt []time.Time   `json:"guessedAt"`
+}
+
+func (g GameState) MarshalJSON() ([]byte, error) {
+	out := gameStateJSON{
+		Version:      CODEC_VERSION,
+		Solution:     g.activeSolutionWord,
+		Hints:        string(g.letterHints),
+		Puzzle:       g.lastEvaluatedAttempt,
+		Easy:         g.easy,
+		ChallengeID:  g.challengeID,
+		FirstGuessAt: g.firstGuessAt,
+		Status:       g.Status(),
+		Transitions:  g.transitions,
+	}
+
+	if g.IsMultiBoard() {
+		out.MultiBoard = &g.multiBoard
+	}
+	if g.survival != (SurvivalRun{}) {
+		out.Survival = &g.survival
+	}
+	if g.timeLimit != 0 || !g.startedAt.IsZero() || len(g.guessedAt) > 0 {
+		out.Speed = &speedJSON{TimeLimit: g.timeLimit, StartedAt: g.startedAt, GuessedAt: g.guessedAt}
+	}
+
+	return json.Marshal(out)
+}
+
+func (g *GameState) UnmarshalJSON(b []byte) error {
+	header := struct {
+		Version int `json:"version"`
+	}{}
+	err := json.Unmarshal(b, &header)
+	if err != nil {
+		return invalid("game state: %s", err)
+	}
+
+	raw := json.RawMessage(b)
+	for v := header.Version; v < CODEC_VERSION; v++ {
+		migrate, ok := jsonMigrations[v]
+		if !ok {
+			return fmt.Errorf("%w: %d", ErrUnknownCodecVersion, header.Version)
+		}
+
+		raw, err = migrate(raw)
+		if err != nil {
+			return fmt.Errorf("migrating game state from version %d failed: %w", v, err)
+		}
+	}
+	if header.Version > CODEC_VERSION {
+		return fmt.Errorf("%w: %d", ErrUnknownCodecVersion, header.Version)
+	}
+
+	in := gameStateJSON{}
+	err = json.Unmarshal(raw, &in)
+	if err != nil {
+		return invalid("game state: %s", err)
+	}
+
+	out := GameState{
+		activeSolutionWord:   in.Solution,
+		letterHints:          []rune(in.Hints),
+		lastEvaluatedAttempt: in.Puzzle,
+		easy:                 in.Easy,
+		challengeID:          in.ChallengeID,
+		firstGuessAt:         in.FirstGuessAt,
+		transitions:          in.Transitions,
+	}
+	if in.Status != STATUS_NOT_STARTED {
+		out.status = in.Status
+	}
+	if in.MultiBoard != nil {
+		out.multiBoard = *in.MultiBoard
+	}
+	if in.Survival != nil {
+		out.survival = *in.Survival
+	}
+	if in.Speed != nil {
+		out.timeLimit, out.startedAt, out.guessedAt = in.Speed.TimeLimit, in.Speed.StartedAt, in.Speed.GuessedAt
+	}
+
+	err = out.check()
+	if err != nil {
+		return err
+	}
+
+	*g = out
+	return nil
+}
+
+// check rejects decoded games the moves of GameState can't lead to, so a
+// corrupted payload can't break the game later on.
+func (g *GameState) check() error {
+	if err := checkWord(g.activeSolutionWord); err != nil {
+		return err
+	}
+
+	if len(g.letterHints) > len(g.activeSolutionWord) {
+		return invalid("%d hints", len(g.letterHints))
+	}
+	for _, l := range g.letterHints {
+		if !validLetter(l) {
+			return invalid("invalid hint %U", l)
+		}
+	}
+
+	if len(g.challengeID) > codecMaxString {
+		return invalid("challenge id too long")
+	}
+
+	if g.timeLimit < 0 || len(g.guessedAt) > codecMaxRows {
+		return invalid("invalid speed game")
+	}
+
+	if err := g.checkMultiBoard(); err != nil {
+		return err
+	}
+
+	return g.checkStatus()
+}
+
+func (g *GameState) checkMultiBoard() error {
+	m := g.multiBoard
+	if m.Boards == nil && m.Guesses == nil && m.MaxAttempts == 0 {
+		return nil
+	}
+
+	n := len(m.Boards)
+	if n == 0 || n > codecMaxBoards || m.MaxAttempts != MultiBoardAttempts(n) || len(m.Guesses) > m.MaxAttempts {
+		return invalid("multi board with %d boards and %d guesses", n, len(m.Guesses))
+	}
+
+	for _, w := range m.Guesses {
+		if err := checkWord(w); err != nil || w == (Word{}) {
+			return invalid("multi board guess %q", w.String())
+		}
+	}
+
+	for i, b := range m.Boards {
+		if err := checkWord(b.Solution); err != nil || b.Solution == (Word{}) {
+			return invalid("multi board solution %d", i)
+		}
+		if len(b.Rows) > len(m.Guesses) {
+			return invalid("multi board %d has %d rows", i, len(b.Rows))
+		}
+		for _, wg := range b.Rows {
+			if !wg.isFilled() {
+				return invalid("multi board %d has an empty row", i)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (g *GameState) checkStatus() error {
+	status := g.Status()
+	if !contains(codecStatuses, status) {
+		return invalid("unknown status %q", status)
+	}
+
+	if len(g.transitions) > codecMaxTransitions {
+		return invalid("%d transitions", len(g.transitions))
+	}
+	for _, t := range g.transitions {
+		if !contains(codecMoves, t.Move) || !contains(codecStatuses, t.From) || !contains(codecStatuses, t.To) {
+			return invalid("unknown transition %+v", t)
+		}
+	}
+
+	if status == STATUS_NOT_STARTED {
+		if len(g.transitions) > 0 {
+			return invalid("transitions of a game not started")
+		}
+		return nil
+	}
+
+	if len(g.transitions) == 0 || g.transitions[len(g.transitions)-1].To != status {
+		return invalid("transitions don't lead to status %q", status)
+	}
+
+	return nil
+}
+
+func contains[T comparable](ts []T, t T) bool {
+	return index(ts, t) >= 0
+}
+
+func index[T comparable](ts []T, t T) int {
+	for i, v := range ts {
+		if v == t {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// binary layout, all integers are varints:
+//
+//	GameState:  magic, version, Word (solution), hints, Puzzle, flags,
+//	            challenge id, [MultiBoard], SurvivalRun, speed, first guess,
+//	            status, transitions
+//	Word:       5 letters
+//	WordGuess:  Word, 2 bits per match packed into one integer
+//	Puzzle:     number of filled rows, WordGuess per row
+//	time:       0 for the zero time, else 1, unix seconds, nanoseconds
+const (
+	flagEasy = 1 << iota
+	flagMultiBoard
+	flagSurvival
+)
+
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) uvarint(v uint64) {
+	e.buf = binary.AppendUvarint(e.buf, v)
+}
+
+func (e *encoder) varint(v int64) {
+	e.buf = binary.AppendVarint(e.buf, v)
+}
+
+func (e *encoder) int(v int) {
+	e.uvarint(uint64(max(v, 0)))
+}
+
+func (e *encoder) string(s string) {
+	e.int(len(s))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *encoder) runes(rs []rune) {
+	e.int(len(rs))
+	for _, r := range rs {
+		e.uvarint(uint64(r))
+	}
+}
+
+func (e *encoder) time(t time.Time) {
+	if t.IsZero() {
+		e.uvarint(0)
+		return
+	}
+
+	e.uvarint(1)
+	e.varint(t.Unix())
+	e.int(t.Nanosecond())
+}
+
+func (e *encoder) word(w Word) {
+	for _, l := range w {
+		e.uvarint(uint64(l))
+	}
+}
+
+func (e *encoder) wordGuess(wg WordGuess) {
+	w := Word{}
+	matches := uint64(0)
+	for i, lg := range wg {
+		w[i] = lg.Letter
+		matches |= uint64(lg.Match&3) << (2 * i)
+	}
+
+	e.word(w)
+	e.uvarint(matches)
+}
+
+func (e *encoder) puzzle(p Puzzle) {
+	e.int(int(p.ActiveRow()))
+	for _, wg := range p.Guesses[:p.ActiveRow()] {
+		e.wordGuess(wg)
+	}
+}
+
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) fail(format string, a ...any) {
+	if d.err == nil {
+		d.err = invalid(format, a...)
+	}
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.fail("truncated or overlong integer")
+		return 0
+	}
+	d.buf = d.buf[n:]
+
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.fail("truncated or overlong integer")
+		return 0
+	}
+	d.buf = d.buf[n:]
+
+	return v
+}
+
+// count reads a length of at most limit.
+func (d *decoder) count(limit int) int {
+	v := d.uvarint()
+	if v > uint64(limit) {
+		d.fail("count %d exceeds %d", v, limit)
+		return 0
+	}
+
+	return int(v)
+}
+
+// int reads a non negative number small enough for every platform.
+func (d *decoder) int() int {
+	return d.count(math.MaxInt32)
+}
+
+func (d *decoder) string() string {
+	n := d.count(codecMaxString)
+	if d.err != nil {
+		return ""
+	}
+	if n > len(d.buf) {
+		d.fail("truncated string")
+		return ""
+	}
+
+	s := string(d.buf[:n])
+	d.buf = d.buf[n:]
+
+	return s
+}
+
+func (d *decoder) rune() rune {
+	v := d.uvarint()
+	if v > utf8.MaxRune {
+		d.fail("invalid letter %d", v)
+		return 0
+	}
+
+	return rune(v)
+}
+
+func (d *decoder) runes(limit int) []rune {
+	rs := []rune{}
+	for range d.count(limit) {
+		rs = append(rs, d.rune())
+	}
+
+	return rs
+}
+
+func (d *decoder) time() time.Time {
+	switch d.uvarint() {
+	case 0:
+		return time.Time{}
+	case 1:
+		sec, nsec := d.varint(), d.uvarint()
+		if nsec >= uint64(time.Second) {
+			d.fail("invalid nanoseconds %d", nsec)
+		}
+		return time.Unix(sec, int64(nsec)).UTC()
+	default:
+		d.fail("invalid time")
+		return time.Time{}
+	}
+}
+
+func (d *decoder) word() Word {
+	w := Word{}
+	for i := range w {
+		w[i] = d.rune()
+	}
+
+	if err := checkWord(w); err != nil && d.err == nil {
+		d.err = err
+	}
+
+	return w
+}
+
+func (d *decoder) wordGuess() WordGuess {
+	w := d.word()
+	matches := d.uvarint()
+	if matches >= 1<<(2*len(w)) {
+		d.fail("invalid matches %b", matches)
+	}
+
+	wg := WordGuess{}
+	for i, l := range w {
+		wg[i] = LetterGuess{Letter: l, Match: Match(matches >> (2 * i) & 3)}
+	}
+
+	if err := checkWordGuess(wg); err != nil && d.err == nil {
+		d.err = err
+	}
+
+	return wg
+}
+
+func (d *decoder) puzzle() Puzzle {
+	rows := []WordGuess{}
+	for range d.count(len(Puzzle{}.Guesses)) {
+		rows = append(rows, d.wordGuess())
+	}
+	if d.err != nil {
+		return Puzzle{}
+	}
+
+	p, err := puzzleFromRows(rows)
+	if err != nil {
+		d.err = err
+	}
+
+	return p
+}
+
+func (d *decoder) status() Status {
+	i := d.count(len(codecStatuses) - 1)
+	return codecStatuses[i]
+}
+
+// MarshalBinary encodes w as its 5 letters.
+func (w Word) MarshalBinary() ([]byte, error) {
+	if err := checkWord(w); err != nil {
+		return nil, err
+	}
+
+	e := encoder{}
+	e.word(w)
+
+	return e.buf, nil
+}
+
+func (w *Word) UnmarshalBinary(b []byte) error {
+	d := decoder{buf: b}
+	out := d.word()
+
+	return d.done(func() { *w = out })
+}
+
+func (wg WordGuess) MarshalBinary() ([]byte, error) {
+	if err := checkWordGuess(wg); err != nil {
+		return nil, err
+	}
+
+	e := encoder{}
+	e.wordGuess(wg)
+
+	return e.buf, nil
+}
+
+func (wg *WordGuess) UnmarshalBinary(b []byte) error {
+	d := decoder{buf: b}
+	out := d.wordGuess()
+
+	return d.done(func() { *wg = out })
+}
+
+func (p Puzzle) MarshalBinary() ([]byte, error) {
+	e := encoder{}
+	e.puzzle(p)
+
+	return e.buf, nil
+}
+
+func (p *Puzzle) UnmarshalBinary(b []byte) error {
+	d := decoder{buf: b}
+	out := d.puzzle()
+
+	return d.done(func() { *p = out })
+}
+
+// done calls set if the whole payload was decoded without an error.
+func (d *decoder) done(set func()) error {
+	if d.err == nil && len(d.buf) > 0 {
+		d.fail("%d trailing bytes", len(d.buf))
+	}
+	if d.err != nil {
+		return d.err
+	}
+
+	set()
+	return nil
+}
+
+func (g GameState) MarshalBinary() ([]byte, error) {
+	e := encoder{buf: []byte{codecMagic}}
+	e.int(CODEC_VERSION)
+
+	e.word(g.activeSolutionWord)
+	e.runes(g.letterHints)
+	e.puzzle(g.lastEvaluatedAttempt)
+
+	flags := uint64(0)
+	if g.easy {
+		flags |= flagEasy
+	}
+	if g.IsMultiBoard() {
+		flags |= flagMultiBoard
+	}
+	if g.survival.Active {
+		flags |= flagSurvival
+	}
+	e.uvarint(flags)
+	e.string(g.challengeID)
+
+	if g.IsMultiBoard() {
+		m := g.multiBoard
+		e.int(len(m.Boards))
+		for _, b := range m.Boards {
+			e.word(b.Solution)
+			e.int(len(b.Rows))
+			for _, wg := range b.Rows {
+				e.wordGuess(wg)
+			}
+		}
+		e.int(len(m.Guesses))
+		for _, w := range m.Guesses {
+			e.word(w)
+		}
+		e.int(m.MaxAttempts)
+	}
+
+	e.int(g.survival.Score)
+	e.int(g.survival.Words)
+	e.int(g.survival.LastPoints)
+
+	e.varint(int64(g.timeLimit))
+	e.time(g.startedAt)
+	e.int(len(g.guessedAt))
+	for _, t := range g.guessedAt {
+		e.time(t)
+	}
+	e.time(g.firstGuessAt)
+
+	e.int(index(codecStatuses, g.Status()))
+	e.int(len(g.transitions))
+	for _, t := range g.transitions {
+		e.int(index(codecMoves, t.Move))
+		e.int(index(codecStatuses, t.From))
+		e.int(index(codecStatuses, t.To))
+		e.time(t.At)
+	}
+
+	return e.buf, nil
+}
+
+func (g *GameState) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 || b[0] != codecMagic {
+		return invalid("not a game state")
+	}
+
+	d := decoder{buf: b[1:]}
+	version := d.int()
+	if d.err != nil {
+		return d.err
+	}
+	if version > CODEC_VERSION {
+		return fmt.Errorf("%w: %d", ErrUnknownCodecVersion, version)
+	}
+
+	var err error
+	for v := version; v < CODEC_VERSION; v++ {
+		migrate, ok := binaryMigrations[v]
+		if !ok {
+			return fmt.Errorf("%w: %d", ErrUnknownCodecVersion, version)
+		}
+
+		d.buf, err = migrate(d.buf)
+		if err != nil {
+			return fmt.Errorf("migrating game state from version %d failed: %w", v, err)
+		}
+	}
+
+	out := GameState{}
+	out.activeSolutionWord = d.word()
+	out.letterHints = d.runes(len(Word{}))
+	out.lastEvaluatedAttempt = d.puzzle()
+
+	flags := d.uvarint()
+	if flags >= flagSurvival<<1 {
+		d.fail("unknown flags %b", flags)
+	}
+	out.easy = flags&flagEasy != 0
+	out.survival.Active = flags&flagSurvival != 0
+	out.challengeID = d.string()
+
+	if flags&flagMultiBoard != 0 {
+		m := MultiBoard{Boards: []Board{}, Guesses: []Word{}}
+		for range d.count(codecMaxBoards) {
+			b := Board{Solution: d.word(), Rows: []WordGuess{}}
+			for range d.count(codecMaxRows) {
+				b.Rows = append(b.Rows, d.wordGuess())
+			}
+			m.Boards = append(m.Boards, b)
+		}
+		for range d.count(codecMaxRows) {
+			m.Guesses = append(m.Guesses, d.word())
+		}
+		m.MaxAttempts = d.count(codecMaxRows)
+		out.multiBoard = m
+	}
+
+	out.survival.Score = d.int()
+	out.survival.Words = d.int()
+	out.survival.LastPoints = d.int()
+
+	out.timeLimit = time.Duration(d.varint())
+	out.startedAt = d.time()
+	if n := d.count(codecMaxRows); n > 0 {
+		out.guessedAt = []time.Time{}
+		for range n {
+			out.guessedAt = append(out.guessedAt, d.time())
+		}
+	}
+	out.firstGuessAt = d.time()
+
+	if status := d.status(); status != STATUS_NOT_STARTED {
+		out.status = status
+	}
+	for range d.count(codecMaxTransitions) {
+		t := Transition{}
+		t.Move = codecMoves[d.count(len(codecMoves)-1)]
+		t.From = d.status()
+		t.To = d.status()
+		t.At = d.time()
+		out.transitions = append(out.transitions, t)
+	}
+
+	err = d.done(func() {})
+	if err != nil {
+		return err
+	}
+
+	err = out.check()
+	if err != nil {
+		return err
+	}
+
+	*g = out
+	return nil
+}
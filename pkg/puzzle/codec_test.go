@@ -0,0 +1,230 @@
+package puzzle
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// codecGames covers every part of a game state the codecs have to keep.
+func codecGames(t testing.TB) map[string]GameState {
+	t.Helper()
+
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	solution := Word{'c', 'r', 'i', 'e', 'd'}
+	tried := EvaluateGuessedWord(Word{'t', 'r', 'i', 'e', 'd'}, solution)
+
+	won := NewChallengeGame("challenge-1", solution)
+	_ = won.AddLetterHint('c')
+	_ = won.Guess(tried, start)
+	_ = won.Guess(EvaluateGuessedWord(solution, solution), start.Add(time.Minute))
+
+	speed := NewChallengeGame("", Word{'b', 'ä', 'r', 'e', 'n'})
+	speed.SetEasy(true)
+	speed.StartSpeed(start, time.Minute)
+	_ = speed.Guess(EvaluateFoldedGuessedWord(Word{'b', 'a', 'r', 'e', 's'}, speed.ActiveSolutionWord(), Word.ToLower), start.Add(10*time.Second))
+	_ = speed.TimeOut(start.Add(time.Minute))
+
+	multi := GameState{activeSolutionWord: solution, letterHints: []rune{}, multiBoard: NewMultiBoard([]Word{solution, {'t', 'r', 'i', 'e', 'd'}})}
+	_ = multi.GuessBoards(solution, Word.ToLower, start)
+
+	survival := NewChallengeGame("", solution)
+	survival.SetSurvival(SurvivalRun{Active: true, Score: 105, Words: 2, LastPoints: 55})
+	_ = survival.Guess(tried, start)
+	_ = survival.Abandon(start.Add(time.Hour))
+
+	return map[string]GameState{
+		"zero":     {},
+		"new":      NewChallengeGame("", solution),
+		"won":      won,
+		"speed":    speed,
+		"multi":    multi,
+		"survival": survival,
+	}
+}
+
+func TestGameState_codecRoundTrip(t *testing.T) {
+	for name, g := range codecGames(t) {
+		t.Run(name, func(t *testing.T) {
+			codecs := map[string]struct {
+				marshal   func(GameState) ([]byte, error)
+				unmarshal func(*GameState, []byte) error
+			}{
+				"json":   {GameState.MarshalJSON, (*GameState).UnmarshalJSON},
+				"binary": {GameState.MarshalBinary, (*GameState).UnmarshalBinary},
+			}
+
+			for codec, c := range codecs {
+				b, err := c.marshal(g)
+				if err != nil {
+					t.Fatalf("%s: marshal error = %s", codec, err)
+				}
+
+				got := GameState{}
+				err = c.unmarshal(&got, b)
+				if err != nil {
+					t.Fatalf("%s: unmarshal error = %s\npayload: %q", codec, err, b)
+				}
+
+				again, _ := c.marshal(got)
+				if !bytes.Equal(b, again) {
+					t.Errorf("%s: re-encoding differs\nfirst:  %q\nsecond: %q", codec, b, again)
+				}
+
+				if got.Status() != g.Status() || got.ActiveSolutionWord() != g.ActiveSolutionWord() ||
+					got.LastEvaluatedAttempt() != g.LastEvaluatedAttempt() || string(got.LetterHints()) != string(g.LetterHints()) ||
+					got.IsTimedOut(time.Time{}) != g.IsTimedOut(time.Time{}) || got.Survival() != g.Survival() ||
+					len(got.MultiBoard().Boards) != len(g.MultiBoard().Boards) || got.IsEasy() != g.IsEasy() || got.ChallengeID() != g.ChallengeID() {
+					t.Errorf("%s: decoded game differs\ngot:  %+v\nwant: %+v", codec, got, g)
+				}
+			}
+		})
+	}
+}
+
+func TestGameState_MarshalJSON_stable(t *testing.T) {
+	g := codecGames(t)["won"]
+
+	b, err := json.Marshal(g)
+	if err != nil {
+		t.Fatalf("Marshal() error = %s", err)
+	}
+
+	want := `{"version":1,"solution":"cried","hints":"c","puzzle":[{"word":"tried","matches":"neeee"},{"word":"cried","matches":"eeeee"}],` +
+		`"challengeId":"challenge-1","firstGuessAt":"2024-06-01T12:00:00Z","status":"won","transitions":[` +
+		`{"move":"guess","from":"not started","to":"in progress","at":"2024-06-01T12:00:00Z"},` +
+		`{"move":"guess","from":"in progress","to":"won","at":"2024-06-01T12:01:00Z"}]}`
+	if string(b) != want {
+		t.Errorf("Marshal() =\n%s\nwant\n%s", b, want)
+	}
+}
+
+func TestGameState_codecVersion(t *testing.T) {
+	g := GameState{}
+
+	err := g.UnmarshalJSON([]byte(`{"version":2}`))
+	if !errors.Is(err, ErrUnknownCodecVersion) {
+		t.Errorf("UnmarshalJSON() of a newer version error = %v, want %s", err, ErrUnknownCodecVersion)
+	}
+
+	err = g.UnmarshalBinary([]byte{codecMagic, 0})
+	if !errors.Is(err, ErrUnknownCodecVersion) {
+		t.Errorf("UnmarshalBinary() of version 0 without migration error = %v, want %s", err, ErrUnknownCodecVersion)
+	}
+
+	t.Run("migration", func(t *testing.T) {
+		b, _ := codecGames(t)["won"].MarshalJSON()
+		old := bytes.Replace(b, []byte(`"version":1,`), []byte(`"version":0,`), 1)
+
+		jsonMigrations[0] = func(raw json.RawMessage) (json.RawMessage, error) {
+			return bytes.Replace(raw, []byte(`"version":0,`), []byte(`"version":1,`), 1), nil
+		}
+		defer delete(jsonMigrations, 0)
+
+		err := g.UnmarshalJSON(old)
+		if err != nil || g.Status() != STATUS_WON {
+			t.Errorf("UnmarshalJSON() of a migrated payload = %s, %v, want a won game", g.Status(), err)
+		}
+	})
+}
+
+func TestWordGuess_codec(t *testing.T) {
+	solution := Word{'g', 'r', 'ü', 'n', 'e'}
+	wg := EvaluateGuessedWord(Word{'g', 'r', 'u', 'n', 'e'}, solution)
+
+	b, err := wg.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %s", err)
+	}
+	got := WordGuess{}
+	if err := got.UnmarshalBinary(b); err != nil || got != wg {
+		t.Errorf("UnmarshalBinary() = %v, %v, want %v", got, err, wg)
+	}
+
+	b, err = json.Marshal(wg)
+	if err != nil || string(b) != `{"word":"grune","matches":"eenee"}` {
+		t.Errorf("json.Marshal() = %s, %v", b, err)
+	}
+
+	for _, payload := range []string{
+		`{"word":"grune","matches":"eene"}`,
+		`{"word":"grune","matches":"eenex"}`,
+		`{"word":"gru","matches":"eee"}`,
+		`{"word":"","matches":"eeeee"}`,
+	} {
+		if err := json.Unmarshal([]byte(payload), &got); !errors.Is(err, ErrInvalidEncoding) {
+			t.Errorf("json.Unmarshal(%s) error = %v, want %s", payload, err, ErrInvalidEncoding)
+		}
+	}
+
+	if _, err := (Word{'a', 0, 'c', 'd', 'e'}).MarshalJSON(); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("MarshalJSON() of an incomplete word error = %v, want %s", err, ErrInvalidEncoding)
+	}
+}
+
+func TestPuzzle_codec(t *testing.T) {
+	solution := Word{'c', 'r', 'i', 'e', 'd'}
+	p := Puzzle{Guesses: [6]WordGuess{EvaluateGuessedWord(Word{'t', 'r', 'i', 'e', 'd'}, solution), EvaluateGuessedWord(solution, solution)}}
+
+	b, _ := p.MarshalBinary()
+	got := Puzzle{}
+	if err := got.UnmarshalBinary(b); err != nil || got != p {
+		t.Errorf("UnmarshalBinary() = %v, %v, want %v", got, err, p)
+	}
+
+	if err := got.UnmarshalBinary(append(b, 0)); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("UnmarshalBinary() with trailing bytes error = %v, want %s", err, ErrInvalidEncoding)
+	}
+	if err := json.Unmarshal([]byte(`[{"word":"","matches":""}]`), &got); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("json.Unmarshal() of an empty row error = %v, want %s", err, ErrInvalidEncoding)
+	}
+}
+
+// fuzzCodec checks that a decoder never panics on corrupted payloads and
+// that everything it accepts is a game the encoder reproduces.
+func fuzzCodec(f *testing.F, marshal func(GameState) ([]byte, error), unmarshal func(*GameState, []byte) error) {
+	for _, g := range codecGames(f) {
+		b, err := marshal(g)
+		if err != nil {
+			f.Fatalf("marshal error = %s", err)
+		}
+
+		f.Add(b)
+		f.Add(b[:len(b)/2])
+		flipped := bytes.Clone(b)
+		flipped[len(flipped)/3] ^= 0xff
+		f.Add(flipped)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		g := GameState{}
+		if err := unmarshal(&g, b); err != nil {
+			return
+		}
+
+		again, err := marshal(g)
+		if err != nil {
+			t.Fatalf("marshal of a decoded game error = %s", err)
+		}
+
+		got := GameState{}
+		if err := unmarshal(&got, again); err != nil {
+			t.Fatalf("unmarshal of a re-encoded game error = %s\npayload: %q", err, again)
+		}
+
+		// the decoded game must be playable
+		_ = got.IsTimedOut(time.Time{})
+		_ = got.Can(MOVE_GUESS)
+		_ = got.MultiBoard().RemainingAttempts()
+	})
+}
+
+func FuzzGameState_UnmarshalJSON(f *testing.F) {
+	fuzzCodec(f, GameState.MarshalJSON, (*GameState).UnmarshalJSON)
+}
+
+func FuzzGameState_UnmarshalBinary(f *testing.F) {
+	fuzzCodec(f, GameState.MarshalBinary, (*GameState).UnmarshalBinary)
+}
@@ -0,0 +1,271 @@
+package puzzle
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pandorasNox/lettr/pkg/language"
+)
+
+type Difficulty string
+
+const (
+	DIFFICULTY_ANY    Difficulty = ""
+	DIFFICULTY_EASY   Difficulty = "easy"
+	DIFFICULTY_MEDIUM Difficulty = "medium"
+	DIFFICULTY_HARD   Difficulty = "hard"
+)
+
+// Difficulties are the difficulties players may pick, from easy to hard.
+var Difficulties = []Difficulty{DIFFICULTY_EASY, DIFFICULTY_MEDIUM, DIFFICULTY_HARD}
+
+func ParseDifficulty(v string) (Difficulty, error) {
+	d := Difficulty(strings.ToLower(v))
+	if d != DIFFICULTY_ANY && !slices.Contains(Difficulties, d) {
+		return DIFFICULTY_ANY, fmt.Errorf("unknown difficulty '%s'", v)
+	}
+
+	return d, nil
+}
+
+// Pick narrows down the solutions RandomPick draws from, the zero value
+// draws from the default solutions regardless of their difficulty.
+type Pick struct {
+	Collection WordCollection
+	Difficulty Difficulty
+}
+
+const (
+	// WIN_RATE_MIN_GAMES games have to be finished on a word before its win
+	// rate counts towards its difficulty
+	WIN_RATE_MIN_GAMES = 20
+
+	// weights of the difficulty parts, see WordDatabase.Difficulty
+	DIFFICULTY_WEIGHT_FREQUENCY  = 0.5
+	DIFFICULTY_WEIGHT_LETTERS    = 0.3
+	DIFFICULTY_WEIGHT_DUPLICATES = 0.2
+	DIFFICULTY_WEIGHT_WIN_RATE   = 0.4
+)
+
+// WordStats counts the games finished on every solution, it is safe for
+// concurrent use.
+type WordStats struct {
+	mutex  sync.Mutex
+	played map[language.Language]map[Word]int
+	won    map[language.Language]map[Word]int
+}
+
+func NewWordStats() *WordStats {
+	return &WordStats{
+		played: map[language.Language]map[Word]int{},
+		won:    map[language.Language]map[Word]int{},
+	}
+}
+
+func (ws *WordStats) Record(l language.Language, w Word, won bool) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	if ws.played[l] == nil {
+		ws.played[l] = map[Word]int{}
+		ws.won[l] = map[Word]int{}
+	}
+
+	w = w.ToLower()
+	ws.played[l][w]++
+	if won {
+		ws.won[l][w]++
+	}
+}
+
+// WinRate returns the share of games won on w, false for words played too
+// rarely to tell.
+func (ws *WordStats) WinRate(l language.Language, w Word) (float64, bool) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	w = w.ToLower()
+	played := ws.played[l][w]
+	if played < WIN_RATE_MIN_GAMES {
+		return 0, false
+	}
+
+	return float64(ws.won[l][w]) / float64(played), true
+}
+
+// RecordResult counts a finished game on the solutions towards their win
+// rates, it does nothing without Stats.
+func (wdb WordDatabase) RecordResult(l language.Language, solutions []Word, won bool) {
+	if wdb.Stats == nil {
+		return
+	}
+
+	for _, w := range solutions {
+		wdb.Stats.Record(l, w, won)
+		wdb.rescore(l, w.ToLower())
+	}
+}
+
+// Difficulty scores w of language l from 0 (easy) to 1 (hard). Rare words,
+// rare letters and duplicate letters make a word harder, once enough games
+// were played on it its win rate counts as well.
+func (wdb WordDatabase) Difficulty(l language.Language, w Word) float64 {
+	w = w.ToLower()
+	if wdb.index == nil {
+		return wdb.difficulty(l, w, wdb.letterCommonness(l))
+	}
+
+	wdb.index.mutex.RLock()
+	score, ok := wdb.index.scores[l][w]
+	wdb.index.mutex.RUnlock()
+	if ok {
+		return score
+	}
+
+	return wdb.difficulty(l, w, wdb.index.commonness[l])
+}
+
+// rescore updates the indexed difficulty of w after a recorded result and
+// moves it within the collections holding it.
+func (wdb WordDatabase) rescore(l language.Language, w Word) {
+	if wdb.index == nil {
+		return
+	}
+
+	score := wdb.difficulty(l, w, wdb.index.commonness[l])
+
+	wdb.index.mutex.Lock()
+	defer wdb.index.mutex.Unlock()
+
+	scores := wdb.index.scores[l]
+	if old, ok := scores[w]; !ok || old == score {
+		return
+	}
+
+	for c, ordered := range wdb.index.byDifficulty[l] {
+		i := slices.Index(ordered, w)
+		if i == -1 {
+			continue
+		}
+
+		// readers may still hold the old slice
+		moved := slices.Delete(slices.Clone(ordered), i, i+1)
+		scores[w] = score
+		j, _ := slices.BinarySearchFunc(moved, w, func(a, b Word) int {
+			return compareDifficulty(a, b, scores)
+		})
+		wdb.index.byDifficulty[l][c] = slices.Insert(moved, j, w)
+	}
+	scores[w] = score
+}
+
+func (wdb WordDatabase) difficulty(l language.Language, w Word, commonness map[rune]float64) float64 {
+	w = w.ToLower()
+
+	// words missing from the frequency ordered lists are rarer than all of
+	// the ranked ones
+	frequency := 1.0
+	if rank, ok := wdb.Ranks[l][w]; ok {
+		frequency = float64(rank) / float64(len(wdb.Ranks[l]))
+	}
+
+	letters := 0.0
+	for _, r := range w {
+		letters += 1 - commonness[r]
+	}
+	letters /= float64(len(w))
+
+	duplicates := 0.0
+	if w.HasDublicateLetters() {
+		duplicates = 1
+	}
+
+	score := DIFFICULTY_WEIGHT_FREQUENCY*frequency + DIFFICULTY_WEIGHT_LETTERS*letters + DIFFICULTY_WEIGHT_DUPLICATES*duplicates
+
+	if wdb.Stats != nil {
+		if rate, ok := wdb.Stats.WinRate(l, w); ok {
+			score = (1-DIFFICULTY_WEIGHT_WIN_RATE)*score + DIFFICULTY_WEIGHT_WIN_RATE*(1-rate)
+		}
+	}
+
+	return score
+}
+
+// letterCommonness returns how often every letter occurs in the words of
+// language l, relative to the most common letter.
+func (wdb WordDatabase) letterCommonness(l language.Language) map[rune]float64 {
+	counts := map[rune]int{}
+	most := 0
+	for _, c := range guessableCollections {
+		for w := range wdb.Db[l][c] {
+			for _, r := range w {
+				counts[r]++
+				most = max(most, counts[r])
+			}
+		}
+	}
+
+	commonness := make(map[rune]float64, len(counts))
+	for r, n := range counts {
+		commonness[r] = float64(n) / float64(most)
+	}
+
+	return commonness
+}
+
+// byDifficulty returns the third of the words of the collection c of
+// language l matching d, the easiest third for DIFFICULTY_EASY. All words
+// are returned for DIFFICULTY_ANY and collections too small to split. The
+// slice must not be changed.
+func (wdb WordDatabase) byDifficulty(l language.Language, d Difficulty, c WordCollection) []Word {
+	band := slices.Index(Difficulties, d)
+	if band == -1 || len(wdb.Db[l][c]) < len(Difficulties) {
+		return wdb.sortedWords(l, c)
+	}
+
+	var ordered []Word
+	if wdb.index != nil {
+		wdb.index.mutex.RLock()
+		ordered = wdb.index.byDifficulty[l][c]
+		wdb.index.mutex.RUnlock()
+	}
+	if ordered == nil {
+		commonness := wdb.letterCommonness(l)
+		scores := map[Word]float64{}
+		for w := range wdb.Db[l][c] {
+			scores[w] = wdb.difficulty(l, w, commonness)
+		}
+		ordered = orderByDifficulty(sortedWords(wdb.Db[l][c]), scores)
+	}
+
+	size := len(ordered) / len(Difficulties)
+	from := band * size
+	to := from + size
+	if band == len(Difficulties)-1 {
+		to = len(ordered)
+	}
+
+	return ordered[from:to]
+}
+
+// orderByDifficulty returns the words sorted from easy to hard by their
+// scores, words of the same score keep their alphabetical order so picks
+// stay reproducible for a seeded rnd.
+func orderByDifficulty(words []Word, scores map[Word]float64) []Word {
+	ordered := slices.Clone(words)
+	slices.SortFunc(ordered, func(a, b Word) int {
+		return compareDifficulty(a, b, scores)
+	})
+
+	return ordered
+}
+
+func compareDifficulty(a, b Word, scores map[Word]float64) int {
+	return cmp.Or(
+		cmp.Compare(scores[a], scores[b]),
+		strings.Compare(a.String(), b.String()),
+	)
+}
@@ -0,0 +1,143 @@
+package puzzle
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/random"
+)
+
+func TestParseDifficulty(t *testing.T) {
+	tests := []struct {
+		v       string
+		want    Difficulty
+		wantErr bool
+	}{
+		{"", DIFFICULTY_ANY, false},
+		{"easy", DIFFICULTY_EASY, false},
+		{"Hard", DIFFICULTY_HARD, false},
+		{"insane", DIFFICULTY_ANY, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.v, func(t *testing.T) {
+			got, err := ParseDifficulty(tt.v)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseDifficulty() = %v, %v; want %v, wantErr %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func difficultyTestDatabase(t *testing.T) WordDatabase {
+	t.Helper()
+
+	wdb := WordDatabase{}
+	err := wdb.Init(fstest.MapFS{
+		"corpus.txt": {Data: []byte("// corpus | order: frequency\nthere\nstone\nrates\ntiger\nfuzzy\n")},
+		"all.txt":    {Data: []byte("# metadata\njazzy\n")},
+	}, map[language.Language]map[WordCollection][]string{
+		language.LANG_EN: {
+			WC_ALL:    {"all.txt", "corpus.txt"},
+			WC_COMMON: {"corpus.txt"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Init() error = %s", err)
+	}
+
+	return wdb
+}
+
+func TestWordDatabase_Difficulty(t *testing.T) {
+	wdb := difficultyTestDatabase(t)
+
+	if rank := wdb.Ranks[language.LANG_EN][Word{'s', 't', 'o', 'n', 'e'}]; rank != 2 {
+		t.Errorf("rank of stone = %d, want 2", rank)
+	}
+	if _, ok := wdb.Ranks[language.LANG_EN][Word{'j', 'a', 'z', 'z', 'y'}]; ok {
+		t.Errorf("words of lists not ordered by frequency must not be ranked")
+	}
+
+	stone := wdb.Difficulty(language.LANG_EN, Word{'s', 't', 'o', 'n', 'e'})
+	tiger := wdb.Difficulty(language.LANG_EN, Word{'t', 'i', 'g', 'e', 'r'})
+	fuzzy := wdb.Difficulty(language.LANG_EN, Word{'f', 'u', 'z', 'z', 'y'})
+	jazzy := wdb.Difficulty(language.LANG_EN, Word{'J', 'A', 'Z', 'Z', 'Y'})
+	if !(stone < tiger && tiger < fuzzy && stone < jazzy) {
+		t.Errorf("Difficulty() stone=%.2f tiger=%.2f fuzzy=%.2f jazzy=%.2f, want frequent words with common letters easiest", stone, tiger, fuzzy, jazzy)
+	}
+	if stone < 0 || jazzy > 1 {
+		t.Errorf("Difficulty() out of [0, 1], stone=%.2f jazzy=%.2f", stone, jazzy)
+	}
+
+	t.Run("win rate", func(t *testing.T) {
+		wdb.Stats = NewWordStats()
+		w := Word{'s', 't', 'o', 'n', 'e'}
+
+		for range WIN_RATE_MIN_GAMES - 1 {
+			wdb.RecordResult(language.LANG_EN, []Word{w}, false)
+		}
+		if got := wdb.Difficulty(language.LANG_EN, w); got != stone {
+			t.Errorf("Difficulty() = %.2f after too few games, want %.2f", got, stone)
+		}
+
+		wdb.RecordResult(language.LANG_EN, []Word{w}, false)
+		if got := wdb.Difficulty(language.LANG_EN, w); got <= stone {
+			t.Errorf("Difficulty() = %.2f for a word never solved, want more than %.2f", got, stone)
+		}
+	})
+}
+
+func TestWordDatabase_RandomPick_difficulty(t *testing.T) {
+	wdb := difficultyTestDatabase(t)
+	rnd := random.New(1)
+
+	// the five solutions split into thirds of one, one and three words
+	easiest := Word{'t', 'h', 'e', 'r', 'e'}
+	for range 10 {
+		w, err := wdb.RandomPick(rnd, language.LANG_EN, Pick{Difficulty: DIFFICULTY_EASY}, []Word{}, 0)
+		if err != nil {
+			t.Fatalf("RandomPick() error = %s", err)
+		}
+		if w != easiest && w != (Word{'s', 't', 'o', 'n', 'e'}) {
+			t.Fatalf("RandomPick() of easy words = %s", w)
+		}
+
+		w, err = wdb.RandomPick(rnd, language.LANG_EN, Pick{Difficulty: DIFFICULTY_HARD}, []Word{}, 0)
+		if err != nil {
+			t.Fatalf("RandomPick() error = %s", err)
+		}
+		if w == easiest {
+			t.Fatalf("RandomPick() of hard words = %s", w)
+		}
+	}
+
+	// used up difficulties fall back to any difficulty
+	hard := wdb.byDifficulty(language.LANG_EN, DIFFICULTY_HARD, WC_COMMON)
+	w := wdb.RandomPickWithFallback(rnd, language.LANG_EN, Pick{Difficulty: DIFFICULTY_HARD}, hard, 0)
+	if w == (Word{'r', 'o', 'a', 't', 'e'}) {
+		t.Errorf("RandomPickWithFallback() = %s, want a solution of another difficulty", w)
+	}
+}
+
+func TestWordDatabase_RecordResult_reordersDifficulties(t *testing.T) {
+	wdb := difficultyTestDatabase(t)
+	wdb.Stats = NewWordStats()
+
+	stone := Word{'s', 't', 'o', 'n', 'e'}
+	if easy := wdb.byDifficulty(language.LANG_EN, DIFFICULTY_EASY, WC_COMMON); !slices.Equal(easy, []Word{stone}) {
+		t.Fatalf("byDifficulty() of easy words = %v, want [stone]", easy)
+	}
+
+	for range WIN_RATE_MIN_GAMES {
+		wdb.RecordResult(language.LANG_EN, []Word{stone}, false)
+	}
+
+	if easy := wdb.byDifficulty(language.LANG_EN, DIFFICULTY_EASY, WC_COMMON); slices.Contains(easy, stone) {
+		t.Errorf("byDifficulty() of easy words = %v after losing every game on stone", easy)
+	}
+	if all := wdb.byDifficulty(language.LANG_EN, DIFFICULTY_ANY, WC_COMMON); len(all) != 5 {
+		t.Errorf("byDifficulty() of all words = %v, want all five", all)
+	}
+}
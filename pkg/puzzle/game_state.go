@@ -2,18 +2,41 @@ package puzzle
 
 import (
 	"slices"
+	"time"
 
 	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/random"
 )
 
 type GameState struct {
 	activeSolutionWord   Word
 	letterHints          []rune
 	lastEvaluatedAttempt Puzzle
+	easy                 bool
+	challengeID          string
+	// multiBoard is only set for games on several boards, activeSolutionWord
+	// is the solution of the first board then
+	multiBoard MultiBoard
+	survival   SurvivalRun
+
+	// speed mode, a zero timeLimit means the game is not timed
+	timeLimit time.Duration
+	startedAt time.Time
+	guessedAt []time.Time
+
+	// history, firstGuessAt stands in as start time of games without timer
+	firstGuessAt time.Time
+
+	// status is only changed by the moves in state.go, the zero value is
+	// STATUS_NOT_STARTED
+	status      Status
+	transitions []Transition
 }
 
-func NewGame(l language.Language, wdb WordDatabase, excludeWords []Word) GameState {
-	newSolutionWord := wdb.RandomPickWithFallback(l, excludeWords, 0)
+// NewGame starts a game on a solution picked as asked by p, see
+// WordDatabase.RandomPick.
+func NewGame(rnd random.Rand, l language.Language, p Pick, wdb WordDatabase, excludeWords []Word) GameState {
+	newSolutionWord := wdb.RandomPickWithFallback(rnd, l, p, excludeWords, 0)
 
 	return GameState{
 		activeSolutionWord:   newSolutionWord,
@@ -22,6 +45,35 @@ func NewGame(l language.Language, wdb WordDatabase, excludeWords []Word) GameSta
 	}
 }
 
+// NewMultiBoardGame starts a game on n distinct solution words sharing one
+// sequence of guesses.
+func NewMultiBoardGame(rnd random.Rand, l language.Language, p Pick, wdb WordDatabase, excludeWords []Word, n int) GameState {
+	exclude := slices.Clone(excludeWords)
+	solutions := []Word{}
+	for range n {
+		w := wdb.RandomPickWithFallback(rnd, l, p, exclude, 0)
+		solutions = append(solutions, w)
+		exclude = append(exclude, w)
+	}
+
+	return GameState{
+		activeSolutionWord:   solutions[0],
+		letterHints:          []rune{},
+		lastEvaluatedAttempt: Puzzle{},
+		multiBoard:           NewMultiBoard(solutions),
+	}
+}
+
+// NewChallengeGame starts a game on a solution picked by another player.
+func NewChallengeGame(challengeID string, solution Word) GameState {
+	return GameState{
+		activeSolutionWord:   solution,
+		letterHints:          []rune{},
+		lastEvaluatedAttempt: Puzzle{},
+		challengeID:          challengeID,
+	}
+}
+
 func (g *GameState) ActiveSolutionWord() Word {
 	return g.activeSolutionWord
 }
@@ -34,14 +86,149 @@ func (g *GameState) LetterHints() []rune {
 	return slices.Clone(g.letterHints)
 }
 
-func (g *GameState) AddLetterHint(l rune) {
-	g.letterHints = append(g.letterHints, l)
+// AddLetterHint reveals letter l, hints are only given while the game is
+// not over.
+func (g *GameState) AddLetterHint(l rune) error {
+	if err := g.Can(MOVE_HINT); err != nil {
+		return err
+	}
+
+	g.letterHints = append(slices.Clip(g.letterHints), l)
+	return nil
 }
 
 func (g *GameState) LastEvaluatedAttempt() Puzzle {
 	return g.lastEvaluatedAttempt
 }
 
-func (g *GameState) SetLastEvaluatedAttempt(p Puzzle) {
-	g.lastEvaluatedAttempt = p
+// IsEasy reports whether guesses are compared ignoring diacritics.
+func (g *GameState) IsEasy() bool {
+	return g.easy
+}
+
+func (g *GameState) SetEasy(easy bool) {
+	g.easy = easy
+}
+
+// ChallengeID is empty unless the game was started from a challenge.
+func (g *GameState) ChallengeID() string {
+	return g.challengeID
+}
+
+// StartSpeed turns the game into a speed game started at now, it is lost
+// once limit has passed without solving it.
+func (g *GameState) StartSpeed(now time.Time, limit time.Duration) {
+	g.timeLimit = limit
+	g.startedAt = now
+	g.guessedAt = []time.Time{}
+}
+
+func (g *GameState) IsSpeed() bool {
+	return g.timeLimit > 0
+}
+
+func (g *GameState) StartedAt() time.Time {
+	return g.startedAt
+}
+
+func (g *GameState) Deadline() time.Time {
+	return g.startedAt.Add(g.timeLimit)
+}
+
+// Remaining returns the time left to solve a speed game, never below zero.
+func (g *GameState) Remaining(now time.Time) time.Duration {
+	if !g.IsSpeed() {
+		return 0
+	}
+
+	return max(g.Deadline().Sub(now), 0)
+}
+
+// RecordGuess stores the server time an accepted guess arrived at.
+func (g *GameState) RecordGuess(now time.Time) {
+	if g.firstGuessAt.IsZero() {
+		g.firstGuessAt = now
+	}
+
+	if !g.IsSpeed() {
+		return
+	}
+
+	g.guessedAt = append(g.guessedAt, now)
+}
+
+// GuessTimes returns how long the player took for every accepted guess.
+func (g *GameState) GuessTimes() []time.Duration {
+	ds := []time.Duration{}
+	last := g.startedAt
+	for _, t := range g.guessedAt {
+		ds = append(ds, t.Sub(last))
+		last = t
+	}
+
+	return ds
+}
+
+// IsTimedOut reports whether a speed game was lost on time or its deadline
+// has passed while it is still running, which counts as a loss as well.
+func (g *GameState) IsTimedOut(now time.Time) bool {
+	if !g.IsSpeed() {
+		return false
+	}
+
+	if g.Status().IsOver() {
+		last := g.transitions[len(g.transitions)-1]
+		return last.Move == MOVE_TIME_OUT
+	}
+
+	return !now.Before(g.Deadline())
+}
+
+// SolveTime returns the time from start to the solving guess of a speed game.
+func (g *GameState) SolveTime() (time.Duration, bool) {
+	if !g.IsSpeed() || !g.IsSolved() || len(g.guessedAt) == 0 {
+		return 0, false
+	}
+
+	return g.guessedAt[len(g.guessedAt)-1].Sub(g.startedAt), true
+}
+
+func (g *GameState) IsMultiBoard() bool {
+	return len(g.multiBoard.Boards) > 0
+}
+
+func (g *GameState) MultiBoard() MultiBoard {
+	return g.multiBoard
+}
+
+// SolutionWords returns the solutions of all boards.
+func (g *GameState) SolutionWords() []Word {
+	if g.IsMultiBoard() {
+		return g.multiBoard.Solutions()
+	}
+
+	return []Word{g.activeSolutionWord}
+}
+
+// IsSolved covers single and multi board games.
+func (g *GameState) IsSolved() bool {
+	return g.Status() == STATUS_WON
+}
+
+// IsLoose covers single and multi board games, including speed games lost on
+// time and abandoned games.
+func (g *GameState) IsLoose() bool {
+	return g.Status() == STATUS_LOST || g.Status() == STATUS_ABANDONED
+}
+
+func (g *GameState) IsSurvival() bool {
+	return g.survival.Active
+}
+
+func (g *GameState) Survival() SurvivalRun {
+	return g.survival
+}
+
+func (g *GameState) SetSurvival(r SurvivalRun) {
+	g.survival = r
 }
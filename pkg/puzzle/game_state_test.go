@@ -0,0 +1,72 @@
+package puzzle
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/clock"
+)
+
+func TestGameState_speed(t *testing.T) {
+	solution := Word{'c', 'r', 'i', 'e', 'd'}
+	c := clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	g := GameState{activeSolutionWord: solution}
+	if g.IsSpeed() || g.IsTimedOut(c.Now().Add(time.Hour)) {
+		t.Fatalf("untimed game must never time out")
+	}
+
+	g.StartSpeed(c.Now(), time.Minute)
+
+	c.Advance(10 * time.Second)
+	if err := g.Guess(EvaluateGuessedWord(Word{'t', 'r', 'i', 'e', 'd'}, solution), c.Now()); err != nil {
+		t.Fatalf("Guess() error = %s", err)
+	}
+
+	if got := g.Remaining(c.Now()); got != 50*time.Second {
+		t.Errorf("Remaining() = %s, want %s", got, 50*time.Second)
+	}
+	if _, ok := g.SolveTime(); ok {
+		t.Errorf("SolveTime() ok for unsolved game")
+	}
+
+	t.Run("solved in time", func(t *testing.T) {
+		g := g
+		c := clock.NewFake(c.Now())
+
+		c.Advance(15 * time.Second)
+		if err := g.Guess(EvaluateGuessedWord(solution, solution), c.Now()); err != nil {
+			t.Fatalf("Guess() error = %s", err)
+		}
+
+		if got, ok := g.SolveTime(); !ok || got != 25*time.Second {
+			t.Errorf("SolveTime() = %s, %t, want %s", got, ok, 25*time.Second)
+		}
+		if want := []time.Duration{10 * time.Second, 15 * time.Second}; !reflect.DeepEqual(g.GuessTimes(), want) {
+			t.Errorf("GuessTimes() = %v, want %v", g.GuessTimes(), want)
+		}
+
+		c.Advance(time.Hour)
+		if g.IsTimedOut(c.Now()) {
+			t.Errorf("IsTimedOut() = true for a solved game")
+		}
+	})
+
+	t.Run("timed out", func(t *testing.T) {
+		c := clock.NewFake(c.Now())
+
+		c.Advance(49 * time.Second)
+		if g.IsTimedOut(c.Now()) {
+			t.Errorf("IsTimedOut() = true before the deadline")
+		}
+
+		c.Advance(time.Second)
+		if !g.IsTimedOut(c.Now()) {
+			t.Errorf("IsTimedOut() = false at the deadline")
+		}
+		if got := g.Remaining(c.Now()); got != 0 {
+			t.Errorf("Remaining() = %s, want 0", got)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package puzzle
+
+import (
+	"slices"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/language"
+)
+
+type Outcome string
+
+const (
+	OUTCOME_WON       Outcome = "won"
+	OUTCOME_LOST      Outcome = "lost"
+	OUTCOME_ABANDONED Outcome = "abandoned"
+)
+
+// GameRecord is a finished game as kept in the history of a player. Single
+// board games have one board, Boards holds the evaluated rows of every board
+// in the order they were guessed.
+type GameRecord struct {
+	Language   language.Language
+	Solutions  []Word
+	Boards     [][]WordGuess
+	Hints      []rune
+	Outcome    Outcome
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// Record returns the game as finished at now. Games lost on time or
+// abandoned are recorded with the rows guessed until then.
+func (g *GameState) Record(l language.Language, now time.Time) GameRecord {
+	r := GameRecord{
+		Language:   l,
+		Solutions:  g.SolutionWords(),
+		Boards:     [][]WordGuess{},
+		Hints:      slices.Clone(g.letterHints),
+		Outcome:    OUTCOME_LOST,
+		StartedAt:  g.startedAt,
+		FinishedAt: now,
+	}
+
+	switch g.Status() {
+	case STATUS_WON:
+		r.Outcome = OUTCOME_WON
+	case STATUS_ABANDONED:
+		r.Outcome = OUTCOME_ABANDONED
+	}
+
+	if r.StartedAt.IsZero() {
+		r.StartedAt = g.firstGuessAt
+	}
+
+	if g.IsMultiBoard() {
+		for _, b := range g.multiBoard.Boards {
+			r.Boards = append(r.Boards, slices.Clone(b.Rows))
+		}
+
+		return r
+	}
+
+	rows := []WordGuess{}
+	for _, wg := range g.lastEvaluatedAttempt.Guesses {
+		if wg.isFilled() {
+			rows = append(rows, wg)
+		}
+	}
+	r.Boards = append(r.Boards, rows)
+
+	return r
+}
+
+// Rows returns the number of guesses taken, multi board games share their
+// guesses, so it is the longest board.
+func (r GameRecord) Rows() int {
+	n := 0
+	for _, rows := range r.Boards {
+		n = max(n, len(rows))
+	}
+
+	return n
+}
+
+// IsSame reports whether both records are the same game, e.g. when the
+// histories of two devices are merged.
+func (r GameRecord) IsSame(o GameRecord) bool {
+	return r.FinishedAt.Equal(o.FinishedAt) && slices.Equal(r.Solutions, o.Solutions)
+}
+
+func (r GameRecord) IsWon() bool {
+	return r.Outcome == OUTCOME_WON
+}
+
+func (r GameRecord) IsAbandoned() bool {
+	return r.Outcome == OUTCOME_ABANDONED
+}
+
+// Replay returns board i as it looked after the first row guesses, padded
+// with empty rows up to size.
+func (r GameRecord) Replay(i int, row int, size int) []WordGuess {
+	rows := r.Boards[i]
+	grid := make([]WordGuess, max(size, len(rows)))
+	copy(grid, rows[:min(row, len(rows))])
+
+	return grid
+}
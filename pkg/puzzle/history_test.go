@@ -0,0 +1,47 @@
+package puzzle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/language"
+)
+
+func TestGameState_Record(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	solution := Word{'c', 'r', 'i', 'e', 'd'}
+	tried := EvaluateGuessedWord(Word{'t', 'r', 'i', 'e', 'd'}, solution)
+	cried := EvaluateGuessedWord(solution, solution)
+
+	g := NewChallengeGame("", solution)
+	_ = g.AddLetterHint('c')
+	_ = g.Guess(tried, start)
+	_ = g.Guess(cried, start.Add(time.Minute))
+
+	r := g.Record(language.LANG_EN, start.Add(time.Minute))
+	if !r.IsWon() || r.Rows() != 2 || !r.StartedAt.Equal(start) || string(r.Hints) != "c" {
+		t.Errorf("Record() = %+v, want a game won in 2 rows with hint 'c' started at %s", r, start)
+	}
+
+	replay := r.Replay(0, 1, 6)
+	if len(replay) != 6 || replay[0] != tried || replay[1] != (WordGuess{}) {
+		t.Errorf("Replay() after one row = %v, want the first guess only", replay)
+	}
+	if replay := r.Replay(0, 10, 6); replay[1] != cried {
+		t.Errorf("Replay() beyond the last row = %v, want all guesses", replay)
+	}
+}
+
+func TestGameState_Record_multiBoard(t *testing.T) {
+	solutions := []Word{{'c', 'r', 'i', 'e', 'd'}, {'t', 'r', 'i', 'e', 'd'}}
+
+	g := GameState{activeSolutionWord: solutions[0], multiBoard: NewMultiBoard(solutions)}
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	_ = g.GuessBoards(solutions[0], Word.ToLower, now)
+	_ = g.GuessBoards(Word{'f', 'r', 'i', 'e', 'd'}, Word.ToLower, now)
+
+	r := g.Record(language.LANG_EN, now)
+	if r.IsWon() || len(r.Boards) != 2 || len(r.Boards[0]) != 1 || len(r.Boards[1]) != 2 || r.Rows() != 2 {
+		t.Errorf("Record() = %+v, want an unsolved game with one row on the first and two on the second board", r)
+	}
+}
@@ -0,0 +1,117 @@
+package puzzle
+
+import (
+	"errors"
+	"slices"
+)
+
+var ErrMultiBoardOver = errors.New("multi board game is over")
+
+// MultiBoardModes are the supported numbers of simultaneous boards.
+var MultiBoardModes = []int{2, 4}
+
+// MultiBoardAttempts returns the guess budget for a game on n boards, every
+// additional board grants one more guess (7 for two, 9 for four boards).
+func MultiBoardAttempts(n int) int {
+	return 5 + n
+}
+
+// Board is one solution word of a MultiBoard together with the evaluated
+// guesses up to the one solving it.
+type Board struct {
+	Solution Word        `json:"solution"`
+	Rows     []WordGuess `json:"rows"`
+}
+
+func (b Board) IsSolved() bool {
+	return len(b.Rows) > 0 && b.Rows[len(b.Rows)-1].isSolved()
+}
+
+// MultiBoard is a game on several solution words at once. All boards share
+// one sequence of guesses, each guess is evaluated against every board that
+// is not solved yet.
+type MultiBoard struct {
+	Boards      []Board `json:"boards"`
+	Guesses     []Word  `json:"guesses"`
+	MaxAttempts int     `json:"maxAttempts"`
+}
+
+func NewMultiBoard(solutions []Word) MultiBoard {
+	boards := []Board{}
+	for _, s := range solutions {
+		boards = append(boards, Board{Solution: s, Rows: []WordGuess{}})
+	}
+
+	return MultiBoard{Boards: boards, Guesses: []Word{}, MaxAttempts: MultiBoardAttempts(len(solutions))}
+}
+
+// Guess evaluates w against all unsolved boards, fold is applied to guess
+// and solutions before comparing them (see EvaluateFoldedGuessedWord).
+func (m *MultiBoard) Guess(w Word, fold func(Word) Word) error {
+	if m.IsSolved() || m.IsLoose() {
+		return ErrMultiBoardOver
+	}
+
+	// game states are passed around by value, never write into shared arrays
+	m.Guesses = append(slices.Clip(m.Guesses), w)
+	m.Boards = slices.Clone(m.Boards)
+	for i, b := range m.Boards {
+		if b.IsSolved() {
+			continue
+		}
+
+		m.Boards[i].Rows = append(slices.Clip(b.Rows), EvaluateFoldedGuessedWord(w, b.Solution, fold))
+	}
+
+	return nil
+}
+
+func (m MultiBoard) IsSolved() bool {
+	for _, b := range m.Boards {
+		if !b.IsSolved() {
+			return false
+		}
+	}
+
+	return len(m.Boards) > 0
+}
+
+func (m MultiBoard) IsLoose() bool {
+	return !m.IsSolved() && len(m.Guesses) >= m.MaxAttempts
+}
+
+func (m MultiBoard) RemainingAttempts() int {
+	return m.MaxAttempts - len(m.Guesses)
+}
+
+// Grid returns the rows of board i padded with empty rows up to the guess
+// budget, ready to be rendered.
+func (m MultiBoard) Grid(i int) []WordGuess {
+	rows := make([]WordGuess, m.MaxAttempts)
+	copy(rows, m.Boards[i].Rows)
+
+	return rows
+}
+
+// LetterGuesses combines the evaluated letters of all boards, so a keyboard
+// key shows the best match the letter has on any board.
+func (m MultiBoard) LetterGuesses() []LetterGuess {
+	lgs := []LetterGuess{}
+	for _, b := range m.Boards {
+		for _, wg := range b.Rows {
+			lgs = append(lgs, wg.LetterGuesses()...)
+		}
+	}
+
+	return lgs
+}
+
+// Solutions returns the solution words of all boards.
+func (m MultiBoard) Solutions() []Word {
+	ws := []Word{}
+	for _, b := range m.Boards {
+		ws = append(ws, b.Solution)
+	}
+
+	return ws
+}
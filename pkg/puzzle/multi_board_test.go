@@ -0,0 +1,81 @@
+package puzzle
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMultiBoard_Guess(t *testing.T) {
+	cried := Word{'c', 'r', 'i', 'e', 'd'}
+	tried := Word{'t', 'r', 'i', 'e', 'd'}
+	gamer := Word{'g', 'a', 'm', 'e', 'r'}
+
+	m := NewMultiBoard([]Word{cried, tried})
+	if m.MaxAttempts != 7 {
+		t.Fatalf("MaxAttempts = %d, want 7", m.MaxAttempts)
+	}
+
+	before := m
+	if err := m.Guess(tried, Word.ToLower); err != nil {
+		t.Fatalf("Guess() error = %s", err)
+	}
+	if len(before.Guesses) != 0 || len(before.Boards[0].Rows) != 0 {
+		t.Errorf("Guess() changed a copy of the game")
+	}
+
+	if m.Boards[0].IsSolved() || !m.Boards[1].IsSolved() {
+		t.Errorf("after guessing %s: boards solved = %t, %t, want false, true", tried, m.Boards[0].IsSolved(), m.Boards[1].IsSolved())
+	}
+
+	_ = m.Guess(gamer, Word.ToLower)
+	if len(m.Boards[0].Rows) != 2 || len(m.Boards[1].Rows) != 1 {
+		t.Errorf("solved board got evaluated again: rows = %d, %d, want 2, 1", len(m.Boards[0].Rows), len(m.Boards[1].Rows))
+	}
+	if got := m.RemainingAttempts(); got != 5 {
+		t.Errorf("RemainingAttempts() = %d, want 5", got)
+	}
+	if got := len(m.Grid(1)); got != 7 {
+		t.Errorf("len(Grid()) = %d, want 7", got)
+	}
+
+	_ = m.Guess(cried, Word.ToLower)
+	if !m.IsSolved() || m.IsLoose() {
+		t.Errorf("IsSolved() = %t, IsLoose() = %t, want true, false", m.IsSolved(), m.IsLoose())
+	}
+	if err := m.Guess(cried, Word.ToLower); !errors.Is(err, ErrMultiBoardOver) {
+		t.Errorf("Guess() error = %v, want %v", err, ErrMultiBoardOver)
+	}
+}
+
+func TestMultiBoard_IsLoose(t *testing.T) {
+	gamer := Word{'g', 'a', 'm', 'e', 'r'}
+	m := NewMultiBoard([]Word{{'c', 'r', 'i', 'e', 'd'}, {'t', 'r', 'i', 'e', 'd'}, {'f', 'r', 'i', 'e', 'd'}, {'d', 'r', 'i', 'e', 'd'}})
+
+	for range MultiBoardAttempts(4) {
+		if m.IsLoose() {
+			t.Fatalf("IsLoose() = true before using all attempts")
+		}
+		_ = m.Guess(gamer, Word.ToLower)
+	}
+
+	if !m.IsLoose() {
+		t.Errorf("IsLoose() = false after %d attempts", MultiBoardAttempts(4))
+	}
+}
+
+func TestMultiBoard_LetterGuesses(t *testing.T) {
+	m := NewMultiBoard([]Word{{'c', 'r', 'i', 'e', 'd'}, {'g', 'a', 'm', 'e', 'r'}})
+	_ = m.Guess(Word{'g', 'a', 'm', 'e', 's'}, Word.ToLower)
+
+	exact := map[rune]bool{}
+	for _, lg := range m.LetterGuesses() {
+		if lg.Match == MatchExact {
+			exact[lg.Letter] = true
+		}
+	}
+
+	// 'g' misses the first board but hits the second one
+	if !exact['g'] || !exact['e'] {
+		t.Errorf("LetterGuesses() exact letters = %v, want g and e among them", exact)
+	}
+}
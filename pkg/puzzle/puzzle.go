@@ -48,7 +48,7 @@ type WordGuess [5]LetterGuess
 
 func (wg WordGuess) isFilled() bool {
 	for _, l := range wg {
-		if l.Letter == 0 || l.Letter == 65533 {
+		if l.Letter == 0 {
 			return false
 		}
 	}
@@ -86,9 +86,12 @@ type LetterGuess struct {
 }
 
 func EvaluateGuessedWord(guessedWord Word, solutionWord Word) WordGuess {
-	solutionWord = solutionWord.ToLower()
-	guessedLetterCountMap := make(map[rune]int)
+	return EvaluateFoldedGuessedWord(guessedWord, solutionWord, Word.ToLower)
+}
 
+// EvaluateFoldedGuessedWord compares guessed and solution word after folding
+// both with fold, the result keeps the letters as they were guessed.
+func EvaluateFoldedGuessedWord(guessedWord Word, solutionWord Word, fold func(Word) Word) WordGuess {
 	resultWordGuess := WordGuess{}
 
 	// initilize
@@ -97,6 +100,10 @@ func EvaluateGuessedWord(guessedWord Word, solutionWord Word) WordGuess {
 		resultWordGuess[i].Match = MatchNone
 	}
 
+	solutionWord = fold(solutionWord)
+	guessedWord = fold(guessedWord)
+	guessedLetterCountMap := make(map[rune]int)
+
 	// mark exact matches
 	for i, gr := range guessedWord {
 		exact := solutionWord[i] == gr
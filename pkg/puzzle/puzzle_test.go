@@ -3,6 +3,8 @@ package puzzle
 import (
 	"reflect"
 	"testing"
+
+	"github.com/pandorasNox/lettr/pkg/language"
 )
 
 func Test_EvaluateGuessedWord(t *testing.T) {
@@ -126,3 +128,53 @@ func Test_EvaluateGuessedWord(t *testing.T) {
 		})
 	}
 }
+
+func Test_EvaluateFoldedGuessedWord(t *testing.T) {
+	fold := FoldWord(language.LANG_DE)
+
+	tests := []struct {
+		name         string
+		guessedWord  Word
+		solutionWord Word
+		want         WordGuess
+	}{
+		{
+			name:         "missing umlaut counts as exact, guessed letter is kept",
+			guessedWord:  Word{'s', 'c', 'h', 'o', 'n'},
+			solutionWord: Word{'s', 'c', 'h', 'ö', 'n'},
+			want: WordGuess{
+				{Letter: 's', Match: MatchExact},
+				{Letter: 'c', Match: MatchExact},
+				{Letter: 'h', Match: MatchExact},
+				{Letter: 'o', Match: MatchExact},
+				{Letter: 'n', Match: MatchExact},
+			},
+		},
+		{
+			name:         "umlaut counts as vague for base letter",
+			guessedWord:  Word{'ü', 'b', 'e', 'r', 'n'},
+			solutionWord: Word{'b', 'r', 'u', 'n', 'o'},
+			want: WordGuess{
+				{Letter: 'ü', Match: MatchVague},
+				{Letter: 'b', Match: MatchVague},
+				{Letter: 'e', Match: MatchNone},
+				{Letter: 'r', Match: MatchVague},
+				{Letter: 'n', Match: MatchVague},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EvaluateFoldedGuessedWord(tt.guessedWord, tt.solutionWord, fold); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EvaluateFoldedGuessedWord() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("strict evaluation keeps umlauts apart", func(t *testing.T) {
+		got := EvaluateGuessedWord(Word{'s', 'c', 'h', 'o', 'n'}, Word{'S', 'C', 'H', 'Ö', 'N'})
+		if got[3].Match != MatchNone {
+			t.Errorf("EvaluateGuessedWord() letter 'o' = %v, want MatchNone", got[3].Match)
+		}
+	})
+}
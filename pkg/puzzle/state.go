@@ -0,0 +1,198 @@
+package puzzle
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Status is the stage of a game. Games start as STATUS_NOT_STARTED and only
+// move on by the moves of GameState, every stage after STATUS_IN_PROGRESS is
+// final.
+type Status string
+
+const (
+	STATUS_NOT_STARTED Status = "not started"
+	STATUS_IN_PROGRESS Status = "in progress"
+	STATUS_WON         Status = "won"
+	STATUS_LOST        Status = "lost"
+	// STATUS_ABANDONED is a game given up before its end, e.g. by starting
+	// a new one, its solution counts as revealed
+	STATUS_ABANDONED Status = "abandoned"
+)
+
+// IsOver reports whether s is final.
+func (s Status) IsOver() bool {
+	return s == STATUS_WON || s == STATUS_LOST || s == STATUS_ABANDONED
+}
+
+type Move string
+
+const (
+	MOVE_GUESS    Move = "guess"
+	MOVE_HINT     Move = "hint"
+	MOVE_TIME_OUT Move = "time out"
+	MOVE_ABANDON  Move = "abandon"
+)
+
+// moves lists the moves allowed in every status and the statuses they may
+// lead to, final statuses allow no moves at all.
+var moves = map[Status]map[Move][]Status{
+	STATUS_NOT_STARTED: {
+		MOVE_GUESS:    {STATUS_IN_PROGRESS},
+		MOVE_HINT:     {STATUS_NOT_STARTED},
+		MOVE_TIME_OUT: {STATUS_LOST},
+		MOVE_ABANDON:  {STATUS_ABANDONED},
+	},
+	STATUS_IN_PROGRESS: {
+		MOVE_GUESS:    {STATUS_IN_PROGRESS, STATUS_WON, STATUS_LOST},
+		MOVE_HINT:     {STATUS_IN_PROGRESS},
+		MOVE_TIME_OUT: {STATUS_LOST},
+		MOVE_ABANDON:  {STATUS_ABANDONED},
+	},
+}
+
+var (
+	ErrIllegalMove   = errors.New("illegal move")
+	ErrGameOver      = errors.New("game is over")
+	ErrDeadlineAhead = errors.New("deadline not reached")
+)
+
+// IllegalMoveError is returned for a move the status of the game does not
+// allow. It matches ErrIllegalMove and, for games already over, ErrGameOver.
+type IllegalMoveError struct {
+	Move   Move
+	Status Status
+}
+
+func (e *IllegalMoveError) Error() string {
+	return fmt.Sprintf("illegal move: %s in status '%s'", e.Move, e.Status)
+}
+
+func (e *IllegalMoveError) Is(target error) bool {
+	return target == ErrIllegalMove || (target == ErrGameOver && e.Status.IsOver())
+}
+
+// Transition is a change of status together with the move causing it.
+type Transition struct {
+	Move Move      `json:"move"`
+	From Status    `json:"from"`
+	To   Status    `json:"to"`
+	At   time.Time `json:"at"`
+}
+
+func (g *GameState) Status() Status {
+	if g.status == "" {
+		return STATUS_NOT_STARTED
+	}
+
+	return g.status
+}
+
+func (g *GameState) Transitions() []Transition {
+	return slices.Clone(g.transitions)
+}
+
+// Can reports whether m is allowed in the current status.
+func (g *GameState) Can(m Move) error {
+	if _, ok := moves[g.Status()][m]; !ok {
+		return &IllegalMoveError{Move: m, Status: g.Status()}
+	}
+
+	return nil
+}
+
+// advance moves the game to status to, staying in the current status is not
+// recorded as transition.
+func (g *GameState) advance(m Move, to Status, now time.Time) error {
+	from := g.Status()
+	if !slices.Contains(moves[from][m], to) {
+		return &IllegalMoveError{Move: m, Status: from}
+	}
+
+	if to == from {
+		return nil
+	}
+
+	g.status = to
+	// game states are passed around by value, never write into shared arrays
+	g.transitions = append(slices.Clip(g.transitions), Transition{Move: m, From: from, To: to, At: now})
+
+	return nil
+}
+
+// Guess stores the evaluated guess wg in the next free row of a single board
+// game.
+func (g *GameState) Guess(wg WordGuess, now time.Time) error {
+	if err := g.Can(MOVE_GUESS); err != nil {
+		return err
+	}
+
+	p := g.lastEvaluatedAttempt
+	row := p.ActiveRow()
+	if int(row) >= len(p.Guesses) {
+		return &IllegalMoveError{Move: MOVE_GUESS, Status: g.Status()}
+	}
+	p.Guesses[row] = wg
+	g.lastEvaluatedAttempt = p
+
+	return g.guessed(now)
+}
+
+// GuessBoards evaluates w against all boards of a multi board game, see
+// MultiBoard.Guess.
+func (g *GameState) GuessBoards(w Word, fold func(Word) Word, now time.Time) error {
+	if err := g.Can(MOVE_GUESS); err != nil {
+		return err
+	}
+
+	m := g.multiBoard
+	err := m.Guess(w, fold)
+	if err != nil {
+		return err
+	}
+	g.multiBoard = m
+
+	return g.guessed(now)
+}
+
+func (g *GameState) guessed(now time.Time) error {
+	g.RecordGuess(now)
+
+	solved, loose := g.lastEvaluatedAttempt.IsSolved(), g.lastEvaluatedAttempt.IsLoose()
+	if g.IsMultiBoard() {
+		solved, loose = g.multiBoard.IsSolved(), g.multiBoard.IsLoose()
+	}
+
+	to := STATUS_IN_PROGRESS
+	if solved {
+		to = STATUS_WON
+	} else if loose {
+		to = STATUS_LOST
+	}
+
+	if g.Status() == STATUS_NOT_STARTED {
+		_ = g.advance(MOVE_GUESS, STATUS_IN_PROGRESS, now)
+	}
+
+	return g.advance(MOVE_GUESS, to, now)
+}
+
+// TimeOut ends a speed game whose deadline has passed as lost.
+func (g *GameState) TimeOut(now time.Time) error {
+	if err := g.Can(MOVE_TIME_OUT); err != nil {
+		return err
+	}
+
+	if !g.IsSpeed() || now.Before(g.Deadline()) {
+		return ErrDeadlineAhead
+	}
+
+	return g.advance(MOVE_TIME_OUT, STATUS_LOST, now)
+}
+
+// Abandon gives up a game before its end.
+func (g *GameState) Abandon(now time.Time) error {
+	return g.advance(MOVE_ABANDON, STATUS_ABANDONED, now)
+}
@@ -0,0 +1,116 @@
+package puzzle
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/language"
+)
+
+func TestGameState_transitions(t *testing.T) {
+	solution := Word{'c', 'r', 'i', 'e', 'd'}
+	tried := EvaluateGuessedWord(Word{'t', 'r', 'i', 'e', 'd'}, solution)
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	g := NewChallengeGame("", solution)
+	if g.Status() != STATUS_NOT_STARTED {
+		t.Fatalf("Status() = %s, want %s", g.Status(), STATUS_NOT_STARTED)
+	}
+
+	if err := g.AddLetterHint('c'); err != nil {
+		t.Errorf("AddLetterHint() before the first guess error = %s", err)
+	}
+	if err := g.Guess(tried, start); err != nil {
+		t.Fatalf("Guess() error = %s", err)
+	}
+	if err := g.Guess(tried, start.Add(time.Minute)); err != nil {
+		t.Fatalf("Guess() error = %s", err)
+	}
+	if err := g.Guess(EvaluateGuessedWord(solution, solution), start.Add(2*time.Minute)); err != nil {
+		t.Fatalf("Guess() error = %s", err)
+	}
+
+	want := []Transition{
+		{Move: MOVE_GUESS, From: STATUS_NOT_STARTED, To: STATUS_IN_PROGRESS, At: start},
+		{Move: MOVE_GUESS, From: STATUS_IN_PROGRESS, To: STATUS_WON, At: start.Add(2 * time.Minute)},
+	}
+	if !reflect.DeepEqual(g.Transitions(), want) {
+		t.Errorf("Transitions() = %+v, want %+v", g.Transitions(), want)
+	}
+	if !g.IsSolved() || g.IsLoose() {
+		t.Errorf("IsSolved() = %t, IsLoose() = %t, want true, false", g.IsSolved(), g.IsLoose())
+	}
+
+	err := g.AddLetterHint('r')
+	var illegal *IllegalMoveError
+	if !errors.As(err, &illegal) || illegal.Move != MOVE_HINT || illegal.Status != STATUS_WON {
+		t.Errorf("AddLetterHint() after win error = %v, want an IllegalMoveError for a hint in status won", err)
+	}
+	if !errors.Is(g.Guess(tried, start), ErrGameOver) {
+		t.Errorf("Guess() after win must fail with ErrGameOver")
+	}
+	if !errors.Is(g.Abandon(start), ErrIllegalMove) {
+		t.Errorf("Abandon() after win must fail with ErrIllegalMove")
+	}
+	if len(g.LetterHints()) != 1 || g.LastEvaluatedAttempt().ActiveRow() != 3 {
+		t.Errorf("illegal moves must not change the game")
+	}
+}
+
+func TestGameState_lost(t *testing.T) {
+	solution := Word{'c', 'r', 'i', 'e', 'd'}
+	tried := EvaluateGuessedWord(Word{'t', 'r', 'i', 'e', 'd'}, solution)
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("out of rows", func(t *testing.T) {
+		g := NewChallengeGame("", solution)
+		for range len(g.LastEvaluatedAttempt().Guesses) {
+			if err := g.Guess(tried, start); err != nil {
+				t.Fatalf("Guess() error = %s", err)
+			}
+		}
+
+		if g.Status() != STATUS_LOST {
+			t.Errorf("Status() = %s, want %s", g.Status(), STATUS_LOST)
+		}
+		if g.IsTimedOut(start) {
+			t.Errorf("IsTimedOut() = true for an untimed game")
+		}
+	})
+
+	t.Run("on time", func(t *testing.T) {
+		g := NewChallengeGame("", solution)
+		g.StartSpeed(start, time.Minute)
+
+		if err := g.TimeOut(start.Add(59 * time.Second)); !errors.Is(err, ErrDeadlineAhead) {
+			t.Errorf("TimeOut() before the deadline error = %v, want %s", err, ErrDeadlineAhead)
+		}
+		if err := g.TimeOut(start.Add(time.Minute)); err != nil {
+			t.Fatalf("TimeOut() error = %s", err)
+		}
+		if !errors.Is(g.TimeOut(start.Add(time.Hour)), ErrGameOver) {
+			t.Errorf("a second TimeOut() must fail with ErrGameOver")
+		}
+
+		if g.Status() != STATUS_LOST || !g.IsTimedOut(start.Add(time.Hour)) {
+			t.Errorf("Status() = %s, IsTimedOut() = %t, want lost on time", g.Status(), g.IsTimedOut(start.Add(time.Hour)))
+		}
+	})
+
+	t.Run("abandoned", func(t *testing.T) {
+		g := NewChallengeGame("", solution)
+		_ = g.Guess(tried, start)
+
+		if err := g.Abandon(start.Add(time.Minute)); err != nil {
+			t.Fatalf("Abandon() error = %s", err)
+		}
+		if g.Status() != STATUS_ABANDONED || !g.IsLoose() {
+			t.Errorf("Status() = %s, want %s counting as loss", g.Status(), STATUS_ABANDONED)
+		}
+		if r := g.Record(language.LANG_EN, start.Add(time.Minute)); !r.IsAbandoned() {
+			t.Errorf("Record() outcome = %s, want %s", r.Outcome, OUTCOME_ABANDONED)
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package puzzle
+
+const (
+	SURVIVAL_POINTS_PER_WORD            = 10
+	SURVIVAL_POINTS_PER_REMAINING_GUESS = 10
+	SURVIVAL_HINT_PENALTY               = 5
+)
+
+// SurvivalRun is the running score of a survival game. It is carried from
+// one solved word to the next and ends with the first loss.
+type SurvivalRun struct {
+	Active bool `json:"active"`
+	Score  int  `json:"score"`
+	Words  int  `json:"words"`
+	// LastPoints are the points the last solved word earned
+	LastPoints int `json:"lastPoints"`
+}
+
+// SurvivalPoints scores a solved puzzle: a base per word plus a bonus for
+// every guess left, minus a penalty for every letter hint. A solved word
+// is always worth at least one point.
+func SurvivalPoints(p Puzzle, hintsUsed int) int {
+	if !p.IsSolved() {
+		return 0
+	}
+
+	remaining := len(p.Guesses) - int(p.ActiveRow())
+	points := SURVIVAL_POINTS_PER_WORD + remaining*SURVIVAL_POINTS_PER_REMAINING_GUESS - hintsUsed*SURVIVAL_HINT_PENALTY
+
+	return max(points, 1)
+}
+
+// Solved returns the run after solving another word for points.
+func (r SurvivalRun) Solved(points int) SurvivalRun {
+	r.Score += points
+	r.Words++
+	r.LastPoints = points
+
+	return r
+}
@@ -0,0 +1,38 @@
+package puzzle
+
+import "testing"
+
+func TestSurvivalPoints(t *testing.T) {
+	solution := Word{'c', 'r', 'i', 'e', 'd'}
+	miss := EvaluateGuessedWord(Word{'g', 'a', 'm', 'e', 'r'}, solution)
+	hit := EvaluateGuessedWord(solution, solution)
+
+	tests := []struct {
+		name      string
+		p         Puzzle
+		hintsUsed int
+		want      int
+	}{
+		{"first guess", Puzzle{Guesses: [6]WordGuess{hit}}, 0, 60},
+		{"third guess", Puzzle{Guesses: [6]WordGuess{miss, miss, hit}}, 0, 40},
+		{"third guess with hints", Puzzle{Guesses: [6]WordGuess{miss, miss, hit}}, 2, 30},
+		{"last guess", Puzzle{Guesses: [6]WordGuess{miss, miss, miss, miss, miss, hit}}, 0, 10},
+		{"never below one point", Puzzle{Guesses: [6]WordGuess{miss, miss, miss, miss, miss, hit}}, 5, 1},
+		{"not solved", Puzzle{Guesses: [6]WordGuess{miss}}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SurvivalPoints(tt.p, tt.hintsUsed); got != tt.want {
+				t.Errorf("SurvivalPoints() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSurvivalRun_Solved(t *testing.T) {
+	r := SurvivalRun{Active: true}.Solved(40).Solved(10)
+
+	if r.Score != 50 || r.Words != 2 || r.LastPoints != 10 || !r.Active {
+		t.Errorf("Solved() = %+v, want score 50 after 2 words", r)
+	}
+}
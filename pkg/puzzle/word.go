@@ -5,6 +5,9 @@ import (
 	"strings"
 	"unicode"
 	"unicode/utf8"
+
+	"github.com/pandorasNox/lettr/pkg/language"
+	"golang.org/x/text/unicode/norm"
 )
 
 type Word [5]rune
@@ -83,13 +86,13 @@ func toWord(wo string) (Word, error) {
 	out := Word{}
 
 	length := 0
-	for i, l := range wo {
+	for _, l := range wo {
 		length++
 		if length > len(out) {
 			return Word{}, fmt.Errorf("string does not match allowed word length: length=%d, expectedLength=%d", length, len(out))
 		}
 
-		out[i] = l
+		out[length-1] = l
 	}
 
 	if length < len(out) {
@@ -107,7 +110,12 @@ func SliceToWord(maybeGuessedWord []string) (Word, error) {
 	}
 
 	for i, l := range maybeGuessedWord {
-		w[i], _ = utf8.DecodeRuneInString(strings.ToLower(l))
+		l = strings.ToLower(norm.NFC.String(l))
+		if utf8.RuneCountInString(l) > 1 {
+			return Word{}, fmt.Errorf("sliceToWord: provided slice holds more than one letter at position %d", i)
+		}
+
+		w[i], _ = utf8.DecodeRuneInString(l)
 		if w[i] == 65533 {
 			w[i] = 0
 		}
@@ -115,3 +123,16 @@ func SliceToWord(maybeGuessedWord []string) (Word, error) {
 
 	return w, nil
 }
+
+// FoldWord returns a func folding words of language l (see language.Fold).
+// Words which can't be folded letter by letter are only lowercased.
+func FoldWord(l language.Language) func(Word) Word {
+	return func(w Word) Word {
+		fw, err := toWord(language.Fold(l, w.String()))
+		if err != nil {
+			return w.ToLower()
+		}
+
+		return fw
+	}
+}
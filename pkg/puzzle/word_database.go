@@ -2,13 +2,15 @@ package puzzle
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	iofs "io/fs"
-	"math/rand"
 	"slices"
-	"time"
+	"strings"
+	"sync"
 
 	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/random"
 )
 
 type WordCollection string
@@ -16,14 +18,97 @@ type WordCollection string
 const (
 	WC_ALL    WordCollection = "wc_all"
 	WC_COMMON WordCollection = "wc_common"
+	// WC_GUESS_ONLY words are accepted as guesses but never picked as
+	// solutions, e.g. obscure words of a guess list. Words also part of
+	// another collection are still picked from there.
+	WC_GUESS_ONLY WordCollection = "wc_guess_only"
 )
 
+// guessableCollections are the collections Exists consults.
+var guessableCollections = []WordCollection{WC_ALL, WC_GUESS_ONLY}
+
+// IsThemed reports whether c is a named collection players may pick their
+// solutions from, e.g. "animals", as opposed to the built in roles.
+func (c WordCollection) IsThemed() bool {
+	return c != "" && c != WC_ALL && c != WC_COMMON && c != WC_GUESS_ONLY
+}
+
+// ErrUnknownCollection is returned for picks from a collection the language
+// doesn't have.
+var ErrUnknownCollection = errors.New("unknown word collection")
+
 type WordDatabase struct {
 	Db map[language.Language]map[WordCollection]map[Word]bool
+	// Tags of the themed collections, read from the metadata line of their
+	// word lists, e.g. "// animal names | tags: animals, kids-friendly"
+	Tags map[language.Language]map[WordCollection][]string
+	// Ranks of the words of word lists ordered by frequency, i.e. with
+	// "order: frequency" in their metadata line, 1 for the most frequent
+	Ranks map[language.Language]map[Word]int
+	// Stats are the observed win rates, nil to score difficulties without
+	// them. Copies of the database share them.
+	Stats *WordStats
+
+	// index is derived from Db by Init, databases built without Init work
+	// without it, just slower
+	index *wordIndex
+}
+
+// wordIndex holds what RandomPick needs of the words, computed once instead
+// of on every pick.
+type wordIndex struct {
+	// sorted words of every collection, map iteration order is random
+	sorted map[language.Language]map[WordCollection][]Word
+	// commonness of the letters of every language, see letterCommonness
+	commonness map[language.Language]map[rune]float64
+	// folded guessable words of every language, see ExistsFolded
+	folded map[language.Language]map[Word]bool
+
+	// mutex guards scores and byDifficulty, recorded results move words
+	mutex sync.RWMutex
+	// scores are the difficulties of all words, see WordDatabase.Difficulty
+	scores map[language.Language]map[Word]float64
+	// byDifficulty are the words of every collection from easy to hard,
+	// replaced on changes, never changed in place
+	byDifficulty map[language.Language]map[WordCollection][]Word
+}
+
+func (wdb *WordDatabase) buildIndex() {
+	index := &wordIndex{
+		sorted:       make(map[language.Language]map[WordCollection][]Word, len(wdb.Db)),
+		commonness:   make(map[language.Language]map[rune]float64, len(wdb.Db)),
+		folded:       make(map[language.Language]map[Word]bool, len(wdb.Db)),
+		scores:       make(map[language.Language]map[Word]float64, len(wdb.Db)),
+		byDifficulty: make(map[language.Language]map[WordCollection][]Word, len(wdb.Db)),
+	}
+
+	for l, collections := range wdb.Db {
+		commonness := wdb.letterCommonness(l)
+		index.commonness[l] = commonness
+		index.folded[l] = wdb.foldedWords(l)
+		index.sorted[l] = make(map[WordCollection][]Word, len(collections))
+		index.scores[l] = make(map[Word]float64)
+		index.byDifficulty[l] = make(map[WordCollection][]Word, len(collections))
+
+		for c, words := range collections {
+			sorted := sortedWords(words)
+			index.sorted[l][c] = sorted
+
+			for _, w := range sorted {
+				if _, ok := index.scores[l][w]; !ok {
+					index.scores[l][w] = wdb.difficulty(l, w, commonness)
+				}
+			}
+			index.byDifficulty[l][c] = orderByDifficulty(sorted, index.scores[l])
+		}
+	}
+
+	wdb.index = index
 }
 
 func (wdb *WordDatabase) Init(fs iofs.FS, filePathsByLanguage map[language.Language]map[WordCollection][]string) error {
 	wdb.Db = make(map[language.Language]map[WordCollection]map[Word]bool)
+	wdb.index = nil
 
 	for l, collectionFilePaths := range filePathsByLanguage {
 		wdb.Db[l] = make(map[WordCollection]map[Word]bool)
@@ -50,19 +135,26 @@ func (wdb *WordDatabase) Init(fs iofs.FS, filePathsByLanguage map[language.Langu
 
 				scanner := bufio.NewScanner(f)
 				var line int = 0
+				ranked := false
 				for scanner.Scan() {
-					if line == 0 { // skip first metadata line
+					if line == 0 { // first line holds the metadata
+						wdb.addTags(l, c, wordListTags(scanner.Text()))
+						order, _ := metadataField(scanner.Text(), "order")
+						ranked = order == "frequency"
 						line++
 						continue
 					}
 
 					candidate := scanner.Text()
-					word, err := toWord(candidate)
+					word, err := toWord(language.Normalize(l, candidate))
 					if err != nil {
 						return fmt.Errorf("wordDatabase init, couldn't parse line to word: line='%s', err=%s", candidate, err)
 					}
 
 					wdb.Db[l][c][word.ToLower()] = true
+					if ranked {
+						wdb.addRank(l, word.ToLower(), line)
+					}
 
 					line++
 				}
@@ -72,30 +164,168 @@ func (wdb *WordDatabase) Init(fs iofs.FS, filePathsByLanguage map[language.Langu
 			}
 		}
 
+		// solutions have to be valid guesses
+		if _, ok := wdb.Db[l][WC_ALL]; !ok {
+			wdb.Db[l][WC_ALL] = make(map[Word]bool)
+		}
 		for w := range wdb.Db[l][WC_COMMON] {
 			wdb.Db[l][WC_ALL][w] = true
 		}
+
+		// themes only pick from the dictionaries, they must not widen the
+		// guesses of every game
+		for c := range wdb.Db[l] {
+			if !c.IsThemed() {
+				continue
+			}
+
+			for w := range wdb.Db[l][c] {
+				if !wdb.Exists(l, w) {
+					return fmt.Errorf("wordDatabase init, themed word '%s' of collection '%s' is not in the word lists of language '%s'", w, c, l)
+				}
+			}
+		}
 	}
 
+	wdb.buildIndex()
+
 	return nil
 }
 
+// metadataField returns the value of the field key of a word list metadata
+// line, fields of the line are separated by '|', e.g. "| order: frequency".
+func metadataField(metadata string, key string) (string, bool) {
+	for _, field := range strings.Split(metadata, "|") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(field), key+":")
+		if ok {
+			return strings.TrimSpace(value), true
+		}
+	}
+
+	return "", false
+}
+
+// wordListTags returns the tags of a word list metadata line.
+func wordListTags(metadata string) []string {
+	tags := []string{}
+	value, _ := metadataField(metadata, "tags")
+	for _, tag := range strings.Split(value, ",") {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+// addRank keeps the best rank of w, lists may repeat across collections.
+func (wdb *WordDatabase) addRank(l language.Language, w Word, rank int) {
+	if wdb.Ranks == nil {
+		wdb.Ranks = make(map[language.Language]map[Word]int)
+	}
+	if wdb.Ranks[l] == nil {
+		wdb.Ranks[l] = make(map[Word]int)
+	}
+
+	if current, ok := wdb.Ranks[l][w]; !ok || rank < current {
+		wdb.Ranks[l][w] = rank
+	}
+}
+
+func (wdb *WordDatabase) addTags(l language.Language, c WordCollection, tags []string) {
+	if len(tags) == 0 {
+		return
+	}
+
+	if wdb.Tags == nil {
+		wdb.Tags = make(map[language.Language]map[WordCollection][]string)
+	}
+	if wdb.Tags[l] == nil {
+		wdb.Tags[l] = make(map[WordCollection][]string)
+	}
+
+	for _, tag := range tags {
+		if !slices.Contains(wdb.Tags[l][c], tag) {
+			wdb.Tags[l][c] = append(wdb.Tags[l][c], tag)
+		}
+	}
+}
+
+// Collections returns the themed collections of language l sorted by name.
+func (wdb WordDatabase) Collections(l language.Language) []WordCollection {
+	out := []WordCollection{}
+	for c := range wdb.Db[l] {
+		if c.IsThemed() && len(wdb.Db[l][c]) > 0 {
+			out = append(out, c)
+		}
+	}
+
+	slices.Sort(out)
+
+	return out
+}
+
+// HasCollection reports whether players of language l may pick c, the empty
+// collection stands for the default solutions.
+func (wdb WordDatabase) HasCollection(l language.Language, c WordCollection) bool {
+	return c == "" || slices.Contains(wdb.Collections(l), c)
+}
+
+// CollectionTags returns the tags of the collection c of language l.
+func (wdb WordDatabase) CollectionTags(l language.Language, c WordCollection) []string {
+	return slices.Clone(wdb.Tags[l][c])
+}
+
 func (wdb WordDatabase) Exists(l language.Language, w Word) bool {
 	db, ok := wdb.Db[l]
 	if !ok {
 		return false
 	}
 
-	db_c, ok := db[WC_ALL]
-	if !ok {
+	nw, err := toWord(language.Normalize(l, w.String()))
+	if err != nil {
 		return false
 	}
 
-	_, ok = db_c[w.ToLower()]
-	return ok
+	for _, c := range guessableCollections {
+		if _, ok := db[c][nw]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ExistsFolded reports whether a word of language l equals w once both are
+// folded, i.e. it ignores diacritics (easy mode).
+func (wdb WordDatabase) ExistsFolded(l language.Language, w Word) bool {
+	fw := FoldWord(l)(w)
+	if wdb.index != nil {
+		return wdb.index.folded[l][fw]
+	}
+
+	return wdb.foldedWords(l)[fw]
+}
+
+// foldedWords returns the guessable words of language l folded.
+func (wdb WordDatabase) foldedWords(l language.Language) map[Word]bool {
+	fold := FoldWord(l)
+	folded := map[Word]bool{}
+	for _, c := range guessableCollections {
+		for w := range wdb.Db[l][c] {
+			folded[fold(w)] = true
+		}
+	}
+
+	return folded
 }
 
-func (wdb WordDatabase) RandomPick(l language.Language, avoidList []Word, retryAkkumulator uint8) (Word, error) {
+// RandomPick picks a solution of the difficulty of p from the themed
+// collection of p, or from the default solutions for an empty collection.
+func (wdb WordDatabase) RandomPick(rnd random.Rand, l language.Language, p Pick, avoidList []Word, retryAkkumulator uint8) (Word, error) {
+	c := p.Collection
+
 	const MAX_RETRY uint8 = 10
 
 	if retryAkkumulator > MAX_RETRY {
@@ -107,9 +337,20 @@ func (wdb WordDatabase) RandomPick(l language.Language, avoidList []Word, retryA
 		return Word{}, fmt.Errorf("RandomPick failed with unknown language: '%s'", l)
 	}
 
+	// WC_GUESS_ONLY is never picked from
 	collection := WC_COMMON
 	db_c, ok := db[collection]
-	if !ok {
+	if c != "" {
+		if !c.IsThemed() {
+			return Word{}, fmt.Errorf("RandomPick with lang '%s' failed, '%s' is no themed collection: %w", l, c, ErrUnknownCollection)
+		}
+
+		collection = c
+		db_c, ok = db[collection]
+		if !ok {
+			return Word{}, fmt.Errorf("RandomPick with lang '%s' failed with collection '%s': %w", l, collection, ErrUnknownCollection)
+		}
+	} else if !ok {
 		collection = WC_ALL
 
 		db_c, ok = db[collection]
@@ -118,59 +359,79 @@ func (wdb WordDatabase) RandomPick(l language.Language, avoidList []Word, retryA
 		}
 	}
 
-	randsource := rand.NewSource(time.Now().UnixNano())
-	randgenerator := rand.New(randsource)
-	rolledLine := randgenerator.Intn(len(db_c))
+	if len(db_c) == 0 {
+		return Word{}, fmt.Errorf("RandomPick with lang '%s' failed with empty collection: '%s'", l, collection)
+	}
 
-	currentLine := 0
-	for w := range db_c {
-		if currentLine == rolledLine {
+	words := wdb.byDifficulty(l, p.Difficulty, collection)
+	w := words[rnd.Intn(len(words))]
 
-			wordContained := slices.ContainsFunc(avoidList, func(wo Word) bool {
-				return w.IsEqual(wo)
-			})
-			if wordContained {
-				return wdb.RandomPick(l, avoidList, retryAkkumulator+1)
-			}
+	wordContained := slices.ContainsFunc(avoidList, func(wo Word) bool {
+		return w.IsEqual(wo)
+	})
+	if wordContained {
+		return wdb.RandomPick(rnd, l, p, avoidList, retryAkkumulator+1)
+	}
+
+	return w, nil
+}
 
-			return w, nil
+// sortedWords returns the words of the collection c of language l sorted,
+// to make picks reproducible for a seeded rnd. The slice must not be changed.
+func (wdb WordDatabase) sortedWords(l language.Language, c WordCollection) []Word {
+	if wdb.index != nil {
+		if words, ok := wdb.index.sorted[l][c]; ok {
+			return words
 		}
+	}
+
+	return sortedWords(wdb.Db[l][c])
+}
 
-		currentLine++
+func sortedWords(collection map[Word]bool) []Word {
+	words := make([]Word, 0, len(collection))
+	for w := range collection {
+		words = append(words, w)
 	}
 
-	return Word{}, fmt.Errorf("RandomPick could not find random line aka this should never happen ^^")
+	slices.SortFunc(words, func(a, b Word) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	return words
 }
 
-func (wdb WordDatabase) RandomPickWithFallback(l language.Language, avoidList []Word, retryAkkumulator uint8) Word {
-	w, err := wdb.RandomPick(l, avoidList, retryAkkumulator)
-	if err != nil {
-		return Word{'R', 'O', 'A', 'T', 'E'}.ToLower()
+// RandomPickWithFallback picks from the default solutions of the same
+// difficulty once the collection of p is used up or unknown, and from all
+// default solutions after that.
+func (wdb WordDatabase) RandomPickWithFallback(rnd random.Rand, l language.Language, p Pick, avoidList []Word, retryAkkumulator uint8) Word {
+	picks := []Pick{p, {Difficulty: p.Difficulty}, {}}
+	for i, pick := range picks {
+		if i > 0 && pick == picks[i-1] {
+			continue
+		}
+
+		w, err := wdb.RandomPick(rnd, l, pick, avoidList, retryAkkumulator)
+		if err == nil {
+			return w.ToLower()
+		}
 	}
 
-	return w.ToLower()
+	return Word{'R', 'O', 'A', 'T', 'E'}.ToLower()
 }
 
+// FilePathsByLang collects the word list files of every language in the
+// language registry.
 func FilePathsByLang() map[language.Language]map[WordCollection][]string {
-	return map[language.Language]map[WordCollection][]string{
-		language.LANG_EN: {
-			WC_ALL: {
-				"configs/corpora-eng_news_2023_10K-export.txt",
-				"configs/en-en.words.v2.txt",
-			},
-			WC_COMMON: {
-				"configs/corpora-eng_news_2023_10K-export.txt",
-				"configs/github.com.ajeetdsouza.clidle.words.go.txt",
-			},
-		},
-		language.LANG_DE: {
-			WC_ALL: {
-				"configs/corpora-deu_news_2023_10K-export.txt",
-				"configs/de-de.words.v2.txt",
-			},
-			WC_COMMON: {
-				"configs/corpora-deu_news_2023_10K-export.txt",
-			},
-		},
+	out := map[language.Language]map[WordCollection][]string{}
+
+	for _, d := range language.DefaultRegistry.Definitions() {
+		out[d.Code] = map[WordCollection][]string{}
+
+		for collection, paths := range d.WordLists {
+			out[d.Code][WordCollection(collection)] = slices.Clone(paths)
+		}
 	}
+
+	return out
 }
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/random"
 )
 
 // func wordTxtByName(name string) []byte {
@@ -294,6 +295,38 @@ notalettrword
 			},
 		},
 		//
+		{
+			name: "normalizes german words",
+			args: args{
+				fs: fstest.MapFS{
+					"de.txt": {
+						Data: []byte("# metadata\nBa\u0308ren\nBÄREN\nMAẞEN\n"),
+					},
+				},
+				filePathsByLanguage: map[language.Language]map[WordCollection][]string{
+					language.LANG_DE: {
+						WC_ALL:    {"de.txt"},
+						WC_COMMON: {"de.txt"},
+					},
+				},
+			},
+			wantErr: false,
+			wantWdb: WordDatabase{
+				Db: map[language.Language]map[WordCollection]map[Word]bool{
+					language.LANG_DE: {
+						WC_ALL: {
+							{'b', 'ä', 'r', 'e', 'n'}: true,
+							{'m', 'a', 'ß', 'e', 'n'}: true,
+						},
+						WC_COMMON: {
+							{'b', 'ä', 'r', 'e', 'n'}: true,
+							{'m', 'a', 'ß', 'e', 'n'}: true,
+						},
+					},
+				},
+			},
+		},
+		//
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -307,9 +340,217 @@ notalettrword
 					t.Errorf("WordDatabase.Init() error = %v, wantErr %v, wantErrMessageContains %s", err, tt.wantErr, tt.wantErrMessageContains)
 				}
 			}
+			if tt.wantErr == false && wdb.index == nil {
+				t.Errorf("WordDatabase.Init() built no index")
+			}
+			// the index is derived from Db
+			wdb.index = nil
 			if tt.wantErr == false && !reflect.DeepEqual(wdb, tt.wantWdb) {
 				t.Errorf("WordDatabase.Init() databases not equal, got %v, want %v", wdb, tt.wantWdb)
 			}
 		})
 	}
 }
+
+func TestWordDatabase_RandomPick(t *testing.T) {
+	wdb := WordDatabase{Db: map[language.Language]map[WordCollection]map[Word]bool{
+		language.LANG_EN: {
+			WC_COMMON: {
+				{'c', 'r', 'i', 'e', 'd'}: true,
+				{'g', 'a', 'm', 'e', 'r'}: true,
+				{'g', 'a', 'm', 'e', 's'}: true,
+				{'t', 'r', 'i', 'e', 'd'}: true,
+			},
+		},
+	}}
+
+	t.Run("same seed picks same words", func(t *testing.T) {
+		a, b := random.New(7), random.New(7)
+		for i := 0; i < 10; i++ {
+			wa, errA := wdb.RandomPick(a, language.LANG_EN, Pick{}, []Word{}, 0)
+			wb, errB := wdb.RandomPick(b, language.LANG_EN, Pick{}, []Word{}, 0)
+			if errA != nil || errB != nil {
+				t.Fatalf("RandomPick() errors = %v, %v", errA, errB)
+			}
+			if wa != wb {
+				t.Fatalf("RandomPick() pick %d differs for same seed: %s != %s", i, wa, wb)
+			}
+		}
+	})
+
+	t.Run("avoid list is respected", func(t *testing.T) {
+		avoid := []Word{{'c', 'r', 'i', 'e', 'd'}, {'g', 'a', 'm', 'e', 'r'}, {'g', 'a', 'm', 'e', 's'}}
+		rnd := random.New(1)
+		for i := 0; i < 10; i++ {
+			w, err := wdb.RandomPick(rnd, language.LANG_EN, Pick{}, avoid, 0)
+			if err != nil {
+				continue // retries may be exhausted, but an avoided word must never be returned
+			}
+			if w != (Word{'t', 'r', 'i', 'e', 'd'}) {
+				t.Fatalf("RandomPick() = %s, want tried", w)
+			}
+		}
+	})
+
+	t.Run("unknown language", func(t *testing.T) {
+		_, err := wdb.RandomPick(random.New(1), language.LANG_DE, Pick{}, []Word{}, 0)
+		if err == nil {
+			t.Errorf("RandomPick() expected error for unknown language")
+		}
+	})
+}
+
+func TestWordDatabase_Exists(t *testing.T) {
+	wdb := WordDatabase{Db: map[language.Language]map[WordCollection]map[Word]bool{
+		language.LANG_DE: {
+			WC_ALL: {
+				{'b', 'ä', 'r', 'e', 'n'}: true,
+				{'m', 'a', 'ß', 'e', 'n'}: true,
+			},
+		},
+	}}
+
+	tests := []struct {
+		name       string
+		w          Word
+		want       bool
+		wantFolded bool
+	}{
+		{name: "exact", w: Word{'b', 'ä', 'r', 'e', 'n'}, want: true, wantFolded: true},
+		{name: "uppercase", w: Word{'B', 'Ä', 'R', 'E', 'N'}, want: true, wantFolded: true},
+		{name: "uppercase sharp s", w: Word{'M', 'A', 'ẞ', 'E', 'N'}, want: true, wantFolded: true},
+		{name: "missing umlaut", w: Word{'b', 'a', 'r', 'e', 'n'}, want: false, wantFolded: true},
+		{name: "sharp s is not folded to s", w: Word{'m', 'a', 's', 'e', 'n'}, want: false, wantFolded: false},
+		{name: "unknown word", w: Word{'h', 'a', 'l', 'l', 'o'}, want: false, wantFolded: false},
+	}
+
+	indexed := wdb
+	indexed.buildIndex()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wdb.Exists(language.LANG_DE, tt.w); got != tt.want {
+				t.Errorf("WordDatabase.Exists(%s) = %v, want %v", tt.w, got, tt.want)
+			}
+			if got := wdb.ExistsFolded(language.LANG_DE, tt.w); got != tt.wantFolded {
+				t.Errorf("WordDatabase.ExistsFolded(%s) = %v, want %v", tt.w, got, tt.wantFolded)
+			}
+			if got := indexed.ExistsFolded(language.LANG_DE, tt.w); got != tt.wantFolded {
+				t.Errorf("WordDatabase.ExistsFolded(%s) with index = %v, want %v", tt.w, got, tt.wantFolded)
+			}
+		})
+	}
+}
+
+func TestWordDatabase_guessOnly(t *testing.T) {
+	wdb := WordDatabase{}
+	err := wdb.Init(fstest.MapFS{
+		"all.txt":        {Data: []byte("# metadata\ncried\n")},
+		"common.txt":     {Data: []byte("# metadata\ncried\n")},
+		"guess-only.txt": {Data: []byte("# metadata\naahed\ncried\n")},
+	}, map[language.Language]map[WordCollection][]string{
+		language.LANG_EN: {
+			WC_ALL:        {"all.txt"},
+			WC_COMMON:     {"common.txt"},
+			WC_GUESS_ONLY: {"guess-only.txt"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Init() error = %s", err)
+	}
+
+	aahed := Word{'a', 'a', 'h', 'e', 'd'}
+	if !wdb.Exists(language.LANG_EN, aahed) || !wdb.ExistsFolded(language.LANG_EN, aahed) {
+		t.Errorf("guess only word '%s' not accepted as guess", aahed)
+	}
+	if wdb.Db[language.LANG_EN][WC_ALL][aahed] {
+		t.Errorf("guess only word '%s' merged into %s", aahed, WC_ALL)
+	}
+
+	rnd := random.New(1)
+	for range 20 {
+		w, err := wdb.RandomPick(rnd, language.LANG_EN, Pick{}, []Word{}, 0)
+		if err != nil {
+			t.Fatalf("RandomPick() error = %s", err)
+		}
+		if w.IsEqual(aahed) {
+			t.Fatalf("RandomPick() picked the guess only word '%s'", aahed)
+		}
+	}
+
+	// without solutions of its own a language has nothing to pick
+	wdb.Db[language.LANG_DE] = map[WordCollection]map[Word]bool{WC_GUESS_ONLY: {aahed: true}}
+	if _, err := wdb.RandomPick(rnd, language.LANG_DE, Pick{}, []Word{}, 0); err == nil {
+		t.Errorf("RandomPick() picked from %s", WC_GUESS_ONLY)
+	}
+}
+
+func TestWordDatabase_themedCollections(t *testing.T) {
+	animals := WordCollection("animals")
+	otter := Word{'o', 't', 't', 'e', 'r'}
+	tiger := Word{'t', 'i', 'g', 'e', 'r'}
+
+	wdb := WordDatabase{}
+	err := wdb.Init(fstest.MapFS{
+		"all.txt":     {Data: []byte("# metadata\ngamer\notter\ntiger\n")},
+		"common.txt":  {Data: []byte("# metadata\ncried\n")},
+		"animals.txt": {Data: []byte("// animals | tags: Animals, kids-friendly\notter\ntiger\n")},
+		"more.txt":    {Data: []byte("// more animals | tags: animals\n")},
+	}, map[language.Language]map[WordCollection][]string{
+		language.LANG_EN: {
+			WC_ALL:    {"all.txt"},
+			WC_COMMON: {"common.txt"},
+			animals:   {"animals.txt", "more.txt"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Init() error = %s", err)
+	}
+
+	if got := wdb.Collections(language.LANG_EN); !reflect.DeepEqual(got, []WordCollection{animals}) {
+		t.Errorf("Collections() = %v, want [%s]", got, animals)
+	}
+	if got := wdb.CollectionTags(language.LANG_EN, animals); !reflect.DeepEqual(got, []string{"animals", "kids-friendly"}) {
+		t.Errorf("CollectionTags() = %v, want [animals kids-friendly]", got)
+	}
+	if !wdb.HasCollection(language.LANG_EN, "") || wdb.HasCollection(language.LANG_EN, WC_ALL) || wdb.HasCollection(language.LANG_DE, animals) {
+		t.Errorf("HasCollection() offers built in collections or collections of other languages")
+	}
+	if !wdb.Exists(language.LANG_EN, otter) {
+		t.Errorf("themed word '%s' not accepted as guess", otter)
+	}
+
+	// themes must not widen the guesses of every game
+	err = (&WordDatabase{}).Init(fstest.MapFS{
+		"all.txt":     {Data: []byte("# metadata\ngamer\n")},
+		"animals.txt": {Data: []byte("// animals | tags: animals\notter\n")},
+	}, map[language.Language]map[WordCollection][]string{
+		language.LANG_EN: {WC_ALL: {"all.txt"}, animals: {"animals.txt"}},
+	})
+	if err == nil {
+		t.Errorf("Init() accepted a themed word missing in the word lists")
+	}
+
+	rnd := random.New(1)
+	for range 10 {
+		w, err := wdb.RandomPick(rnd, language.LANG_EN, Pick{Collection: animals}, []Word{}, 0)
+		if err != nil {
+			t.Fatalf("RandomPick() error = %s", err)
+		}
+		if w != otter && w != tiger {
+			t.Fatalf("RandomPick() = %s, want an animal", w)
+		}
+	}
+
+	if _, err := wdb.RandomPick(rnd, language.LANG_EN, Pick{Collection: "plants"}, []Word{}, 0); !errors.Is(err, ErrUnknownCollection) {
+		t.Errorf("RandomPick() of an unknown collection error = %v, want %s", err, ErrUnknownCollection)
+	}
+	if _, err := wdb.RandomPick(rnd, language.LANG_EN, Pick{Collection: WC_GUESS_ONLY}, []Word{}, 0); !errors.Is(err, ErrUnknownCollection) {
+		t.Errorf("RandomPick() of %s error = %v, want %s", WC_GUESS_ONLY, err, ErrUnknownCollection)
+	}
+
+	// used up collections fall back to the default solutions
+	if w := wdb.RandomPickWithFallback(rnd, language.LANG_EN, Pick{Collection: animals}, []Word{otter, tiger}, 0); w != (Word{'c', 'r', 'i', 'e', 'd'}) {
+		t.Errorf("RandomPickWithFallback() = %s, want cried", w)
+	}
+}
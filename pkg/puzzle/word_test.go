@@ -0,0 +1,52 @@
+package puzzle
+
+import (
+	"testing"
+
+	"github.com/pandorasNox/lettr/pkg/language"
+)
+
+func TestSliceToWord(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []string
+		want    Word
+		wantErr bool
+	}{
+		{name: "lowercases", in: []string{"H", "a", "L", "l", "O"}, want: Word{'h', 'a', 'l', 'l', 'o'}},
+		{name: "composes decomposed umlaut", in: []string{"B", "a\u0308", "r", "e", "n"}, want: Word{'b', 'ä', 'r', 'e', 'n'}},
+		{name: "uppercase sharp s", in: []string{"M", "A", "ẞ", "E", "N"}, want: Word{'m', 'a', 'ß', 'e', 'n'}},
+		{name: "empty cells", in: make([]string, 5), want: Word{}},
+		{name: "more than one letter per cell", in: []string{"m", "a", "ss", "e", "n"}, wantErr: true},
+		{name: "wrong length", in: []string{"m", "a"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SliceToWord(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SliceToWord() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("SliceToWord() = %q, want %q", got.String(), tt.want.String())
+			}
+		})
+	}
+}
+
+func TestFoldWord(t *testing.T) {
+	tests := []struct {
+		name string
+		w    Word
+		want Word
+	}{
+		{name: "umlauts", w: Word{'S', 'c', 'h', 'ö', 'n'}, want: Word{'s', 'c', 'h', 'o', 'n'}},
+		{name: "sharp s", w: Word{'m', 'a', 'ẞ', 'e', 'n'}, want: Word{'m', 'a', 'ß', 'e', 'n'}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FoldWord(language.LANG_DE)(tt.w); got != tt.want {
+				t.Errorf("FoldWord() = %q, want %q", got.String(), tt.want.String())
+			}
+		})
+	}
+}
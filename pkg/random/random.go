@@ -0,0 +1,47 @@
+package random
+
+import (
+	crand "crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+// Rand is the source for picks that only need to look random to a player
+// (solution words, letter hints). Security relevant values must use
+// SecureString instead.
+type Rand interface {
+	Intn(n int) int
+}
+
+// lockedRand guards a math/rand generator, which is not safe for concurrent
+// use on its own, because one Rand is shared by all requests.
+type lockedRand struct {
+	mutex sync.Mutex
+	r     *rand.Rand
+}
+
+// New returns a Rand seeded with seed. The same seed yields the same picks.
+func New(seed int64) Rand {
+	return &lockedRand{r: rand.New(rand.NewSource(seed))}
+}
+
+func (lr *lockedRand) Intn(n int) int {
+	lr.mutex.Lock()
+	defer lr.mutex.Unlock()
+
+	return lr.r.Intn(n)
+}
+
+// SecureString returns a hex string of the given length read from
+// crypto/rand.
+func SecureString(length int) (string, error) {
+	b := make([]byte, (length+1)/2)
+	_, err := crand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("SecureString: read from crypto/rand failed: %s", err)
+	}
+
+	return hex.EncodeToString(b)[:length], nil
+}
@@ -0,0 +1,36 @@
+package random
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestNew_isDeterministic(t *testing.T) {
+	a := New(42)
+	b := New(42)
+
+	for i := 0; i < 10; i++ {
+		if x, y := a.Intn(1000), b.Intn(1000); x != y {
+			t.Fatalf("pick %d differs for same seed: %d != %d", i, x, y)
+		}
+	}
+}
+
+func TestSecureString(t *testing.T) {
+	for _, length := range []int{1, 7, 42} {
+		s, err := SecureString(length)
+		if err != nil {
+			t.Fatalf("SecureString(%d) error = %s", length, err)
+		}
+
+		if !regexp.MustCompile(`^[0-9a-f]+$`).MatchString(s) || len(s) != length {
+			t.Errorf("SecureString(%d) = %q, want hex string of length %d", length, s, length)
+		}
+	}
+
+	a, _ := SecureString(42)
+	b, _ := SecureString(42)
+	if a == b {
+		t.Errorf("SecureString returned the same value twice: %q", a)
+	}
+}
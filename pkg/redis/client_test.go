@@ -0,0 +1,152 @@
+package redis
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestClient_Do(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c := NewClient(mr.Addr())
+	defer c.Close()
+
+	if reply, err := c.Do("SET", "foo", "bar\r\nbaz"); err != nil || reply != "OK" {
+		t.Fatalf("SET = %v, %v, want OK", reply, err)
+	}
+	if reply, err := c.Do("GET", "foo"); err != nil || reply != "bar\r\nbaz" {
+		t.Errorf("GET = %q, %v, want the stored value", reply, err)
+	}
+	if _, err := c.Do("GET", "missing"); !errors.Is(err, ErrNil) {
+		t.Errorf("GET of a missing key error = %v, want %s", err, ErrNil)
+	}
+	if reply, err := c.Do("INCR", "counter"); err != nil || reply != int64(1) {
+		t.Errorf("INCR = %v, %v, want 1", reply, err)
+	}
+
+	var replyErr Error
+	if _, err := c.Do("INCR", "foo"); !errors.As(err, &replyErr) {
+		t.Errorf("INCR of a string error = %v, want an error reply", err)
+	}
+
+	reply, err := c.Do("HMGET", "hash", "a", "b")
+	if err != nil || !reflect.DeepEqual(reply, []any{nil, nil}) {
+		t.Errorf("HMGET of a missing hash = %v, %v, want two nil replies", reply, err)
+	}
+
+	// connections stay usable after error replies
+	if reply, err := c.Do("PING"); err != nil || reply != "PONG" {
+		t.Errorf("PING = %v, %v, want PONG", reply, err)
+	}
+}
+
+func TestScript_Run(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c := NewClient(mr.Addr())
+	defer c.Close()
+
+	incrBy := NewScript(`return redis.call('INCRBY', KEYS[1], ARGV[1])`)
+
+	for want := int64(2); want <= 4; want += 2 {
+		reply, err := incrBy.Run(c, []string{"counter"}, "2")
+		if err != nil || reply != want {
+			t.Errorf("Run() = %v, %v, want %d", reply, err, want)
+		}
+	}
+
+	// a restarted server lost its scripts
+	mr.Restart()
+	mr.FlushAll()
+	if reply, err := incrBy.Run(c, []string{"counter"}, "3"); err != nil || reply != int64(3) {
+		t.Errorf("Run() after restart = %v, %v, want 3", reply, err)
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	mr := miniredis.RunT(t)
+	mr.RequireUserAuth("lettr", "secret")
+	mr.Select(2)
+	mr.Set("foo", "bar")
+
+	c, err := ParseURL("redis://lettr:secret@" + mr.Addr() + "/2")
+	if err != nil {
+		t.Fatalf("ParseURL() error = %s", err)
+	}
+	defer c.Close()
+
+	if reply, err := c.Do("GET", "foo"); err != nil || reply != "bar" {
+		t.Errorf("GET = %v, %v, want the value of database 2", reply, err)
+	}
+
+	c, _ = ParseURL("redis://lettr:wrong@" + mr.Addr())
+	if _, err := c.Do("PING"); err == nil {
+		t.Errorf("PING with a wrong password succeeded")
+	}
+
+	for _, u := range []string{"http://localhost", "redis://localhost/db"} {
+		if _, err := ParseURL(u); err == nil {
+			t.Errorf("ParseURL(%s) succeeded", u)
+		}
+	}
+}
+
+func TestClient_Do_arrays(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c := NewClient(mr.Addr())
+	defer c.Close()
+
+	c.Do("SET", "foo", "bar")
+	c.Do("MULTI")
+	c.Do("INCR", "foo")
+	c.Do("GET", "foo")
+	reply, err := c.Do("EXEC")
+	if err != nil {
+		t.Fatalf("EXEC error = %s", err)
+	}
+
+	replies, _ := reply.([]any)
+	var replyErr Error
+	if len(replies) != 2 || !errors.As(replies[0].(error), &replyErr) || replies[1] != "bar" {
+		t.Errorf("EXEC = %v, want the error reply of INCR and the value of GET", reply)
+	}
+
+	// the whole array got read
+	if reply, err := c.Do("PING"); err != nil || reply != "PONG" {
+		t.Errorf("PING = %v, %v, want PONG", reply, err)
+	}
+}
+
+func TestClient_Do_protocolError(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	go func() {
+		nc, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer nc.Close()
+
+		// an array broken after its first element, followed by unread data
+		bufio.NewReader(nc).ReadString('\n')
+		io.WriteString(nc, "*3\r\n:1\r\n?\r\n:3\r\n")
+	}()
+
+	c := NewClient(l.Addr().String())
+	defer c.Close()
+
+	if _, err := c.Do("PING"); err == nil {
+		t.Fatalf("Do() of a broken reply succeeded")
+	}
+	if len(c.idle) != 0 {
+		t.Errorf("connection with unread data put back into the pool")
+	}
+}
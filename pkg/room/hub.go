@@ -0,0 +1,290 @@
+package room
+
+import (
+	crand "crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/clock"
+	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/random"
+)
+
+const CODE_LENGTH = 6
+
+// codeAlphabet leaves out letters and digits which are easily mixed up.
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// MAX_PLAYERS limits a room, so the progress board stays readable.
+const MAX_PLAYERS = 8
+
+// ROOM_TTL is how long a room is kept after it was created.
+const ROOM_TTL = 2 * time.Hour
+
+var (
+	ErrRoomNotFound   = errors.New("room not found")
+	ErrRoomFull       = errors.New("room is full")
+	ErrAlreadyStarted = errors.New("room already started")
+	ErrNotRunning     = errors.New("room is not running")
+	ErrNotHost        = errors.New("only the host can do this")
+	ErrNotAPlayer     = errors.New("not a player of this room")
+	ErrPlayerFinished = errors.New("player already finished")
+)
+
+// Hub holds all rooms and notifies subscribers whenever a room changes.
+type Hub struct {
+	mutex       sync.Mutex
+	rooms       map[string]*Room
+	subscribers map[string]map[chan struct{}]bool
+	clock       clock.Clock
+	rnd         random.Rand
+}
+
+func NewHub(c clock.Clock, rnd random.Rand) *Hub {
+	return &Hub{
+		rooms:       map[string]*Room{},
+		subscribers: map[string]map[chan struct{}]bool{},
+		clock:       c,
+		rnd:         rnd,
+	}
+}
+
+// Create opens a room in language l with the host as first player. The
+// solution is picked from the common words of wdb.
+func (h *Hub) Create(hostID string, l language.Language, wdb puzzle.WordDatabase, timeLimit time.Duration) (Room, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.removeExpired()
+
+	code, err := h.newCode()
+	if err != nil {
+		return Room{}, fmt.Errorf("creating room code failed: %s", err)
+	}
+	r := &Room{
+		Code:      code,
+		HostID:    hostID,
+		Language:  l,
+		State:     STATE_WAITING,
+		TimeLimit: timeLimit,
+		CreatedAt: h.clock.Now(),
+		Players:   []Player{{SessionID: hostID, Number: 1}},
+		solution:  wdb.RandomPickWithFallback(h.rnd, l, puzzle.Pick{}, []puzzle.Word{}, 0),
+	}
+	h.rooms[code] = r
+
+	return r.clone(), nil
+}
+
+// Join adds the session as player, joining twice is a no-op.
+func (h *Hub) Join(code string, sessionID string) (Room, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	r, err := h.room(code)
+	if err != nil {
+		return Room{}, err
+	}
+
+	if _, ok := r.Player(sessionID); ok {
+		return r.clone(), nil
+	}
+
+	if r.State != STATE_WAITING {
+		return Room{}, ErrAlreadyStarted
+	}
+
+	if len(r.Players) >= MAX_PLAYERS {
+		return Room{}, ErrRoomFull
+	}
+
+	r.Players = append(r.Players, Player{SessionID: sessionID, Number: len(r.Players) + 1})
+	h.notify(r.Code)
+
+	return r.clone(), nil
+}
+
+// Start begins the race, only the host may start it.
+func (h *Hub) Start(code string, sessionID string) (Room, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	r, err := h.room(code)
+	if err != nil {
+		return Room{}, err
+	}
+
+	if r.HostID != sessionID {
+		return Room{}, ErrNotHost
+	}
+
+	if r.State != STATE_WAITING {
+		return Room{}, ErrAlreadyStarted
+	}
+
+	r.State = STATE_RUNNING
+	r.StartedAt = h.clock.Now()
+	r.Deadline = r.StartedAt.Add(r.TimeLimit)
+	h.notify(r.Code)
+
+	// wake up subscribers once time runs out, nobody might guess anymore
+	h.clock.AfterFunc(r.TimeLimit, func() {
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+
+		if r, err := h.room(code); err == nil && r.State == STATE_FINISHED {
+			h.notify(code)
+		}
+	})
+
+	return r.clone(), nil
+}
+
+// Guess evaluates w against the solution of the room and stores it as the
+// next row of the player. The caller is responsible for checking that w is
+// part of the word list.
+func (h *Hub) Guess(code string, sessionID string, w puzzle.Word) (Room, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	r, err := h.room(code)
+	if err != nil {
+		return Room{}, err
+	}
+
+	if r.State != STATE_RUNNING {
+		return Room{}, ErrNotRunning
+	}
+
+	i := -1
+	for pi, p := range r.Players {
+		if p.SessionID == sessionID {
+			i = pi
+		}
+	}
+	if i == -1 {
+		return Room{}, ErrNotAPlayer
+	}
+
+	p := &r.Players[i]
+	if p.IsFinished() {
+		return Room{}, ErrPlayerFinished
+	}
+
+	p.Puzzle.Guesses[p.Puzzle.ActiveRow()] = puzzle.EvaluateGuessedWord(w, r.solution)
+	if p.IsFinished() {
+		p.FinishedAt = h.clock.Now()
+	}
+
+	if r.allFinished() {
+		r.State = STATE_FINISHED
+	}
+	h.notify(r.Code)
+
+	return r.clone(), nil
+}
+
+func (h *Hub) Get(code string) (Room, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	r, err := h.room(code)
+	if err != nil {
+		return Room{}, err
+	}
+
+	return r.clone(), nil
+}
+
+// Subscribe returns a channel receiving a value whenever the room changes.
+// Notifications are coalesced, so receivers always have to re-read the room
+// via Get. Call unsubscribe once done listening.
+func (h *Hub) Subscribe(code string) (updates <-chan struct{}, unsubscribe func(), err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if _, err := h.room(code); err != nil {
+		return nil, nil, err
+	}
+
+	c := make(chan struct{}, 1)
+	if h.subscribers[code] == nil {
+		h.subscribers[code] = map[chan struct{}]bool{}
+	}
+	h.subscribers[code][c] = true
+
+	unsubscribe = func() {
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+
+		delete(h.subscribers[code], c)
+	}
+
+	return c, unsubscribe, nil
+}
+
+// room returns the room for code, finishing it first if time ran out.
+// Callers must hold the mutex.
+func (h *Hub) room(code string) (*Room, error) {
+	r, ok := h.rooms[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
+
+	if r.State == STATE_RUNNING && !h.clock.Now().Before(r.Deadline) {
+		r.State = STATE_FINISHED
+	}
+
+	return r, nil
+}
+
+func (h *Hub) notify(code string) {
+	for c := range h.subscribers[code] {
+		select {
+		case c <- struct{}{}:
+		default: // a notification is already pending
+		}
+	}
+}
+
+// newCode returns an unused room code read from crypto/rand, codes are the
+// only thing keeping strangers out of a room.
+func (h *Hub) newCode() (string, error) {
+	// bytes from limit on would make the first letters more likely
+	limit := 256 - 256%len(codeAlphabet)
+
+	for {
+		b := strings.Builder{}
+		buf := make([]byte, CODE_LENGTH)
+		for b.Len() < CODE_LENGTH {
+			_, err := crand.Read(buf)
+			if err != nil {
+				return "", fmt.Errorf("room hub: reading random code failed: %s", err)
+			}
+
+			for _, r := range buf {
+				if int(r) < limit && b.Len() < CODE_LENGTH {
+					b.WriteByte(codeAlphabet[int(r)%len(codeAlphabet)])
+				}
+			}
+		}
+
+		if _, taken := h.rooms[b.String()]; !taken {
+			return b.String(), nil
+		}
+	}
+}
+
+func (h *Hub) removeExpired() {
+	now := h.clock.Now()
+	for code, r := range h.rooms {
+		if now.Sub(r.CreatedAt) > ROOM_TTL {
+			delete(h.rooms, code)
+			delete(h.subscribers, code)
+		}
+	}
+}
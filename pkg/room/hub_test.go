@@ -0,0 +1,207 @@
+package room
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/clock"
+	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/random"
+)
+
+var (
+	cried = puzzle.Word{'c', 'r', 'i', 'e', 'd'}
+	tried = puzzle.Word{'t', 'r', 'i', 'e', 'd'}
+	gamer = puzzle.Word{'g', 'a', 'm', 'e', 'r'}
+)
+
+func newTestHub(t *testing.T) (*Hub, *clock.Fake, puzzle.WordDatabase) {
+	t.Helper()
+
+	wdb := puzzle.WordDatabase{Db: map[language.Language]map[puzzle.WordCollection]map[puzzle.Word]bool{
+		language.LANG_EN: {
+			puzzle.WC_COMMON: {cried: true},
+			puzzle.WC_ALL:    {cried: true, tried: true, gamer: true},
+		},
+	}}
+	c := clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	return NewHub(c, random.New(1)), c, wdb
+}
+
+func TestHub_race(t *testing.T) {
+	h, c, wdb := newTestHub(t)
+
+	r, _ := h.Create("host", language.LANG_EN, wdb, 5*time.Minute)
+	if len(r.Code) != CODE_LENGTH {
+		t.Fatalf("Create() code = %q, want length %d", r.Code, CODE_LENGTH)
+	}
+	if r.Solution() != cried {
+		t.Fatalf("Create() solution = %s, want %s", r.Solution(), cried)
+	}
+
+	if _, err := h.Guess(r.Code, "host", cried); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("Guess() before start error = %v, want %v", err, ErrNotRunning)
+	}
+
+	r, err := h.Join(r.Code, "guest")
+	if err != nil {
+		t.Fatalf("Join() error = %s", err)
+	}
+	if len(r.Players) != 2 || r.Players[1].Number != 2 {
+		t.Fatalf("Join() players = %v, want host and guest", r.Players)
+	}
+
+	if _, err := h.Start(r.Code, "guest"); !errors.Is(err, ErrNotHost) {
+		t.Fatalf("Start() by guest error = %v, want %v", err, ErrNotHost)
+	}
+	if _, err := h.Start(r.Code, "host"); err != nil {
+		t.Fatalf("Start() error = %s", err)
+	}
+	if _, err := h.Join(r.Code, "late"); !errors.Is(err, ErrAlreadyStarted) {
+		t.Fatalf("Join() after start error = %v, want %v", err, ErrAlreadyStarted)
+	}
+
+	c.Advance(10 * time.Second)
+	r, err = h.Guess(r.Code, "guest", tried)
+	if err != nil {
+		t.Fatalf("Guess() error = %s", err)
+	}
+	guest, _ := r.Player("guest")
+	want := Progress{puzzle.MatchNone, puzzle.MatchExact, puzzle.MatchExact, puzzle.MatchExact, puzzle.MatchExact}
+	if rows := guest.Rows(); len(rows) != 1 || rows[0] != want {
+		t.Fatalf("Player.Rows() = %v, want [%v]", rows, want)
+	}
+
+	c.Advance(10 * time.Second)
+	if r, _ = h.Guess(r.Code, "host", cried); r.State != STATE_RUNNING {
+		t.Fatalf("State = %s after host solved, want %s", r.State, STATE_RUNNING)
+	}
+	if _, err := h.Guess(r.Code, "host", cried); !errors.Is(err, ErrPlayerFinished) {
+		t.Fatalf("Guess() after solving error = %v, want %v", err, ErrPlayerFinished)
+	}
+
+	c.Advance(10 * time.Second)
+	r, _ = h.Guess(r.Code, "guest", cried)
+	if r.State != STATE_FINISHED {
+		t.Fatalf("State = %s after everyone solved, want %s", r.State, STATE_FINISHED)
+	}
+
+	results := r.Results()
+	if results[0].SessionID != "host" || results[1].SessionID != "guest" {
+		t.Errorf("Results() = %s, %s; want host, guest", results[0].SessionID, results[1].SessionID)
+	}
+}
+
+func TestHub_timeLimit(t *testing.T) {
+	h, c, wdb := newTestHub(t)
+
+	r, _ := h.Create("host", language.LANG_EN, wdb, time.Minute)
+	if _, err := h.Start(r.Code, "host"); err != nil {
+		t.Fatalf("Start() error = %s", err)
+	}
+
+	c.Advance(time.Minute)
+	r, _ = h.Get(r.Code)
+	if r.State != STATE_FINISHED {
+		t.Fatalf("State = %s after time limit, want %s", r.State, STATE_FINISHED)
+	}
+
+	if _, err := h.Guess(r.Code, "host", cried); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("Guess() after time limit error = %v, want %v", err, ErrNotRunning)
+	}
+}
+
+func TestHub_subscribe(t *testing.T) {
+	h, _, wdb := newTestHub(t)
+
+	r, _ := h.Create("host", language.LANG_EN, wdb, time.Minute)
+	updates, unsubscribe, err := h.Subscribe(r.Code)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %s", err)
+	}
+	defer unsubscribe()
+
+	h.Join(r.Code, "guest")
+	h.Join(r.Code, "other")
+
+	select {
+	case <-updates:
+	default:
+		t.Fatalf("Subscribe() no update received after join")
+	}
+
+	select {
+	case <-updates:
+		t.Fatalf("Subscribe() notifications are not coalesced")
+	default:
+	}
+
+	if _, _, err := h.Subscribe("NOPE00"); !errors.Is(err, ErrRoomNotFound) {
+		t.Errorf("Subscribe() unknown room error = %v, want %v", err, ErrRoomNotFound)
+	}
+}
+
+func TestHub_expiredRoomsAreRemoved(t *testing.T) {
+	h, c, wdb := newTestHub(t)
+
+	old, _ := h.Create("host", language.LANG_EN, wdb, time.Minute)
+	c.Advance(ROOM_TTL + time.Second)
+	h.Create("host", language.LANG_EN, wdb, time.Minute)
+
+	if _, err := h.Get(old.Code); !errors.Is(err, ErrRoomNotFound) {
+		t.Errorf("Get() expired room error = %v, want %v", err, ErrRoomNotFound)
+	}
+}
+
+func TestHub_timeLimitNotifies(t *testing.T) {
+	h, c, wdb := newTestHub(t)
+
+	r, _ := h.Create("host", language.LANG_EN, wdb, time.Minute)
+	if _, err := h.Start(r.Code, "host"); err != nil {
+		t.Fatalf("Start() error = %s", err)
+	}
+
+	updates, unsubscribe, err := h.Subscribe(r.Code)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %s", err)
+	}
+	defer unsubscribe()
+
+	c.Advance(time.Minute - time.Second)
+	select {
+	case <-updates:
+		t.Fatalf("Subscribe() update received before the time limit")
+	default:
+	}
+
+	c.Advance(time.Second)
+	select {
+	case <-updates:
+	default:
+		t.Errorf("Subscribe() no update received once time ran out")
+	}
+}
+
+func TestHub_codes(t *testing.T) {
+	h, _, wdb := newTestHub(t)
+
+	codes := map[string]bool{}
+	for range 20 {
+		r, err := h.Create("host", language.LANG_EN, wdb, time.Minute)
+		if err != nil {
+			t.Fatalf("Create() error = %s", err)
+		}
+		if len(r.Code) != CODE_LENGTH || strings.Trim(r.Code, codeAlphabet) != "" {
+			t.Fatalf("Create() code = %q, want %d letters of %q", r.Code, CODE_LENGTH, codeAlphabet)
+		}
+		codes[r.Code] = true
+	}
+
+	if len(codes) != 20 {
+		t.Errorf("Create() codes = %v, want all different", codes)
+	}
+}
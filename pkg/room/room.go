@@ -0,0 +1,140 @@
+// Package room lets several players race each other on the same solution
+// word. Rooms live in memory only, they are handed out by a Hub.
+package room
+
+import (
+	"slices"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+)
+
+type State string
+
+const (
+	STATE_WAITING  State = "waiting"
+	STATE_RUNNING  State = "running"
+	STATE_FINISHED State = "finished"
+)
+
+// Progress is a guessed row without its letters, so opponents can be shown
+// without spoiling the solution.
+type Progress [5]puzzle.Match
+
+type Player struct {
+	SessionID  string
+	Number     int
+	Puzzle     puzzle.Puzzle
+	FinishedAt time.Time
+}
+
+// Rows returns the letter free progress of all filled rows.
+func (p Player) Rows() []Progress {
+	rows := []Progress{}
+	for _, wg := range p.Puzzle.Guesses {
+		lgs := wg.LetterGuesses()
+		if len(lgs) == 0 {
+			break
+		}
+
+		row := Progress{}
+		for i, lg := range lgs {
+			row[i] = lg.Match
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func (p Player) IsSolved() bool {
+	return p.Puzzle.IsSolved()
+}
+
+func (p Player) IsFinished() bool {
+	return p.Puzzle.IsSolved() || p.Puzzle.IsLoose()
+}
+
+// Room is a snapshot of a race, changing it has no effect on the Hub.
+type Room struct {
+	Code      string
+	HostID    string
+	Language  language.Language
+	State     State
+	TimeLimit time.Duration
+	CreatedAt time.Time
+	StartedAt time.Time
+	Deadline  time.Time
+	Players   []Player
+
+	solution puzzle.Word
+}
+
+func (r Room) IsWaiting() bool {
+	return r.State == STATE_WAITING
+}
+
+func (r Room) IsRunning() bool {
+	return r.State == STATE_RUNNING
+}
+
+func (r Room) IsFinished() bool {
+	return r.State == STATE_FINISHED
+}
+
+// Solution returns the word everyone is guessing. Only reveal it once the
+// room is finished.
+func (r Room) Solution() puzzle.Word {
+	return r.solution
+}
+
+func (r Room) Player(sessionID string) (Player, bool) {
+	i := slices.IndexFunc(r.Players, func(p Player) bool {
+		return p.SessionID == sessionID
+	})
+	if i == -1 {
+		return Player{}, false
+	}
+
+	return r.Players[i], true
+}
+
+// Results returns the players ranked: solvers before the rest, fewer rows
+// before more rows and earlier finishers before later ones.
+func (r Room) Results() []Player {
+	ranked := slices.Clone(r.Players)
+	slices.SortStableFunc(ranked, func(a, b Player) int {
+		if a.IsSolved() != b.IsSolved() {
+			if a.IsSolved() {
+				return -1
+			}
+			return 1
+		}
+
+		if a.IsSolved() {
+			if d := len(a.Rows()) - len(b.Rows()); d != 0 {
+				return d
+			}
+		}
+
+		return a.FinishedAt.Compare(b.FinishedAt)
+	})
+
+	return ranked
+}
+
+func (r Room) allFinished() bool {
+	for _, p := range r.Players {
+		if !p.IsFinished() {
+			return false
+		}
+	}
+
+	return len(r.Players) > 0
+}
+
+func (r Room) clone() Room {
+	r.Players = slices.Clone(r.Players)
+	return r
+}
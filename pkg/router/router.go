@@ -5,41 +5,63 @@ import (
 	"net/http"
 
 	"github.com/pandorasNox/lettr/pkg/middleware"
-	"github.com/pandorasNox/lettr/pkg/puzzle"
 	"github.com/pandorasNox/lettr/pkg/router/routes"
-	"github.com/pandorasNox/lettr/pkg/server"
-	"github.com/pandorasNox/lettr/pkg/session"
 )
 
 type Router struct {
 	mux http.ServeMux
 }
 
-func New(staticFS iofs.FS, server *server.Server, sessions *session.Sessions, wordDb puzzle.WordDatabase, imprintUrl string, githubToken string, revision string, faviconPath string) http.Handler {
+func New(staticFS iofs.FS, app *routes.App) http.Handler {
 	mux := http.NewServeMux()
 
-	mux = addRoutes(mux, staticFS, server, sessions, wordDb, imprintUrl, githubToken, revision, faviconPath)
+	mux = addRoutes(mux, staticFS, app)
 
 	handlerWithRoutesWithMiddlewares := addMiddlewares(mux)
 
 	return handlerWithRoutesWithMiddlewares
 }
 
-func addRoutes(mux *http.ServeMux, staticFS iofs.FS, server *server.Server, sessions *session.Sessions, wordDb puzzle.WordDatabase, imprintUrl string, githubToken string, revision string, faviconPath string) *http.ServeMux {
+func addRoutes(mux *http.ServeMux, staticFS iofs.FS, app *routes.App) *http.ServeMux {
 	mux.HandleFunc("GET /static/", routes.Static(staticFS))
-	mux.HandleFunc("GET /", routes.Index(sessions, wordDb, imprintUrl, revision, faviconPath))
-	mux.HandleFunc("GET /letter-hint", routes.LetterHint(sessions, wordDb))
-	mux.HandleFunc("GET /lettr", routes.GetLettr(sessions, wordDb, imprintUrl, revision, faviconPath))
-	mux.HandleFunc("POST /lettr", routes.PostLettr(sessions, wordDb, imprintUrl, revision, faviconPath))
-	mux.HandleFunc("POST /new", routes.PostNew(sessions, wordDb, imprintUrl, revision, faviconPath))
-	mux.HandleFunc("POST /help", routes.Help(sessions, wordDb))
-	mux.HandleFunc("GET /suggest", routes.GetSuggest(sessions, wordDb))
-	mux.HandleFunc("POST /suggest", routes.PostSuggest(githubToken, sessions, wordDb, server))
-	mux.HandleFunc("GET /metrics", routes.GetMetrics(server))
+	mux.HandleFunc("GET /", app.Index())
+	mux.HandleFunc("GET /letter-hint", app.LetterHint())
+	mux.HandleFunc("GET /lettr", app.GetLettr())
+	mux.HandleFunc("POST /lettr", app.PostLettr())
+	mux.HandleFunc("POST /lettr/boards", app.PostLettrBoards())
+	mux.HandleFunc("POST /new", app.PostNew())
+	mux.HandleFunc("POST /help", app.Help())
+	mux.HandleFunc("POST /keyboard-layout", app.PostKeyboardLayout())
+	mux.HandleFunc("GET /suggest", app.GetSuggest())
+	mux.HandleFunc("POST /suggest", app.PostSuggest())
+	mux.HandleFunc("GET /rooms", app.GetRooms())
+	mux.HandleFunc("POST /rooms", app.PostRooms())
+	mux.HandleFunc("POST /rooms/join", app.PostRoomJoin())
+	mux.HandleFunc("GET /rooms/{code}", app.GetRoom())
+	mux.HandleFunc("POST /rooms/{code}/start", app.PostRoomStart())
+	mux.HandleFunc("POST /rooms/{code}/guess", app.PostRoomGuess())
+	mux.HandleFunc("GET /rooms/{code}/events", app.GetRoomEvents())
+	mux.HandleFunc("GET /leaderboard", app.GetLeaderboard())
+	mux.HandleFunc("POST /nickname", app.PostNickname())
+	mux.HandleFunc("GET /api/leaderboard", app.GetLeaderboardAPI())
+	mux.HandleFunc("GET /challenge", app.GetChallenge())
+	mux.HandleFunc("POST /challenge", app.PostChallenge())
+	mux.HandleFunc("GET /c/{token}", app.GetChallengeToken())
+	mux.HandleFunc("GET /account", app.GetAccount())
+	mux.HandleFunc("POST /account/login", app.PostAccountLogin())
+	mux.HandleFunc("GET /account/login/{token}", app.GetAccountLoginToken())
+	mux.HandleFunc("POST /account/login/{token}", app.PostAccountLoginToken())
+	mux.HandleFunc("POST /account/logout", app.PostAccountLogout())
+	mux.HandleFunc("POST /account/delete", app.PostAccountDelete())
+	mux.HandleFunc("GET /account/export", app.GetAccountExport())
+	mux.HandleFunc("GET /history", app.GetHistory())
+	mux.HandleFunc("GET /history/export", app.GetHistoryExport())
+	mux.HandleFunc("GET /history/{index}", app.GetHistoryReplay())
+	mux.HandleFunc("GET /metrics", app.GetMetrics())
 
 	// add tesing routes
 	// mux.HandleFunc("GET /test", routes.GetTestPage())
-	// mux.HandleFunc("POST /test/honey/increment", routes.PostIncrementHoneyTrapped(server))
+	// mux.HandleFunc("POST /test/honey/increment", app.PostIncrementHoneyTrapped())
 
 	return mux
 }
@@ -0,0 +1,61 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pandorasNox/lettr/pkg/clock"
+	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/random"
+	"github.com/pandorasNox/lettr/pkg/router/routes"
+	"github.com/pandorasNox/lettr/pkg/server"
+	"github.com/pandorasNox/lettr/pkg/session"
+)
+
+func TestNew_servesIndexWithoutMain(t *testing.T) {
+	mockFs := fstest.MapFS{
+		"common.txt": {
+			Data: []byte(`# metadata
+cried
+`),
+		},
+	}
+
+	wordDb := puzzle.WordDatabase{}
+	err := wordDb.Init(mockFs, map[language.Language]map[puzzle.WordCollection][]string{
+		language.LANG_EN: {
+			puzzle.WC_ALL:    {"common.txt"},
+			puzzle.WC_COMMON: {"common.txt"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("init wordDatabase failed: %s", err)
+	}
+
+	sessions := session.NewSessions(clock.Real{}, random.New(1))
+	app := routes.NewApp(&sessions, wordDb, routes.Config{Revision: "abcdef0"}, &server.Server{})
+
+	ts := httptest.NewServer(New(fstest.MapFS{}, app))
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/")
+	if err != nil {
+		t.Fatalf("GET / failed: %s", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("GET / status = %d, want %d", res.StatusCode, http.StatusOK)
+	}
+
+	// the go client counts as bot, which get the landing page without state
+	if len(res.Cookies()) != 0 {
+		t.Errorf("GET / of a bot expected no session cookie, got %v", res.Cookies())
+	}
+	if got := res.Header.Get("Cache-Control"); got != "public, max-age=3600" {
+		t.Errorf("GET / of a bot Cache-Control = '%s', want a cacheable page", got)
+	}
+}
@@ -0,0 +1,122 @@
+package routertest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+	"testing/fstest"
+)
+
+var loginLinkRegexp = regexp.MustCompile(`https://[^/\s]+(/account/login/[0-9a-f]+)`)
+
+type accountExport struct {
+	Email    string `json:"email"`
+	Progress struct {
+		PastWords []string `json:"pastWords"`
+		Nickname  string   `json:"nickname"`
+		Streak    int      `json:"streak"`
+	} `json:"progress"`
+}
+
+// login requests a login link for email and follows it, like a player
+// clicking the link in the mail.
+func login(t *testing.T, h *Harness, email string) string {
+	t.Helper()
+
+	res := h.Post("/account/login", url.Values{"email": {email}})
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "Login link sent")
+
+	mail, ok := h.Mailbox.Last()
+	if !ok || mail.To != email {
+		t.Fatalf("no login mail to %s, last mail: %+v", email, mail)
+	}
+	m := loginLinkRegexp.FindStringSubmatch(mail.Body)
+	if m == nil {
+		t.Fatalf("no login link in mail\nbody:\n%s", mail.Body)
+	}
+
+	res = h.Get(m[1])
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, `action="`+m[1]+`"`)
+
+	res = h.Post(m[1], url.Values{})
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, `id="lettr-container"`)
+
+	return m[1]
+}
+
+func getAccountExport(t *testing.T, h *Harness) accountExport {
+	t.Helper()
+
+	res := h.Get("/account/export")
+	AssertStatus(t, res, http.StatusOK)
+	AssertHeader(t, res, "Content-Disposition", `attachment; filename="lettr-account.json"`)
+
+	export := accountExport{}
+	err := json.Unmarshal([]byte(res.Body), &export)
+	if err != nil {
+		t.Fatalf("decoding account export failed: %s\nbody:\n%s", err, res.Body)
+	}
+
+	return export
+}
+
+func TestAccountFlow(t *testing.T) {
+	f := DefaultFixture()
+	f.Files["en-common.txt"] = &fstest.MapFile{Data: []byte("# metadata\ncried\ntried\nfried\npried\ndried\n")}
+
+	laptop := New(t, f)
+	laptop.Start()
+
+	res := laptop.Get("/account")
+	AssertContains(t, res, "Send login link")
+
+	// anonymous progress before the account exists
+	first := laptop.SolutionWord()
+	AssertContains(t, laptop.Guess(first), "SOLVED")
+	laptop.NewGame()
+	AssertStatus(t, laptop.Post("/nickname", url.Values{"nickname": {"Laptop Player"}}), http.StatusOK)
+
+	res = laptop.Post("/account/login", url.Values{"email": {"not an email"}})
+	AssertStatus(t, res, http.StatusUnprocessableEntity)
+	AssertContains(t, res, "email address invalid")
+
+	link := login(t, laptop, "player@example.com")
+	AssertContains(t, laptop.Get("/account"), "Signed in as player@example.com")
+
+	res = laptop.Post(link, url.Values{})
+	AssertStatus(t, res, http.StatusUnprocessableEntity)
+	AssertContains(t, res, "invalid or expired")
+
+	// a second device picks up the progress of the first one
+	phone := laptop.NewPlayer()
+	phone.Start()
+	AssertContains(t, phone.Get("/leaderboard"), `value=""`)
+	login(t, phone, "player@example.com")
+
+	export := getAccountExport(t, phone)
+	if export.Email != "player@example.com" || export.Progress.Nickname != "Laptop Player" || export.Progress.Streak != 1 {
+		t.Errorf("export = %+v, want the progress of the laptop", export)
+	}
+	if len(export.Progress.PastWords) != 1 || export.Progress.PastWords[0] != first {
+		t.Errorf("export past words = %v, want [%s]", export.Progress.PastWords, first)
+	}
+	AssertContains(t, phone.Get("/leaderboard"), `value="Laptop Player"`)
+
+	// and changes on one device show up on the other
+	AssertStatus(t, phone.Post("/nickname", url.Values{"nickname": {"Phone Player"}}), http.StatusOK)
+	AssertContains(t, laptop.Get("/leaderboard"), `value="Phone Player"`)
+
+	res = phone.Post("/account/delete", url.Values{})
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "Your account was deleted.")
+	AssertContains(t, res, "Send login link")
+
+	AssertContains(t, laptop.Get("/account"), "Send login link")
+	AssertStatus(t, laptop.Get("/account/export"), http.StatusUnauthorized)
+	AssertStatus(t, laptop.Post("/account/delete", url.Values{}), http.StatusUnprocessableEntity)
+}
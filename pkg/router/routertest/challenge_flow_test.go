@@ -0,0 +1,70 @@
+package routertest
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var challengeLinkRegexp = regexp.MustCompile(`value="https://[^/"]+(/c/[^"]+)"`)
+
+func TestChallengeFlow(t *testing.T) {
+	challenger := New(t, DefaultFixture())
+	challenger.Start()
+	recipient := challenger.NewPlayer()
+	recipient.Start()
+
+	res := challenger.Post("/challenge", url.Values{"word": {"Tried"}})
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "Nobody played it yet.")
+
+	m := challengeLinkRegexp.FindStringSubmatch(res.Body)
+	if m == nil {
+		t.Fatalf("no challenge link in response\nbody:\n%s", res.Body)
+	}
+	link := m[1]
+	if strings.Contains(strings.ToLower(link), "tried") {
+		t.Errorf("challenge link %q contains the word in clear text", link)
+	}
+
+	res = recipient.Get(link)
+	AssertStatus(t, res, http.StatusOK)
+	if got := recipient.SolutionWord(); got != "tried" {
+		t.Fatalf("solution = %q, want %q", got, "tried")
+	}
+
+	AssertStatus(t, recipient.Guess("fried"), http.StatusOK)
+	res = recipient.Guess("tried")
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "SOLVED")
+
+	res = challenger.Get("/challenge")
+	AssertNotContains(t, res, "Nobody played it yet.")
+	AssertContains(t, res, "Player 1")
+	AssertContains(t, res, ">f</div>")
+
+	res = recipient.Get("/challenge")
+	AssertNotContains(t, res, link)
+}
+
+func TestChallengeFlow_errors(t *testing.T) {
+	h := New(t, DefaultFixture())
+	h.Start()
+
+	res := h.Post("/challenge", url.Values{"word": {"xxxxx"}})
+	AssertStatus(t, res, http.StatusUnprocessableEntity)
+	AssertContains(t, res, "word not in word list")
+
+	res = h.Post("/challenge", url.Values{"word": {"tri"}})
+	AssertStatus(t, res, http.StatusUnprocessableEntity)
+	AssertContains(t, res, "Enter a word with five letters")
+
+	solution := h.SolutionWord()
+	res = h.Get("/c/invalid-token")
+	AssertStatus(t, res, http.StatusOK)
+	if got := h.SolutionWord(); got != solution {
+		t.Errorf("solution = %q after invalid token, want %q", got, solution)
+	}
+}
@@ -0,0 +1,432 @@
+package routertest
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/language"
+)
+
+func TestGameFlow_win(t *testing.T) {
+	h := New(t, DefaultFixture())
+
+	res := h.Start()
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, `id="lettr-container"`)
+	AssertContains(t, res, "unsolved")
+
+	if got := h.SolutionWord(); got != "cried" {
+		t.Fatalf("SolutionWord() = %q, want %q", got, "cried")
+	}
+
+	res = h.Guess("tried")
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "unsolved")
+	AssertContains(t, res, `value="t"`)
+
+	res = h.Guess("cried")
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "SOLVED")
+	AssertContains(t, res, "inert")
+
+	res = h.Guess("gamer")
+	AssertStatus(t, res, http.StatusUnprocessableEntity)
+	AssertContains(t, res, "This game is already over.")
+}
+
+func TestGameFlow_loose(t *testing.T) {
+	h := New(t, DefaultFixture())
+	h.Start()
+
+	for _, g := range []string{"gamer", "games", "tried", "fried", "pried"} {
+		res := h.Guess(g)
+		AssertStatus(t, res, http.StatusOK)
+		AssertContains(t, res, "unsolved")
+	}
+
+	res := h.Guess("dried")
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "YOU LOOSE")
+}
+
+func TestGameFlow_wordNotInWordList(t *testing.T) {
+	h := New(t, DefaultFixture())
+	h.Start()
+
+	res := h.Guess("zzzzz")
+	AssertStatus(t, res, http.StatusUnprocessableEntity)
+	AssertContains(t, res, `hx-swap-oob="outerHTML"`)
+	AssertContains(t, res, "word not in word list")
+}
+
+func TestGameFlow_staleRow(t *testing.T) {
+	h := New(t, DefaultFixture())
+	h.Start()
+
+	// a row ahead of the server, e.g. after the game got lost on a restart
+	res := h.GuessRow(3, "tried")
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "Your game was out of sync")
+	AssertContains(t, res, `name="row" value="0"`)
+	AssertNotContains(t, res, `value="t"`)
+
+	AssertStatus(t, h.Guess("tried"), http.StatusOK)
+
+	// a replayed submission (double submit, second tab) must not rewrite row 0
+	res = h.GuessRow(0, "gamer")
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "Your game was out of sync")
+	AssertContains(t, res, `name="row" value="1"`)
+	AssertContains(t, res, `value="t"`)
+	AssertNotContains(t, res, `value="g"`)
+
+	res = h.Post("/lettr", url.Values{"guess": {"c", "r", "i", "e", "d"}})
+	AssertStatus(t, res, http.StatusUnprocessableEntity)
+
+	res = h.Guess("cried")
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "SOLVED")
+}
+
+func TestGameFlow_hints(t *testing.T) {
+	h := New(t, DefaultFixture())
+	h.Start()
+
+	// all letters except 'c' are revealed by the guess
+	h.Guess("tried")
+
+	res := h.Hint()
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, ">c</span>")
+
+	res = h.Hint()
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "No more hints to provide")
+
+	res = h.Post("/help", url.Values{})
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "cried")
+}
+
+func TestGameFlow_gameOver(t *testing.T) {
+	h := New(t, DefaultFixture())
+	h.Start()
+
+	AssertContains(t, h.Guess(h.SolutionWord()), "SOLVED")
+
+	res := h.Hint()
+	AssertStatus(t, res, http.StatusUnprocessableEntity)
+	AssertContains(t, res, "This game is already over.")
+
+	res = h.Guess("tried")
+	AssertStatus(t, res, http.StatusUnprocessableEntity)
+	AssertContains(t, res, "This game is already over.")
+}
+
+func TestGameFlow_newGameAndLanguageSwitch(t *testing.T) {
+	h := New(t, DefaultFixture())
+	h.Start()
+	h.Guess("cried")
+
+	res := h.SwitchLanguage(language.LANG_DE)
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, `id="language-dropdown-menu"`)
+	AssertContains(t, res, "Deutsch")
+	AssertContains(t, res, "ungelöst")
+
+	if got := h.SolutionWord(); got != "hallo" {
+		t.Fatalf("SolutionWord() = %q, want %q", got, "hallo")
+	}
+
+	res = h.Guess("cried")
+	AssertStatus(t, res, http.StatusUnprocessableEntity)
+	AssertContains(t, res, "Wort nicht in der Wortliste")
+
+	res = h.Guess("hello")
+	AssertStatus(t, res, http.StatusOK)
+
+	res = h.NewGame()
+	AssertStatus(t, res, http.StatusOK)
+	AssertNotContains(t, res, "Deutsch")
+	AssertContains(t, res, "Neues Spiel")
+
+	res = h.Post("/help", url.Values{})
+	AssertContains(t, res, "cried")
+}
+
+func TestGameFlow_acceptLanguageOnFirstVisit(t *testing.T) {
+	h := New(t, DefaultFixture())
+	h.Header.Set("Accept-Language", "fr-FR,de;q=0.8,en;q=0.5")
+
+	res := h.Start()
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, `<html lang="de">`)
+	AssertContains(t, res, "Wort vorschlagen")
+
+	if got := h.SolutionWord(); got != "hallo" {
+		t.Fatalf("SolutionWord() = %q, want %q", got, "hallo")
+	}
+
+	// the negotiated language sticks to the session
+	h.Header.Set("Accept-Language", "en")
+	res = h.Get("/")
+	AssertContains(t, res, `<html lang="de">`)
+}
+
+func TestGameFlow_suggestValidationError(t *testing.T) {
+	h := New(t, DefaultFixture())
+	h.Start()
+
+	res := h.Get("/suggest")
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, `name="suggestion"`)
+
+	res = h.Post("/suggest", url.Values{"word": {"?????"}})
+	AssertStatus(t, res, http.StatusUnprocessableEntity)
+	AssertHeader(t, res, "HX-Reswap", "none")
+	AssertContains(t, res, "validation failed")
+}
+
+func TestGameFlow_keyboardLayout(t *testing.T) {
+	h := New(t, DefaultFixture())
+
+	res := h.SwitchLanguage(language.LANG_DE)
+	AssertContains(t, res, `<option value="qwertz" selected>`)
+	AssertContains(t, res, "Ä")
+
+	res = h.Post("/keyboard-layout", url.Values{"layout": {"dvorak"}})
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, `<option value="dvorak" selected>`)
+
+	res = h.Post("/keyboard-layout", url.Values{"layout": {"colemak"}})
+	AssertStatus(t, res, http.StatusUnprocessableEntity)
+
+	// the preference survives a reload
+	res = h.Get("/")
+	AssertContains(t, res, `<option value="dvorak" selected>`)
+}
+
+func TestGameFlow_speed(t *testing.T) {
+	// several common words, so every new game has a solution from the list
+	f := DefaultFixture()
+	f.Files["en-common.txt"] = &fstest.MapFile{Data: []byte("# metadata\ncried\ntried\nfried\npried\ndried\n")}
+
+	h := New(t, f)
+	h.Start()
+
+	res := h.NewSpeedGame()
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, `id="speed-timer" data-remaining="180"`)
+	AssertContains(t, res, "3:00")
+
+	h.Clock.Advance(20 * time.Second)
+	res = h.Guess("gamer")
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "2:40")
+
+	h.Clock.Advance(22 * time.Second)
+	res = h.Guess(h.SolutionWord())
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "SOLVED")
+	AssertContains(t, res, "Solved in 0:42")
+	AssertContains(t, res, "<li>0:20</li>")
+	AssertContains(t, res, "<li>0:22</li>")
+	AssertNotContains(t, res, "speed-timer")
+
+	t.Run("timeout is a loss", func(t *testing.T) {
+		res := h.NewSpeedGame()
+		AssertStatus(t, res, http.StatusOK)
+
+		h.Clock.Advance(3 * time.Minute)
+		res = h.Guess(h.SolutionWord())
+		AssertStatus(t, res, http.StatusOK)
+		AssertContains(t, res, "TIME&#39;S UP")
+		AssertNotContains(t, res, "SOLVED")
+		AssertContains(t, res, "inert")
+
+		res = h.Get("/lettr")
+		AssertContains(t, res, "TIME&#39;S UP")
+	})
+
+	t.Run("untimed game", func(t *testing.T) {
+		res := h.NewGame()
+		AssertNotContains(t, res, "speed-timer")
+
+		h.Clock.Advance(time.Hour)
+		res = h.Guess(h.SolutionWord())
+		AssertContains(t, res, "SOLVED")
+		AssertNotContains(t, res, "Solved in")
+	})
+}
+
+func TestGameFlow_multiBoard(t *testing.T) {
+	f := DefaultFixture()
+	f.Files["en-common.txt"] = &fstest.MapFile{Data: []byte("# metadata\ncried\ntried\nfried\npried\ndried\n")}
+
+	h := New(t, f)
+	h.Start()
+
+	res := h.NewMultiBoardGame(4)
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, `hx-post="/lettr/boards"`)
+	AssertContains(t, res, `<option value="4" selected>`)
+	AssertContains(t, res, "9 guesses left")
+
+	solutions := h.SolutionWords()
+	if len(solutions) != 4 {
+		t.Fatalf("SolutionWords() = %v, want 4 words", solutions)
+	}
+	distinct := slices.Clone(solutions)
+	slices.Sort(distinct)
+	if len(slices.Compact(distinct)) != 4 {
+		t.Errorf("SolutionWords() = %v, want distinct words", solutions)
+	}
+
+	// the single board form does not accept guesses for a multi board game
+	AssertStatus(t, h.Guess("gamer"), http.StatusUnprocessableEntity)
+
+	res = h.GuessBoards("zzzzz")
+	AssertStatus(t, res, http.StatusUnprocessableEntity)
+	AssertContains(t, res, "word not in word list")
+
+	res = h.GuessBoards("gamer")
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "8 guesses left")
+
+	for i, s := range solutions {
+		res = h.GuessBoards(s)
+		AssertStatus(t, res, http.StatusOK)
+		if i < len(solutions)-1 {
+			AssertContains(t, res, "unsolved")
+		}
+	}
+	AssertContains(t, res, "SOLVED")
+	AssertContains(t, res, "inert")
+
+	res = h.GuessBoards("gamer")
+	AssertStatus(t, res, http.StatusUnprocessableEntity)
+	AssertContains(t, res, "This game is already over.")
+
+	res = h.NewGame()
+	AssertNotContains(t, res, "/lettr/boards")
+}
+
+func TestGameFlow_survival(t *testing.T) {
+	f := DefaultFixture()
+	f.Files["en-common.txt"] = &fstest.MapFile{Data: []byte("# metadata\ncried\ntried\nfried\npried\ndried\n")}
+
+	h := New(t, f)
+	h.Start()
+
+	res := h.NewSurvivalGame()
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "Score: 0 (0 words)")
+
+	// first word in the second guess: 10 + 4 * 10 points
+	first := h.SolutionWord()
+	h.Guess("gamer")
+	res = h.Guess(first)
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "Solved! &#43;50 points")
+	AssertContains(t, res, "Score: 50 (1 words)")
+	AssertNotContains(t, res, "SOLVED")
+	h.ForgetGuesses()
+
+	if h.SolutionWord() == first {
+		t.Fatalf("solving a word did not start the next one")
+	}
+
+	// second word with a hint in the first guess: 10 + 5 * 10 - 5 points
+	AssertStatus(t, h.Hint(), http.StatusOK)
+	res = h.Guess(h.SolutionWord())
+	AssertContains(t, res, "Solved! &#43;55 points")
+	AssertContains(t, res, "Score: 105 (2 words)")
+	h.ForgetGuesses()
+
+	for _, g := range []string{"gamer", "games", "gamer", "games", "gamer"} {
+		AssertStatus(t, h.Guess(g), http.StatusOK)
+	}
+	res = h.Guess("games")
+	AssertContains(t, res, "YOU LOOSE")
+	AssertContains(t, res, "Run over! Final score: 105")
+	AssertContains(t, res, "New personal best: 105")
+	AssertContains(t, res, "rank 1 on the leaderboard")
+	AssertContains(t, res, "Best: 105")
+
+	res = h.Get("/leaderboard")
+	AssertContains(t, res, "105 points, 2 words (you)")
+
+	other := h.NewPlayer()
+	other.Start()
+	res = other.Get("/leaderboard")
+	AssertContains(t, res, "105 points, 2 words")
+	AssertNotContains(t, res, "(you)")
+
+	// a new run starts from zero but keeps the personal best
+	res = h.NewSurvivalGame()
+	AssertContains(t, res, "Score: 0 (0 words)")
+	AssertContains(t, res, "Best: 105")
+}
+
+func TestGameFlow_themedCollection(t *testing.T) {
+	h := New(t, DefaultFixture())
+	res := h.Start()
+	AssertContains(t, res, `id="collection-mode"`)
+	AssertContains(t, res, "Animals (animals, kids friendly)")
+
+	res = h.NewThemedGame("animals")
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, `<option value="animals" selected>`)
+	if got := h.SolutionWord(); got != "otter" {
+		t.Fatalf("SolutionWord() = %q, want %q", got, "otter")
+	}
+
+	// guesses are still checked against the full dictionary
+	res = h.Guess("tried")
+	AssertStatus(t, res, http.StatusOK)
+
+	// the pick sticks to following games, the only animal is used up
+	res = h.NewGame()
+	AssertContains(t, res, `<option value="animals" selected>`)
+	if got := h.SolutionWord(); got == "otter" {
+		t.Fatalf("SolutionWord() = %q, a used up collection must not repeat its words", got)
+	}
+
+	// built in collections can't be picked
+	res = h.NewThemedGame("wc_guess_only")
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, `<option value="" selected>`)
+
+	// german has no animals, switching the language drops the pick
+	h.NewThemedGame("animals")
+	res = h.SwitchLanguage(language.LANG_DE)
+	AssertNotContains(t, res, `id="collection-mode"`)
+	if got := h.SolutionWord(); got != "hallo" {
+		t.Fatalf("SolutionWord() = %q, want %q", got, "hallo")
+	}
+
+	res = h.SwitchLanguage(language.LANG_EN)
+	AssertContains(t, res, `<option value="" selected>`)
+}
+
+func TestGameFlow_difficulty(t *testing.T) {
+	h := New(t, DefaultFixture())
+	res := h.Start()
+	AssertContains(t, res, `id="difficulty-mode"`)
+	AssertContains(t, res, `<option value="" selected>any difficulty</option>`)
+
+	res = h.Post("/new", url.Values{"difficulty": {"hard"}})
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, `<option value="hard" selected>hard words</option>`)
+
+	// the pick sticks to following games, unknown difficulties reset it
+	res = h.NewGame()
+	AssertContains(t, res, `<option value="hard" selected>hard words</option>`)
+
+	res = h.Post("/new", url.Values{"difficulty": {"insane"}})
+	AssertContains(t, res, `<option value="" selected>any difficulty</option>`)
+}
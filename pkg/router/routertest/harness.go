@@ -0,0 +1,454 @@
+// Package routertest boots the complete lettr router against fixture data
+// and provides helpers to play whole games over HTTP in end-to-end tests.
+package routertest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/clock"
+	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/random"
+	"github.com/pandorasNox/lettr/pkg/router"
+	"github.com/pandorasNox/lettr/pkg/router/routes"
+	"github.com/pandorasNox/lettr/pkg/server"
+	"github.com/pandorasNox/lettr/pkg/session"
+)
+
+// Fixture describes the word lists the harness loads into its WordDatabase.
+type Fixture struct {
+	Files     fstest.MapFS
+	FilePaths map[language.Language]map[puzzle.WordCollection][]string
+	Seed      int64
+}
+
+// DefaultFixture contains a single common word per language, so the solution
+// of every game is known upfront ("cried" for english, "hallo" for german).
+// English players may pick the themed collection "animals" ("otter").
+func DefaultFixture() Fixture {
+	return Fixture{
+		Files: fstest.MapFS{
+			"en-all.txt": {Data: []byte(`# metadata
+gamer
+games
+tried
+fried
+pried
+dried
+otter
+`)},
+			"en-common.txt": {Data: []byte(`# metadata
+cried
+`)},
+			"en-animals.txt": {Data: []byte(`// animals | tags: animals, kids-friendly
+otter
+`)},
+			"de-all.txt": {Data: []byte(`# metadata
+hello
+`)},
+			"de-common.txt": {Data: []byte(`# metadata
+hallo
+`)},
+		},
+		FilePaths: map[language.Language]map[puzzle.WordCollection][]string{
+			language.LANG_EN: {
+				puzzle.WC_ALL:    {"en-all.txt"},
+				puzzle.WC_COMMON: {"en-common.txt"},
+				"animals":        {"en-animals.txt"},
+			},
+			language.LANG_DE: {
+				puzzle.WC_ALL:    {"de-all.txt"},
+				puzzle.WC_COMMON: {"de-common.txt"},
+			},
+		},
+		Seed: 1,
+	}
+}
+
+// BROWSER_USER_AGENT is sent by players unless Header sets another one.
+const BROWSER_USER_AGENT = "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0"
+
+// Harness is a running lettr server plus a cookie aware client acting as a
+// single player. Header is sent along with every request. Clock is the
+// clock of the route handlers, sessions keep using the real one.
+type Harness struct {
+	t        testing.TB
+	Server   *httptest.Server
+	Client   *http.Client
+	App      *routes.App
+	Sessions *session.Sessions
+	Header   http.Header
+	Clock    *clock.Fake
+	Mailbox  *Mailbox
+
+	guesses []string
+}
+
+// Mailbox records the mails the app sends instead of delivering them.
+type Mailbox struct {
+	mutex sync.Mutex
+	mails []Mail
+}
+
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+func (m *Mailbox) Send(to string, subject string, body string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.mails = append(m.mails, Mail{To: to, Subject: subject, Body: body})
+	return nil
+}
+
+// Last returns the most recent mail, false if none was sent.
+func (m *Mailbox) Last() (Mail, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if len(m.mails) == 0 {
+		return Mail{}, false
+	}
+
+	return m.mails[len(m.mails)-1], true
+}
+
+// Response is a fully read HTTP response.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       string
+}
+
+// New starts a TLS test server (the session cookie is marked secure) serving
+// the complete router. The server is closed via t.Cleanup.
+func New(t testing.TB, f Fixture) *Harness {
+	t.Helper()
+
+	wordDb := puzzle.WordDatabase{}
+	err := wordDb.Init(f.Files, f.FilePaths)
+	if err != nil {
+		t.Fatalf("routertest: init wordDatabase failed: %s", err)
+	}
+
+	rnd := random.New(f.Seed)
+	sessions := session.NewSessions(clock.Real{}, rnd)
+	fakeClock := clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	app := routes.NewApp(&sessions, wordDb, routes.Config{Revision: "0000000", Clock: fakeClock, Rand: rnd}, &server.Server{})
+	mailbox := &Mailbox{}
+	app.Mailer = mailbox
+
+	ts := httptest.NewTLSServer(router.New(fstest.MapFS{}, app))
+	t.Cleanup(ts.Close)
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatalf("routertest: creating cookie jar failed: %s", err)
+	}
+	client := ts.Client()
+	client.Jar = jar
+
+	return &Harness{t: t, Server: ts, Client: client, App: app, Sessions: &sessions, Header: http.Header{}, Clock: fakeClock, Mailbox: mailbox}
+}
+
+// NewPlayer returns a harness for another player of the same server, it has
+// its own cookie jar and thereby its own session.
+func (h *Harness) NewPlayer() *Harness {
+	h.t.Helper()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		h.t.Fatalf("routertest: creating cookie jar failed: %s", err)
+	}
+	// Server.Client always returns the same client, so copy it to keep jars apart
+	client := *h.Server.Client()
+	client.Jar = jar
+
+	return &Harness{t: h.t, Server: h.Server, Client: &client, App: h.App, Sessions: h.Sessions, Header: http.Header{}, Clock: h.Clock, Mailbox: h.Mailbox}
+}
+
+// Get performs a GET request against the harness server.
+func (h *Harness) Get(path string) Response {
+	h.t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, h.Server.URL+path, nil)
+	if err != nil {
+		h.t.Fatalf("routertest: creating request failed: %s", err)
+	}
+
+	return h.do(req)
+}
+
+// Post performs a form encoded POST request against the harness server.
+func (h *Harness) Post(path string, form url.Values) Response {
+	h.t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, h.Server.URL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		h.t.Fatalf("routertest: creating request failed: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("HX-Request", "true")
+
+	return h.do(req)
+}
+
+func (h *Harness) do(req *http.Request) Response {
+	h.t.Helper()
+
+	for k, vs := range h.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	// bots get no session, so players come with a browser
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", BROWSER_USER_AGENT)
+	}
+
+	res, err := h.Client.Do(req)
+	if err != nil {
+		h.t.Fatalf("routertest: %s %s failed: %s", req.Method, req.URL.Path, err)
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		h.t.Fatalf("routertest: reading body failed: %s", err)
+	}
+
+	return Response{StatusCode: res.StatusCode, Header: res.Header, Body: string(b)}
+}
+
+// Start loads the index page and, like the first move of a player would,
+// starts the session unless the player has one already.
+func (h *Harness) Start() Response {
+	h.t.Helper()
+	h.guesses = nil
+
+	res := h.Get("/")
+	if _, ok := h.sessionCookie(); !ok {
+		// opening the help is the lightest move, it leaves the game as is
+		h.Post("/help", url.Values{})
+	}
+
+	return res
+}
+
+// NewGame starts a new game in the current language.
+func (h *Harness) NewGame() Response {
+	h.t.Helper()
+	h.guesses = nil
+
+	return h.Post("/new", url.Values{})
+}
+
+// NewThemedGame starts a new game on a solution of the themed collection c,
+// an empty c switches back to the default solutions.
+func (h *Harness) NewThemedGame(c puzzle.WordCollection) Response {
+	h.t.Helper()
+	h.guesses = nil
+
+	return h.Post("/new", url.Values{"collection": {string(c)}})
+}
+
+// NewSpeedGame starts a new game against the clock.
+func (h *Harness) NewSpeedGame() Response {
+	h.t.Helper()
+	h.guesses = nil
+
+	return h.Post("/new", url.Values{"speed": {"on"}})
+}
+
+// NewSurvivalGame starts a new survival run.
+func (h *Harness) NewSurvivalGame() Response {
+	h.t.Helper()
+	h.guesses = nil
+
+	return h.Post("/new", url.Values{"survival": {"on"}})
+}
+
+// NewMultiBoardGame starts a new game on the given number of boards.
+func (h *Harness) NewMultiBoardGame(boards int) Response {
+	h.t.Helper()
+	h.guesses = nil
+
+	return h.Post("/new", url.Values{"boards": {fmt.Sprint(boards)}})
+}
+
+// SwitchLanguage starts a new game in the given language.
+func (h *Harness) SwitchLanguage(l language.Language) Response {
+	h.t.Helper()
+	h.guesses = nil
+
+	return h.Post("/new", url.Values{"lang": {string(l)}})
+}
+
+// Guess submits word as the next row together with the row index the
+// client believes is current, the same way the lettr form does it.
+func (h *Harness) Guess(word string) Response {
+	h.t.Helper()
+
+	res := h.GuessRow(len(h.guesses), word)
+	if res.StatusCode == http.StatusOK {
+		h.guesses = append(h.guesses, word)
+	}
+
+	return res
+}
+
+// GuessRow submits word for the given row, e.g. to send a stale row.
+func (h *Harness) GuessRow(row int, word string) Response {
+	h.t.Helper()
+
+	form := url.Values{"row": {fmt.Sprint(row)}}
+	for _, l := range word {
+		form.Add("guess", string(l))
+	}
+
+	return h.Post("/lettr", form)
+}
+
+// GuessBoards submits word as the next guess of a multi board game.
+func (h *Harness) GuessBoards(word string) Response {
+	h.t.Helper()
+
+	form := url.Values{}
+	for _, l := range word {
+		form.Add("guess", string(l))
+	}
+
+	return h.Post("/lettr/boards", form)
+}
+
+// ForgetGuesses drops the rows Guess sends along, for when the server
+// started the next game on its own (e.g. in a survival run).
+func (h *Harness) ForgetGuesses() {
+	h.guesses = nil
+}
+
+// Hint requests a letter hint.
+func (h *Harness) Hint() Response {
+	h.t.Helper()
+
+	return h.Get("/letter-hint")
+}
+
+// SessionID returns the id of the session the client's cookie refers to.
+func (h *Harness) SessionID() string {
+	h.t.Helper()
+
+	sess, err := h.Sessions.GetByCookie(h.SessionToken())
+	if err != nil {
+		h.t.Fatalf("routertest: %s", err)
+	}
+
+	return sess.ID()
+}
+
+// SessionToken returns the value of the client's session cookie.
+func (h *Harness) SessionToken() string {
+	h.t.Helper()
+
+	value, ok := h.sessionCookie()
+	if !ok {
+		h.t.Fatalf("routertest: no session cookie set")
+	}
+
+	return value
+}
+
+func (h *Harness) sessionCookie() (string, bool) {
+	h.t.Helper()
+
+	u, err := url.Parse(h.Server.URL)
+	if err != nil {
+		h.t.Fatalf("routertest: parsing server url failed: %s", err)
+	}
+
+	for _, c := range h.Client.Jar.Cookies(u) {
+		if c.Name == session.SESSION_COOKIE_NAME {
+			return c.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// SolutionWord returns the solution of the player's active game.
+func (h *Harness) SolutionWord() string {
+	h.t.Helper()
+
+	sess, err := h.Sessions.GetByCookie(h.SessionToken())
+	if err != nil {
+		h.t.Fatalf("routertest: %s", err)
+	}
+
+	return sess.GameState().ActiveSolutionWord().String()
+}
+
+// SolutionWords returns the solutions of all boards of the active game.
+func (h *Harness) SolutionWords() []string {
+	h.t.Helper()
+
+	sess, err := h.Sessions.GetByCookie(h.SessionToken())
+	if err != nil {
+		h.t.Fatalf("routertest: %s", err)
+	}
+
+	ws := []string{}
+	for _, w := range sess.GameState().SolutionWords() {
+		ws = append(ws, w.String())
+	}
+
+	return ws
+}
+
+// AssertStatus fails the test if the response has an unexpected status code.
+func AssertStatus(t testing.TB, res Response, want int) {
+	t.Helper()
+
+	if res.StatusCode != want {
+		t.Errorf("status = %d, want %d\nbody:\n%s", res.StatusCode, want, res.Body)
+	}
+}
+
+// AssertContains fails the test if the response body misses the fragment.
+func AssertContains(t testing.TB, res Response, fragment string) {
+	t.Helper()
+
+	if !strings.Contains(res.Body, fragment) {
+		t.Errorf("body does not contain %q\nbody:\n%s", fragment, res.Body)
+	}
+}
+
+// AssertNotContains fails the test if the response body contains the fragment.
+func AssertNotContains(t testing.TB, res Response, fragment string) {
+	t.Helper()
+
+	if strings.Contains(res.Body, fragment) {
+		t.Errorf("body unexpectedly contains %q\nbody:\n%s", fragment, res.Body)
+	}
+}
+
+// AssertHeader fails the test if the response header key does not equal want.
+func AssertHeader(t testing.TB, res Response, key string, want string) {
+	t.Helper()
+
+	if got := res.Header.Get(key); got != want {
+		t.Errorf("header %s = %q, want %q", key, got, want)
+	}
+}
@@ -0,0 +1,108 @@
+package routertest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"testing/fstest"
+)
+
+type historyExport struct {
+	Solutions []string `json:"solutions"`
+	Boards    [][]struct {
+		Word    string   `json:"word"`
+		Matches []string `json:"matches"`
+	} `json:"boards"`
+	Hints   []string `json:"hints"`
+	Outcome string   `json:"outcome"`
+}
+
+func TestHistoryFlow(t *testing.T) {
+	f := DefaultFixture()
+	f.Files["en-common.txt"] = &fstest.MapFile{Data: []byte("# metadata\ncried\ntried\nfried\npried\ndried\n")}
+
+	h := New(t, f)
+	h.Start()
+
+	AssertContains(t, h.Get("/history"), "No finished games yet.")
+
+	won := h.SolutionWord()
+	AssertStatus(t, h.Hint(), http.StatusOK)
+	h.Guess("gamer")
+	AssertContains(t, h.Guess(won), "SOLVED")
+
+	h.NewGame()
+	lost := h.SolutionWord()
+	for _, g := range []string{"gamer", "games", "gamer", "games", "gamer", "games"} {
+		h.Guess(g)
+	}
+
+	res := h.Get("/history")
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, `hx-get="/history/0"`)
+	AssertContains(t, res, `hx-get="/history/1"`)
+	AssertContains(t, res, won+"</span>:")
+	AssertContains(t, res, lost+"</span>:")
+	AssertContains(t, res, "solved in 2")
+	AssertContains(t, res, "not solved")
+
+	res = h.Get("/history/0")
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "Row 0 of 2")
+	AssertNotContains(t, res, ">g</div>")
+
+	res = h.Get("/history/0?row=1")
+	AssertContains(t, res, "Row 1 of 2")
+	AssertContains(t, res, ">g</div>")
+	AssertContains(t, res, `hx-get="/history/0?row=2"`)
+	AssertNotContains(t, res, "solved in 2</p>")
+
+	res = h.Get("/history/0?row=2")
+	AssertContains(t, res, "Row 2 of 2")
+	AssertContains(t, res, "solved in 2</p>")
+
+	AssertStatus(t, h.Get("/history/2"), http.StatusUnprocessableEntity)
+
+	res = h.Get("/history/export")
+	AssertStatus(t, res, http.StatusOK)
+	AssertHeader(t, res, "Content-Disposition", `attachment; filename="lettr-history.json"`)
+
+	export := []historyExport{}
+	err := json.Unmarshal([]byte(res.Body), &export)
+	if err != nil {
+		t.Fatalf("decoding history export failed: %s\nbody:\n%s", err, res.Body)
+	}
+	if len(export) != 2 {
+		t.Fatalf("export = %+v, want two games", export)
+	}
+	if e := export[0]; e.Outcome != "won" || e.Solutions[0] != won || len(e.Hints) != 1 || len(e.Boards[0]) != 2 || e.Boards[0][1].Word != won {
+		t.Errorf("first game = %+v, want %s won in 2 rows with one hint", e, won)
+	}
+	if e := export[0].Boards[0][1]; len(e.Matches) != 5 || e.Matches[0] != "exact" {
+		t.Errorf("solving row = %+v, want exact matches", e)
+	}
+	if e := export[1]; e.Outcome != "lost" || e.Solutions[0] != lost || len(e.Boards[0]) != 6 {
+		t.Errorf("second game = %+v, want %s lost in 6 rows", e, lost)
+	}
+}
+
+func TestHistoryFlow_abandoned(t *testing.T) {
+	f := DefaultFixture()
+	f.Files["en-common.txt"] = &fstest.MapFile{Data: []byte("# metadata\ncried\ntried\nfried\npried\ndried\n")}
+
+	h := New(t, f)
+	h.Start()
+
+	// games without a guess are replaced without a trace
+	h.NewGame()
+	AssertContains(t, h.Get("/history"), "No finished games yet.")
+
+	abandoned := h.SolutionWord()
+	h.Guess("gamer")
+	h.NewGame()
+
+	res := h.Get("/history")
+	AssertContains(t, res, abandoned+"</span>:")
+	AssertContains(t, res, "given up")
+	AssertNotContains(t, res, `hx-get="/history/1"`)
+}
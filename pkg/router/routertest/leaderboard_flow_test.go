@@ -0,0 +1,93 @@
+package routertest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+type leaderboardEntry struct {
+	Nickname string `json:"nickname"`
+	Score    int    `json:"score"`
+}
+
+func getLeaderboardAPI(t *testing.T, h *Harness) map[string][]leaderboardEntry {
+	t.Helper()
+
+	res := h.Get("/api/leaderboard")
+	AssertStatus(t, res, http.StatusOK)
+	AssertHeader(t, res, "Content-Type", "application/json")
+
+	if strings.Contains(res.Body, h.SessionID()) {
+		t.Errorf("leaderboard api exposes the session id\nbody:\n%s", res.Body)
+	}
+
+	boards := map[string][]leaderboardEntry{}
+	err := json.Unmarshal([]byte(res.Body), &boards)
+	if err != nil {
+		t.Fatalf("decoding leaderboard api failed: %s\nbody:\n%s", err, res.Body)
+	}
+
+	return boards
+}
+
+func TestLeaderboardFlow(t *testing.T) {
+	f := DefaultFixture()
+	f.Files["en-common.txt"] = &fstest.MapFile{Data: []byte("# metadata\ncried\ntried\nfried\npried\ndried\n")}
+
+	h := New(t, f)
+	h.Start()
+
+	AssertContains(t, h.Guess(h.SolutionWord()), "SOLVED")
+
+	res := h.Get("/leaderboard")
+	AssertContains(t, res, "anonymous: 1 wins in a row (you)")
+	AssertContains(t, res, "anonymous: 1 solved (you)")
+
+	for _, invalid := range []string{"ab", "<b>bold</b>", "semi;colon", strings.Repeat("x", 21)} {
+		res = h.Post("/nickname", url.Values{"nickname": {invalid}})
+		AssertStatus(t, res, http.StatusUnprocessableEntity)
+		AssertContains(t, res, "nickname must have 3 to 20")
+	}
+
+	res = h.Post("/nickname", url.Values{"nickname": {"  Wörd Smith_1 "}})
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, `value="Wörd Smith_1"`)
+	AssertContains(t, res, "Wörd Smith_1: 1 wins in a row (you)")
+
+	h.NewGame()
+	AssertContains(t, h.Guess(h.SolutionWord()), "SOLVED")
+
+	boards := getLeaderboardAPI(t, h)
+	for _, kind := range []string{"streak", "daily"} {
+		if got := boards[kind]; len(got) != 1 || got[0].Nickname != "Wörd Smith_1" || got[0].Score != 2 {
+			t.Errorf("%s board = %+v, want one entry of Wörd Smith_1 with 2", kind, got)
+		}
+	}
+
+	// a loss ends the streak, the best one stays on the board
+	h.NewGame()
+	for _, g := range []string{"gamer", "games", "gamer", "games", "gamer", "games"} {
+		h.Guess(g)
+	}
+	if got := getLeaderboardAPI(t, h)["streak"]; len(got) != 1 || got[0].Score != 2 {
+		t.Errorf("streak board = %+v, want the best streak of 2", got)
+	}
+
+	other := h.NewPlayer()
+	other.Start()
+	res = other.Get("/leaderboard")
+	AssertContains(t, res, "Wörd Smith_1: 2 wins in a row")
+	AssertNotContains(t, res, "(you)")
+
+	// the daily board starts empty every day
+	h.Clock.Advance(24 * time.Hour)
+	boards = getLeaderboardAPI(t, other)
+	if len(boards["daily"]) != 0 || len(boards["streak"]) != 1 {
+		t.Errorf("boards on the next day = %+v, want no daily but the streak entry", boards)
+	}
+}
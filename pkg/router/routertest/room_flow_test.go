@@ -0,0 +1,203 @@
+package routertest
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var roomCodeRegexp = regexp.MustCompile(`sse-connect="/rooms/([A-Z0-9]+)/events"`)
+
+func TestRoomFlow_race(t *testing.T) {
+	host := New(t, DefaultFixture())
+	host.Start()
+	guest := host.NewPlayer()
+	guest.Start()
+
+	res := host.Post("/rooms", url.Values{"time-limit": {"3"}})
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "Start race")
+
+	m := roomCodeRegexp.FindStringSubmatch(res.Body)
+	if m == nil {
+		t.Fatalf("no room code in response\nbody:\n%s", res.Body)
+	}
+	code := m[1]
+
+	res = guest.Post("/rooms/join", url.Values{"code": {strings.ToLower(code)}})
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "Waiting for the host")
+	AssertNotContains(t, res, "Start race")
+
+	events := subscribe(t, guest, code)
+	events.expect("progress", "Player 2 (you)")
+
+	res = guest.Post("/rooms/"+code+"/start", url.Values{})
+	AssertStatus(t, res, http.StatusUnprocessableEntity)
+	AssertContains(t, res, "Only the host can start the race")
+
+	res = host.Post("/rooms/"+code+"/start", url.Values{})
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, `name="guess"`)
+	events.expect("board", `name="guess"`)
+
+	res = host.Post("/rooms/"+code+"/guess", url.Values{"guess": {"t", "r", "i", "e", "d"}})
+	AssertStatus(t, res, http.StatusOK)
+	progress := events.expect("progress", "bg-green-400")
+	if strings.Contains(progress, ">t<") {
+		t.Errorf("progress event reveals the letters of an opponent:\n%s", progress)
+	}
+
+	res = host.Post("/rooms/"+code+"/guess", url.Values{"guess": {"x", "x", "x", "x", "x"}})
+	AssertStatus(t, res, http.StatusUnprocessableEntity)
+	AssertContains(t, res, "word not in word list")
+
+	res = host.Post("/rooms/"+code+"/guess", url.Values{"guess": {"C", "R", "I", "E", "D"}})
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "Done, waiting for the others")
+
+	res = guest.Post("/rooms/"+code+"/guess", url.Values{"guess": {"c", "r", "i", "e", "d"}})
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "Race is over")
+	events.expect("progress", "The word was: cried")
+
+	res = host.Get("/rooms/" + code)
+	AssertContains(t, res, "Results")
+	AssertContains(t, res, "Player 1 (you)")
+}
+
+func TestRoomFlow_errors(t *testing.T) {
+	h := New(t, DefaultFixture())
+	h.Start()
+
+	res := h.Post("/rooms", url.Values{"time-limit": {"2"}})
+	AssertStatus(t, res, http.StatusUnprocessableEntity)
+	AssertContains(t, res, "Invalid time limit")
+
+	res = h.Post("/rooms/join", url.Values{"code": {"NOPE00"}})
+	AssertStatus(t, res, http.StatusUnprocessableEntity)
+	AssertContains(t, res, "Room not found")
+
+	res = h.Get("/rooms/NOPE00/events")
+	AssertStatus(t, res, http.StatusNotFound)
+}
+
+func TestRoomFlow_visitors(t *testing.T) {
+	host := New(t, DefaultFixture())
+	host.Start()
+
+	res := host.Post("/rooms", url.Values{"time-limit": {"3"}})
+	m := roomCodeRegexp.FindStringSubmatch(res.Body)
+	if m == nil {
+		t.Fatalf("no room code in response\nbody:\n%s", res.Body)
+	}
+	code := m[1]
+	stored := host.Sessions.String()
+
+	// room links shared in chats are opened by link previews first
+	bot := host.NewPlayer()
+	bot.Header.Set("User-Agent", "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)")
+	res = bot.Get("/rooms/" + code)
+	AssertStatus(t, res, http.StatusOK)
+	if res.Header.Get("Set-Cookie") != "" || host.Sessions.String() != stored {
+		t.Errorf("bots must not get a session, got:\n%s", host.Sessions.String())
+	}
+
+	visitor := host.NewPlayer()
+	res = visitor.Get("/rooms/" + code)
+	AssertStatus(t, res, http.StatusOK)
+	subscribe(t, visitor, code).expect("progress", "Player 1")
+	if _, ok := visitor.sessionCookie(); ok || host.Sessions.String() != stored {
+		t.Errorf("watching a room must not start a session, got:\n%s", host.Sessions.String())
+	}
+
+	res = visitor.Post("/rooms/join", url.Values{"code": {code}})
+	AssertStatus(t, res, http.StatusOK)
+	if _, ok := visitor.sessionCookie(); !ok {
+		t.Errorf("joining a room must start a session")
+	}
+}
+
+type eventStream struct {
+	t       *testing.T
+	lines   chan string
+	timeout time.Duration
+}
+
+// subscribe opens the server-sent event stream of the room for player h.
+func subscribe(t *testing.T, h *Harness, code string) *eventStream {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.Server.URL+"/rooms/"+code+"/events", nil)
+	if err != nil {
+		t.Fatalf("creating request failed: %s", err)
+	}
+	req.Header.Set("User-Agent", BROWSER_USER_AGENT)
+
+	res, err := h.Client.Do(req)
+	if err != nil {
+		t.Fatalf("GET events failed: %s", err)
+	}
+	t.Cleanup(func() { res.Body.Close() })
+
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return &eventStream{t: t, lines: lines, timeout: 5 * time.Second}
+}
+
+// expect skips events until one named event contains fragment and returns
+// its data.
+func (es *eventStream) expect(event string, fragment string) string {
+	es.t.Helper()
+
+	deadline := time.After(es.timeout)
+	name, data := "", strings.Builder{}
+	for {
+		select {
+		case <-deadline:
+			es.t.Fatalf("no %q event containing %q received", event, fragment)
+			return ""
+		case line, ok := <-es.lines:
+			if !ok {
+				es.t.Fatalf("event stream closed while waiting for %q", event)
+				return ""
+			}
+
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				data.WriteString(strings.TrimPrefix(line, "data: ") + "\n")
+			case line == "":
+				if name == event && strings.Contains(data.String(), fragment) {
+					return data.String()
+				}
+				name = ""
+				data.Reset()
+			}
+		}
+	}
+}
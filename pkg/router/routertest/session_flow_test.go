@@ -0,0 +1,104 @@
+package routertest
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/language"
+)
+
+func TestSessionFlow_lazy(t *testing.T) {
+	h := New(t, DefaultFixture())
+
+	res := h.Get("/")
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, `id="lettr-container"`)
+	if _, ok := h.sessionCookie(); ok || h.Sessions.String() != "" {
+		t.Fatalf("visiting the index must not start a session, got:\n%s", h.Sessions.String())
+	}
+
+	res = h.Get("/lettr")
+	AssertStatus(t, res, http.StatusOK)
+	if _, ok := h.sessionCookie(); ok {
+		t.Fatalf("loading the game must not start a session")
+	}
+
+	// the first guess starts the session and is kept
+	res = h.Guess("tried")
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, `value="t"`)
+	if h.SolutionWord() != "cried" {
+		t.Errorf("SolutionWord() = %q, want %q", h.SolutionWord(), "cried")
+	}
+
+	res = h.Get("/")
+	AssertContains(t, res, `value="t"`)
+}
+
+func TestSessionFlow_bot(t *testing.T) {
+	h := New(t, DefaultFixture())
+	h.Header.Set("User-Agent", "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)")
+
+	res := h.Get("/")
+	AssertStatus(t, res, http.StatusOK)
+	AssertHeader(t, res, "Cache-Control", "public, max-age=3600")
+	AssertHeader(t, res, "Vary", "Accept-Language, Cookie")
+	if res.Header.Get("Set-Cookie") != "" || h.Sessions.String() != "" {
+		t.Errorf("bots must not get a session, got:\n%s", h.Sessions.String())
+	}
+
+	// challenge links shared in chats are opened by link previews first
+	h.Get("/c/some-token")
+	if _, ok := h.sessionCookie(); ok {
+		t.Errorf("opening a challenge link as bot must not start a session")
+	}
+}
+
+func TestSessionFlow_creationLimit(t *testing.T) {
+	h := New(t, DefaultFixture())
+	h.Sessions.SetCreationLimit(2, time.Hour)
+
+	first, second, third := h.NewPlayer(), h.NewPlayer(), h.NewPlayer()
+	AssertStatus(t, first.Guess("tried"), http.StatusOK)
+	AssertStatus(t, second.Guess("tried"), http.StatusOK)
+
+	res := third.Guess("tried")
+	AssertStatus(t, res, http.StatusUnprocessableEntity)
+	AssertContains(t, res, "Too many new games from your network")
+	if _, ok := third.sessionCookie(); ok {
+		t.Errorf("a capped address must not get a session")
+	}
+
+	// players with a session are not affected
+	res = first.Guess("cried")
+	AssertStatus(t, res, http.StatusOK)
+	AssertContains(t, res, "SOLVED")
+}
+
+func TestSessionFlow_tokenRotation(t *testing.T) {
+	h := New(t, DefaultFixture())
+	h.Start()
+
+	id, token := h.SessionID(), h.SessionToken()
+
+	login(t, h, "player@example.com")
+	if h.SessionToken() == token {
+		t.Errorf("linking an account must rotate the session token")
+	}
+	if h.SessionID() != id {
+		t.Errorf("SessionID() = %q after login, want %q", h.SessionID(), id)
+	}
+	if _, err := h.Sessions.GetByCookie(token); err == nil {
+		t.Errorf("the rotated token must not be accepted anymore")
+	}
+
+	token = h.SessionToken()
+	h.SwitchLanguage(language.LANG_DE)
+	if h.SessionToken() == token {
+		t.Errorf("switching the language must rotate the session token")
+	}
+	if h.SessionID() != id {
+		t.Errorf("SessionID() = %q after switching the language, want %q", h.SessionID(), id)
+	}
+}
@@ -0,0 +1,181 @@
+package routes
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/pandorasNox/lettr/pkg/account"
+	"github.com/pandorasNox/lettr/pkg/i18n"
+	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/notification"
+	"github.com/pandorasNox/lettr/pkg/router/routes/models"
+	"github.com/pandorasNox/lettr/pkg/router/routes/templates"
+	"github.com/pandorasNox/lettr/pkg/session"
+)
+
+func (a *App) GetAccount() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, _ := session.PeekSession(w, r, a.Sessions)
+
+		a.renderAccount(w, s.Language(), s.AccountID())
+	}
+}
+
+// PostAccountLogin mails a login link. The answer is the same for known
+// and unknown addresses.
+func (a *App) PostAccountLogin() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s := session.HandleSession(w, r, a.Sessions, a.WordDb)
+		a.Sessions.UpdateOrSet(s)
+		notifier := a.NewNotifier()
+
+		email, err := account.NormalizeEmail(r.FormValue("email"))
+		if err != nil {
+			w.Header().Add("HX-Reswap", "none")
+			writeErrorMessage(w, notifier, s.Language(), "msg.validation.email")
+			return
+		}
+
+		token, err := a.Accounts.RequestLogin(email)
+		if err != nil {
+			log.Printf("error requesting login: %s", err)
+			writeErrorMessage(w, notifier, s.Language(), "msg.account.failed")
+			return
+		}
+
+		link := baseURL(r) + "/account/login/" + token
+		err = a.Mailer.Send(email, i18n.T(s.Language(), "account.mail.subject"), i18n.T(s.Language(), "account.mail.body", link))
+		if err != nil {
+			log.Printf("error sending login mail: %s", err)
+			writeErrorMessage(w, notifier, s.Language(), "msg.account.failed")
+			return
+		}
+
+		notifier.AddSuccess("msg.account.linkSent")
+		a.renderAccountWithMessages(w, notifier, s.Language(), s.AccountID())
+	}
+}
+
+// GetAccountLoginToken shows the confirmation page of a login link. The
+// link is usually opened from a mail client, a cross site navigation which
+// does not carry the strict session cookie, so no session is touched here.
+func (a *App) GetAccountLoginToken() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		td := models.TemplateDataAccountConfirm{
+			Language: i18n.Negotiate(r.Header.Get("Accept-Language")),
+			Token:    r.PathValue("token"),
+		}
+
+		err := templates.Routes.ExecuteTemplate(w, "account-confirm", td)
+		if err != nil {
+			log.Printf("error t.ExecuteTemplate 'account-confirm': %s", err)
+		}
+	}
+}
+
+// PostAccountLoginToken redeems a login link. The session of the browser is
+// merged into the account and claimed by it.
+func (a *App) PostAccountLoginToken() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s := session.HandleSession(w, r, a.Sessions, a.WordDb)
+
+		acc, err := a.Accounts.Redeem(r.PathValue("token"))
+		if err != nil {
+			a.Sessions.UpdateOrSet(s)
+
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			td := models.TemplateDataAccountConfirm{Language: s.Language(), Invalid: true}
+			err = templates.Routes.ExecuteTemplate(w, "account-confirm", td)
+			if err != nil {
+				log.Printf("error t.ExecuteTemplate 'account-confirm': %s", err)
+			}
+			return
+		}
+
+		s.Claim(acc.ID, acc.Progress.Merge(s.Progress()))
+		// the session gains access to the account, a token leaked before
+		// must not
+		s.RotateToken(a.Clock.Now())
+		a.Sessions.UpdateOrSet(s)
+		a.Leaderboards.Rename(s.ID(), s.Nickname())
+
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+	}
+}
+
+// PostAccountLogout makes the session anonymous, it keeps the progress.
+func (a *App) PostAccountLogout() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s := session.HandleSession(w, r, a.Sessions, a.WordDb)
+		s.Unclaim()
+		s.RotateToken(a.Clock.Now())
+		a.Sessions.UpdateOrSet(s)
+
+		a.renderAccount(w, s.Language(), s.AccountID())
+	}
+}
+
+// PostAccountDelete removes the account. Sessions claimed by it on other
+// devices become anonymous on their next request.
+func (a *App) PostAccountDelete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s := session.HandleSession(w, r, a.Sessions, a.WordDb)
+		notifier := a.NewNotifier()
+
+		if s.AccountID() == "" {
+			a.Sessions.UpdateOrSet(s)
+			writeErrorMessage(w, notifier, s.Language(), "msg.account.notLoggedIn")
+			return
+		}
+
+		a.Accounts.Delete(s.AccountID())
+		s.Unclaim()
+		a.Sessions.UpdateOrSet(s)
+
+		notifier.AddSuccess("msg.account.deleted")
+		a.renderAccountWithMessages(w, notifier, s.Language(), s.AccountID())
+	}
+}
+
+// GetAccountExport hands out all data of the account as JSON download.
+func (a *App) GetAccountExport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s := session.HandleSession(w, r, a.Sessions, a.WordDb)
+		a.Sessions.UpdateOrSet(s)
+
+		acc, ok := a.Accounts.Get(s.AccountID())
+		if !ok {
+			http.Error(w, i18n.T(s.Language(), "msg.account.notLoggedIn"), http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", `attachment; filename="lettr-account.json"`)
+		err := json.NewEncoder(w).Encode(models.AccountExport{}.New(acc))
+		if err != nil {
+			log.Printf("error encoding '/account/export' response: %s", err)
+		}
+	}
+}
+
+func (a *App) renderAccount(w http.ResponseWriter, l language.Language, accountID string) {
+	td := models.TemplateDataAccount{Language: l}
+	if acc, ok := a.Accounts.Get(accountID); ok {
+		td.Email = acc.Email
+	}
+
+	err := templates.Routes.ExecuteTemplate(w, "account", td)
+	if err != nil {
+		log.Printf("error t.ExecuteTemplate 'account': %s", err)
+	}
+}
+
+func (a *App) renderAccountWithMessages(w http.ResponseWriter, notifier notification.Notifier, l language.Language, accountID string) {
+	err := templates.Routes.ExecuteTemplate(w, "oob-messages", notifier.ToTemplate(l))
+	if err != nil {
+		log.Printf("error t.ExecuteTemplate 'oob-messages': %s", err)
+	}
+
+	a.renderAccount(w, l, accountID)
+}
@@ -0,0 +1,139 @@
+package routes
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/account"
+	"github.com/pandorasNox/lettr/pkg/challenge"
+	"github.com/pandorasNox/lettr/pkg/clock"
+	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/leaderboard"
+	"github.com/pandorasNox/lettr/pkg/notification"
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/random"
+	"github.com/pandorasNox/lettr/pkg/room"
+	"github.com/pandorasNox/lettr/pkg/router/routes/templates"
+	"github.com/pandorasNox/lettr/pkg/server"
+	"github.com/pandorasNox/lettr/pkg/session"
+)
+
+// Config holds the static settings the route handlers need to render pages
+// and talk to external services.
+type Config struct {
+	ImprintUrl  string
+	GithubToken string
+	Revision    string
+	FaviconPath string
+	// ChallengeSecret seals challenge tokens, if empty a random one is used
+	// and challenge links break on restart.
+	ChallengeSecret string
+	// SpeedTimeLimit is the time a player has to solve a speed game,
+	// DEFAULT_SPEED_TIME_LIMIT if zero.
+	SpeedTimeLimit time.Duration
+	// MailFile is where login mails are appended to, if empty they are
+	// written to the log. There is no real mail delivery yet.
+	MailFile string
+	// LeaderboardStore keeps the leaderboards, if nil they are kept in
+	// memory and every instance ranks its own players.
+	LeaderboardStore leaderboard.Store
+	// Accounts keeps the accounts, if nil they are kept in memory, lost on
+	// restart and every instance knows its own accounts only.
+	Accounts account.Accounts
+	// Clock is the time of the handlers, rooms, challenges, leaderboards
+	// and accounts, the real clock if nil.
+	Clock clock.Clock
+	// Rand picks words and room solutions, a time seeded Rand if nil.
+	Rand random.Rand
+}
+
+const DEFAULT_SPEED_TIME_LIMIT = 3 * time.Minute
+
+// App bundles all dependencies of the route handlers. Handlers hang off App
+// as methods, so tests can build an App with fakes instead of wiring
+// positional arguments through every constructor.
+type App struct {
+	Sessions     *session.Sessions
+	WordDb       puzzle.WordDatabase
+	Config       Config
+	Server       *server.Server
+	NewNotifier  func() notification.Notifier
+	Clock        clock.Clock
+	Rand         random.Rand
+	Rooms        *room.Hub
+	Challenges   *challenge.Store
+	Leaderboards leaderboard.Boards
+	Accounts     account.Accounts
+	Mailer       account.Mailer
+}
+
+// NewApp returns an App using the clock and Rand of cfg and the default
+// notifier.
+func NewApp(sessions *session.Sessions, wdb puzzle.WordDatabase, cfg Config, s *server.Server) *App {
+	var c clock.Clock = clock.Real{}
+	if cfg.Clock != nil {
+		c = cfg.Clock
+	}
+
+	rnd := cfg.Rand
+	if rnd == nil {
+		rnd = random.New(time.Now().UnixNano())
+	}
+
+	secret := cfg.ChallengeSecret
+	if secret == "" {
+		var err error
+		secret, err = random.SecureString(64)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	if cfg.SpeedTimeLimit <= 0 {
+		cfg.SpeedTimeLimit = DEFAULT_SPEED_TIME_LIMIT
+	}
+
+	var mailer account.Mailer = account.LogMailer{}
+	if cfg.MailFile != "" {
+		mailer = &account.FileMailer{Path: cfg.MailFile}
+	}
+
+	var leaderboards leaderboard.Store = leaderboard.NewMemoryStore()
+	if cfg.LeaderboardStore != nil {
+		leaderboards = cfg.LeaderboardStore
+	}
+
+	var accounts account.Accounts = account.NewStore(c)
+	if cfg.Accounts != nil {
+		accounts = cfg.Accounts
+	}
+	sessions.SetProgressStore(accounts)
+
+	return &App{
+		Sessions:     sessions,
+		WordDb:       wdb,
+		Config:       cfg,
+		Server:       s,
+		NewNotifier:  notification.NewNotifier,
+		Clock:        c,
+		Rand:         rnd,
+		Rooms:        room.NewHub(c, rnd),
+		Challenges:   challenge.MustNewStore(c, secret),
+		Leaderboards: leaderboard.MustNewBoards(c, leaderboards, leaderboard.DEFAULT_SIZE),
+		Accounts:     accounts,
+		Mailer:       mailer,
+	}
+}
+
+// writeErrorMessage answers with 422 and the translated message, rendered
+// as out of band swap into the messages container.
+func writeErrorMessage(w http.ResponseWriter, notifier notification.Notifier, l language.Language, key string) {
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	notifier.AddError(key)
+
+	err := templates.Routes.ExecuteTemplate(w, "oob-messages", notifier.ToTemplate(l))
+	if err != nil {
+		log.Printf("error t.ExecuteTemplate 'oob-messages': %s", err)
+	}
+}
@@ -0,0 +1,122 @@
+package routes
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/router/routes/models/shared"
+	"github.com/pandorasNox/lettr/pkg/router/routes/templates"
+	"github.com/pandorasNox/lettr/pkg/session"
+)
+
+// PostLettrBoards takes the next guess of a multi board game. Unlike the
+// single board form only the new word is sent, the server keeps the rows.
+func (a *App) PostLettrBoards() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		notifier := a.NewNotifier()
+		s, err := session.StartSession(w, r, a.Sessions, a.WordDb)
+		if err != nil {
+			writeErrorMessage(w, notifier, s.Language(), "msg.tooManySessions")
+			return
+		}
+
+		err = r.ParseForm()
+		if err != nil {
+			log.Printf("error: %s", err)
+
+			writeErrorMessage(w, notifier, s.Language(), "msg.formParseFailed")
+			return
+		}
+
+		g := s.GameState()
+		now := a.Clock.Now()
+
+		if !g.IsMultiBoard() {
+			writeErrorMessage(w, notifier, s.Language(), "msg.formParseFailed")
+			return
+		}
+
+		// only the first request after the deadline ends the game
+		if g.IsTimedOut(now) {
+			if g.TimeOut(now) == nil {
+				streak, solvedToday := s.RecordResult(now, false)
+				s.AddToHistory(g.Record(s.Language(), now))
+				err = a.Sessions.Update(s)
+				if err == nil {
+					a.recordResult(s.ID(), s.Nickname(), false, streak, solvedToday)
+					a.WordDb.RecordResult(s.Language(), g.SolutionWords(), false)
+				} else {
+					log.Printf("saving session failed: %s", err)
+				}
+			}
+		} else {
+			if err = g.Can(puzzle.MOVE_GUESS); err != nil {
+				writeErrorMessage(w, notifier, s.Language(), "msg.gameOver")
+				return
+			}
+
+			guessedWord, err := puzzle.SliceToWord(r.PostForm["guess"])
+			if err != nil {
+				writeErrorMessage(w, notifier, s.Language(), "msg.formParseFailed")
+				return
+			}
+
+			fold := puzzle.Word.ToLower
+			exists := a.WordDb.Exists(s.Language(), guessedWord)
+			if g.IsEasy() {
+				fold = puzzle.FoldWord(s.Language())
+				exists = a.WordDb.ExistsFolded(s.Language(), guessedWord)
+			}
+			if !exists {
+				writeErrorMessage(w, notifier, s.Language(), "msg.notInWordList")
+				return
+			}
+
+			err = g.GuessBoards(guessedWord, fold, now)
+			if err != nil {
+				log.Printf("error: %s", err)
+				writeErrorMessage(w, notifier, s.Language(), "msg.gameOver")
+				return
+			}
+			s.SetGameState(*g)
+
+			status := g.Status()
+			var streak, solvedToday int
+			if status.IsOver() {
+				streak, solvedToday = s.RecordResult(now, status == puzzle.STATUS_WON)
+				s.AddToHistory(g.Record(s.Language(), now))
+			}
+
+			err = a.Sessions.Update(s)
+			if errors.Is(err, session.ErrSessionConflict) {
+				// another instance stored a guess for this game first
+				s = session.HandleSession(w, r, a.Sessions, a.WordDb)
+				writeResynced(w, notifier, s.Language(), a.storedLettrForm(s.Language(), s.KeyboardLayout(), s.GameState(), s.PastWords(), s.SurvivalBest(), s.Pick(), now))
+				return
+			}
+			if err != nil {
+				log.Printf("saving session failed: %s", err)
+			}
+
+			// results shared with other players only count once the game is saved
+			if err == nil && status.IsOver() {
+				a.recordResult(s.ID(), s.Nickname(), status == puzzle.STATUS_WON, streak, solvedToday)
+				a.WordDb.RecordResult(s.Language(), g.SolutionWords(), status == puzzle.STATUS_WON)
+			}
+		}
+
+		fData := shared.TemplateDataLettr{}.New(s.Language(), s.KeyboardLayout(), g.LastEvaluatedAttempt(), g.LetterHints(), s.PastWords(), a.Config.ImprintUrl, a.Config.Revision, a.Config.FaviconPath)
+		fData.SetStatus(g.Status())
+		fData.IsEasy = g.IsEasy()
+		fData.SetMultiBoard(g.MultiBoard(), g.LetterHints())
+		fData.SetSpeed(g, now)
+		fData.SetPick(a.WordDb, s.Pick())
+
+		err = templates.Routes.ExecuteTemplate(w, "lettr-form", fData)
+		if err != nil {
+			log.Printf("error t.ExecuteTemplate '/lettr/boards' route: %s", err)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package routes
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/pandorasNox/lettr/pkg/bot"
+	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/router/routes/models"
+	"github.com/pandorasNox/lettr/pkg/router/routes/templates"
+	"github.com/pandorasNox/lettr/pkg/session"
+)
+
+func (a *App) GetChallenge() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, _ := session.PeekSession(w, r, a.Sessions)
+
+		a.renderChallenges(w, r, s.Language(), s.ID())
+	}
+}
+
+func (a *App) PostChallenge() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s := session.HandleSession(w, r, a.Sessions, a.WordDb)
+		a.Sessions.UpdateOrSet(s)
+
+		word, err := typedWord(language.Normalize(s.Language(), r.FormValue("word")))
+		if err != nil || !a.WordDb.Exists(s.Language(), word) {
+			// players without umlauts on their keyboard write them out
+			transliterated, tErr := typedWord(language.Transliterate(s.Language(), r.FormValue("word")))
+			if tErr == nil && a.WordDb.Exists(s.Language(), transliterated) {
+				word, err = transliterated, nil
+			}
+		}
+		if err != nil {
+			writeErrorMessage(w, a.NewNotifier(), s.Language(), "msg.challenge.word")
+			return
+		}
+
+		if !a.WordDb.Exists(s.Language(), word) {
+			writeErrorMessage(w, a.NewNotifier(), s.Language(), "msg.notInWordList")
+			return
+		}
+
+		_, err = a.Challenges.Create(s.ID(), s.Language(), word)
+		if err != nil {
+			log.Printf("error creating challenge: %s", err)
+			writeErrorMessage(w, a.NewNotifier(), s.Language(), "msg.challenge.failed")
+			return
+		}
+
+		a.renderChallenges(w, r, s.Language(), s.ID())
+	}
+}
+
+// GetChallengeToken starts a game on the word sealed into the token and
+// sends the player to the index page. Invalid tokens just lead to the
+// running game.
+func (a *App) GetChallengeToken() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// link previews must not start the game for the player sharing it
+		if bot.IsBot(r.UserAgent()) {
+			http.Redirect(w, r, "/", http.StatusSeeOther)
+			return
+		}
+
+		s := session.HandleSession(w, r, a.Sessions, a.WordDb)
+
+		c, err := a.Challenges.Open(r.PathValue("token"))
+		if err != nil {
+			a.Sessions.UpdateOrSet(s)
+			http.Redirect(w, r, "/", http.StatusSeeOther)
+			return
+		}
+
+		s.AbandonGame(a.Clock.Now())
+		s.AddPastWord(s.GameState().ActiveSolutionWord())
+		s.SetLanguage(c.Language)
+		s.SetGameState(puzzle.NewChallengeGame(c.ID, c.Word))
+		a.Sessions.UpdateOrSet(s)
+
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+	}
+}
+
+func (a *App) renderChallenges(w http.ResponseWriter, r *http.Request, l language.Language, sessionID string) {
+	td := models.TemplateDataChallenge{}.New(l, baseURL(r), a.Challenges.ByChallenger(sessionID))
+
+	err := templates.Routes.ExecuteTemplate(w, "challenge", td)
+	if err != nil {
+		log.Printf("error t.ExecuteTemplate 'challenge': %s", err)
+	}
+}
+
+// baseURL returns scheme and host the request was sent to, honoring a TLS
+// terminating proxy in front of the server.
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	return fmt.Sprintf("%s://%s", scheme, r.Host)
+}
+
+// typedWord turns a word typed into a text field into a Word, it fails
+// unless the normalized input has exactly five letters.
+func typedWord(normalized string) (puzzle.Word, error) {
+	letters := []string{}
+	for _, l := range normalized {
+		letters = append(letters, string(l))
+	}
+
+	return puzzle.SliceToWord(letters)
+}
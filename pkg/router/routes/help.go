@@ -4,19 +4,19 @@ import (
 	"log"
 	"net/http"
 
-	"github.com/pandorasNox/lettr/pkg/puzzle"
 	"github.com/pandorasNox/lettr/pkg/router/routes/models"
 	"github.com/pandorasNox/lettr/pkg/router/routes/templates"
 	"github.com/pandorasNox/lettr/pkg/session"
 )
 
-func Help(sessions *session.Sessions, wdb puzzle.WordDatabase) http.HandlerFunc {
+func (a *App) Help() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		s := session.HandleSession(w, r, sessions, wdb)
+		s := session.HandleSession(w, r, a.Sessions, a.WordDb)
 		g := s.GameState()
-		sessions.UpdateOrSet(s)
+		a.Sessions.UpdateOrSet(s)
 
 		td := models.TemplateDataHelpPage{
+			Language:                    s.Language(),
 			SolutionWord:                g.ActiveSolutionWord().String(),
 			PastWords:                   s.PastWords(),
 			LetterHints:                 g.LetterHints(),
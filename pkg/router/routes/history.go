@@ -0,0 +1,64 @@
+package routes
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/pandorasNox/lettr/pkg/router/routes/models"
+	"github.com/pandorasNox/lettr/pkg/router/routes/templates"
+	"github.com/pandorasNox/lettr/pkg/session"
+)
+
+func (a *App) GetHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, _ := session.PeekSession(w, r, a.Sessions)
+
+		td := models.TemplateDataHistory{}.New(s.Language(), s.History())
+
+		err := templates.Routes.ExecuteTemplate(w, "history", td)
+		if err != nil {
+			log.Printf("error t.ExecuteTemplate '/history' route: %s", err)
+		}
+	}
+}
+
+// GetHistoryReplay shows a finished game after the number of rows given by
+// the "row" query parameter, stepping through it one row at a time.
+func (a *App) GetHistoryReplay() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, _ := session.PeekSession(w, r, a.Sessions)
+
+		history := s.History()
+		i, err := strconv.Atoi(r.PathValue("index"))
+		if err != nil || i < 0 || i >= len(history) {
+			writeErrorMessage(w, a.NewNotifier(), s.Language(), "msg.history.unknownGame")
+			return
+		}
+
+		// a missing or broken row starts the replay at the empty grid
+		row, _ := strconv.Atoi(r.URL.Query().Get("row"))
+
+		td := models.TemplateDataHistoryReplay{}.New(s.Language(), models.HistoryGame{Index: i, GameRecord: history[i]}, row)
+
+		err = templates.Routes.ExecuteTemplate(w, "history-replay", td)
+		if err != nil {
+			log.Printf("error t.ExecuteTemplate '/history/{index}' route: %s", err)
+		}
+	}
+}
+
+// GetHistoryExport hands out all finished games as JSON download.
+func (a *App) GetHistoryExport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, _ := session.PeekSession(w, r, a.Sessions)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", `attachment; filename="lettr-history.json"`)
+		err := json.NewEncoder(w).Encode(models.HistoryExport{}.New(s.History()))
+		if err != nil {
+			log.Printf("error encoding '/history/export' response: %s", err)
+		}
+	}
+}
@@ -4,22 +4,33 @@ import (
 	"log"
 	"net/http"
 
-	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/bot"
 	"github.com/pandorasNox/lettr/pkg/router/routes/models"
 	"github.com/pandorasNox/lettr/pkg/router/routes/templates"
 	"github.com/pandorasNox/lettr/pkg/session"
 )
 
-func Index(sessions *session.Sessions, wdb puzzle.WordDatabase, imprintUrl string, revision string, faviconPath string) http.HandlerFunc {
+func (a *App) Index() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		sess := session.HandleSession(w, r, sessions, wdb)
+		sess := session.TransientSession(r)
+		if bot.IsBot(r.UserAgent()) {
+			// bots get the landing page without any state, so shared caches
+			// may keep it
+			w.Header().Set("Cache-Control", "public, max-age=3600")
+			w.Header().Set("Vary", "Accept-Language, Cookie")
+		} else {
+			sess, _ = session.PeekSession(w, r, a.Sessions)
+		}
 
 		p := sess.GameState().LastEvaluatedAttempt()
-		sessions.UpdateOrSet(sess)
 
-		fData := models.TemplateDataIndex{}.New(sess.Language(), p, sess.GameState().LetterHints(), sess.PastWords(), imprintUrl, revision, faviconPath)
-		fData.IsSolved = p.IsSolved()
-		fData.IsLoose = p.IsLoose()
+		fData := models.TemplateDataIndex{}.New(a.Clock.Now(), sess.Language(), sess.KeyboardLayout(), p, sess.GameState().LetterHints(), sess.PastWords(), a.Config.ImprintUrl, a.Config.Revision, a.Config.FaviconPath)
+		fData.SetStatus(sess.GameState().Status())
+		fData.IsEasy = sess.GameState().IsEasy()
+		fData.SetMultiBoard(sess.GameState().MultiBoard(), sess.GameState().LetterHints())
+		fData.SetSpeed(sess.GameState(), a.Clock.Now())
+		fData.SetSurvival(sess.GameState().Survival(), sess.SurvivalBest())
+		fData.SetPick(a.WordDb, sess.Pick())
 
 		err := templates.Routes.ExecuteTemplate(w, "index.html.tmpl", fData)
 		if err != nil {
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/router/routes/models/shared"
+	"github.com/pandorasNox/lettr/pkg/router/routes/templates"
+	"github.com/pandorasNox/lettr/pkg/session"
+)
+
+func (a *App) PostKeyboardLayout() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s := session.HandleSession(w, r, a.Sessions, a.WordDb)
+
+		layout := r.FormValue("layout")
+		if _, ok := language.LookupKeyboardLayout(layout); !ok {
+			w.WriteHeader(422)
+			return
+		}
+
+		s.SetKeyboardLayout(layout)
+		a.Sessions.UpdateOrSet(s)
+
+		g := s.GameState()
+		fData := shared.TemplateDataLettr{}.New(s.Language(), s.KeyboardLayout(), g.LastEvaluatedAttempt(), g.LetterHints(), s.PastWords(), a.Config.ImprintUrl, a.Config.Revision, a.Config.FaviconPath)
+		fData.SetMultiBoard(g.MultiBoard(), g.LetterHints())
+
+		err := templates.Routes.ExecuteTemplate(w, "keyboard", fData)
+		if err != nil {
+			log.Printf("error t.ExecuteTemplate '/keyboard-layout' route: %s", err)
+		}
+	}
+}
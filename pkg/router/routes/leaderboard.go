@@ -0,0 +1,87 @@
+package routes
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/pandorasNox/lettr/pkg/leaderboard"
+	"github.com/pandorasNox/lettr/pkg/router/routes/models"
+	"github.com/pandorasNox/lettr/pkg/router/routes/templates"
+	"github.com/pandorasNox/lettr/pkg/session"
+)
+
+func (a *App) GetLeaderboard() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, _ := session.PeekSession(w, r, a.Sessions)
+
+		td := models.TemplateDataLeaderboard{}.New(s.Language(), a.Leaderboards, s.ID(), s.Nickname())
+
+		err := templates.Routes.ExecuteTemplate(w, "leaderboard", td)
+		if err != nil {
+			log.Printf("error t.ExecuteTemplate '/leaderboard' route: %s", err)
+		}
+	}
+}
+
+// PostNickname sets the nickname shown for the player on all leaderboards,
+// an empty nickname makes the player anonymous again.
+func (a *App) PostNickname() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s := session.HandleSession(w, r, a.Sessions, a.WordDb)
+		notifier := a.NewNotifier()
+
+		err := r.ParseForm()
+		if err != nil {
+			log.Printf("error: %s", err)
+
+			writeErrorMessage(w, notifier, s.Language(), "msg.formParseFailed")
+			return
+		}
+
+		nickname, err := models.ValidateNickname(r.PostForm.Get("nickname"))
+		if err != nil {
+			w.Header().Add("HX-Reswap", "none")
+			writeErrorMessage(w, notifier, s.Language(), "msg.validation.nickname")
+			return
+		}
+
+		s.SetNickname(nickname)
+		a.Sessions.UpdateOrSet(s)
+		a.Leaderboards.Rename(s.ID(), nickname)
+
+		td := models.TemplateDataLeaderboard{}.New(s.Language(), a.Leaderboards, s.ID(), s.Nickname())
+
+		err = templates.Routes.ExecuteTemplate(w, "leaderboard", td)
+		if err != nil {
+			log.Printf("error t.ExecuteTemplate '/nickname' route: %s", err)
+		}
+	}
+}
+
+// GetLeaderboardAPI is the public read-only JSON view of all leaderboards.
+// It neither needs nor creates a session and never exposes session ids.
+func (a *App) GetLeaderboardAPI() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body := map[leaderboard.Kind][]leaderboard.Entry{
+			leaderboard.KIND_SURVIVAL: a.Leaderboards.Survival.Top(),
+			leaderboard.KIND_STREAK:   a.Leaderboards.Streak.Top(),
+			leaderboard.KIND_DAILY:    a.Leaderboards.Daily.Top(),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(body)
+		if err != nil {
+			log.Printf("error encoding '/api/leaderboard' response: %s", err)
+		}
+	}
+}
+
+// recordResult counts a finished game towards the streak and daily boards.
+// The counts come from session.RecordResult.
+func (a *App) recordResult(sessionID string, nickname string, solved bool, streak int, solvedToday int) {
+	a.Leaderboards.Streak.Add(sessionID, nickname, streak, 0)
+	if solved {
+		a.Leaderboards.Daily.Add(sessionID, nickname, solvedToday, 0)
+	}
+}
@@ -2,13 +2,11 @@ package routes
 
 import (
 	"log"
-	"math/rand"
 	"net/http"
 	"slices"
-	"time"
 
-	"github.com/pandorasNox/lettr/pkg/notification"
 	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/random"
 	"github.com/pandorasNox/lettr/pkg/router/routes/models"
 	"github.com/pandorasNox/lettr/pkg/router/routes/templates"
 	"github.com/pandorasNox/lettr/pkg/session"
@@ -22,7 +20,7 @@ func Map[T, V any](ts []T, fn func(T) V) []V {
 	return result
 }
 
-func PickRandomRune(runeList []rune, randSrc rand.Source) rune {
+func PickRandomRune(runeList []rune, rnd random.Rand) rune {
 	if len(runeList) == 0 {
 		return rune(0)
 	}
@@ -30,18 +28,26 @@ func PickRandomRune(runeList []rune, randSrc rand.Source) rune {
 		return runeList[0]
 	}
 
-	randgenerator := rand.New(randSrc)
-	randIndex := randgenerator.Intn(len(runeList))
+	randIndex := rnd.Intn(len(runeList))
 
 	return runeList[randIndex]
 }
 
-func LetterHint(sessions *session.Sessions, wdb puzzle.WordDatabase) http.HandlerFunc {
+func (a *App) LetterHint() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		notifier := notification.NewNotifier()
-		sess := session.HandleSession(w, r, sessions, wdb)
+		notifier := a.NewNotifier()
+		sess, err := session.StartSession(w, r, a.Sessions, a.WordDb)
+		if err != nil {
+			writeErrorMessage(w, notifier, sess.Language(), "msg.tooManySessions")
+			return
+		}
 		gameState := sess.GameState()
 
+		if err := gameState.Can(puzzle.MOVE_HINT); err != nil {
+			writeErrorMessage(w, notifier, sess.Language(), "msg.gameOver")
+			return
+		}
+
 		solutionWord := gameState.ActiveSolutionWord()
 		lg := gameState.LastEvaluatedAttempt().LetterGuesses()
 
@@ -61,21 +67,24 @@ func LetterHint(sessions *session.Sessions, wdb puzzle.WordDatabase) http.Handle
 			return slices.Contains(lhs, l)
 		})
 
-		randSrc := rand.NewSource(time.Now().UnixNano())
-		pick := PickRandomRune(hintOptions, randSrc)
+		pick := PickRandomRune(hintOptions, a.Rand)
 		if pick == rune(0) {
-			notifier.AddInfo("No more hints to provide")
-			err := templates.Routes.ExecuteTemplate(w, "oob-messages", notifier.ToTemplate())
+			notifier.AddInfo("msg.noMoreHints")
+			err := templates.Routes.ExecuteTemplate(w, "oob-messages", notifier.ToTemplate(sess.Language()))
 			if err != nil {
 				log.Printf("error t.ExecuteTemplate 'oob-messages': %s", err)
 			}
 			return
 		}
 
-		gameState.AddLetterHint(pick)
-		sessions.UpdateOrSet(sess)
+		err = gameState.AddLetterHint(pick)
+		if err != nil {
+			writeErrorMessage(w, notifier, sess.Language(), "msg.gameOver")
+			return
+		}
+		a.Sessions.UpdateOrSet(sess)
 
-		err := templates.Routes.ExecuteTemplate(w, "single-letter-hint", models.TemplateDataLetterHint(pick))
+		err = templates.Routes.ExecuteTemplate(w, "single-letter-hint", models.TemplateDataLetterHint(pick))
 		if err != nil {
 			log.Printf("error t.ExecuteTemplate 'single-letter-hint': %s", err)
 		}
@@ -6,7 +6,8 @@ import (
 	"log"
 	"net/http"
 	"net/url"
-	"slices"
+	"strconv"
+	"time"
 
 	"github.com/pandorasNox/lettr/pkg/language"
 	"github.com/pandorasNox/lettr/pkg/notification"
@@ -18,24 +19,28 @@ import (
 
 var ErrNotInWordList = errors.New("not in wordlist")
 
-func GetLettr(sessions *session.Sessions, wdb puzzle.WordDatabase, imprintUrl string, revision string, faviconPath string) http.HandlerFunc {
+func (a *App) GetLettr() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		s := session.HandleSession(w, r, sessions, wdb)
-		sessions.UpdateOrSet(s)
+		s, _ := session.PeekSession(w, r, a.Sessions)
 
 		p := s.GameState().LastEvaluatedAttempt()
 
 		fData := shared.TemplateDataLettr{}.New(
 			s.Language(),
+			s.KeyboardLayout(),
 			p,
 			s.GameState().LetterHints(),
 			s.PastWords(),
-			imprintUrl,
-			revision,
-			faviconPath,
+			a.Config.ImprintUrl,
+			a.Config.Revision,
+			a.Config.FaviconPath,
 		)
-		fData.IsSolved = p.IsSolved()
-		fData.IsLoose = p.IsLoose()
+		fData.SetStatus(s.GameState().Status())
+		fData.IsEasy = s.GameState().IsEasy()
+		fData.SetMultiBoard(s.GameState().MultiBoard(), s.GameState().LetterHints())
+		fData.SetSurvival(s.GameState().Survival(), s.SurvivalBest())
+		fData.SetPick(a.WordDb, s.Pick())
+		fData.SetSpeed(s.GameState(), a.Clock.Now())
 
 		err := templates.Routes.ExecuteTemplate(w, "lettr-form", fData)
 		if err != nil {
@@ -44,10 +49,14 @@ func GetLettr(sessions *session.Sessions, wdb puzzle.WordDatabase, imprintUrl st
 	}
 }
 
-func PostLettr(sessions *session.Sessions, wdb puzzle.WordDatabase, imprintUrl string, revision string, faviconPath string) http.HandlerFunc {
+func (a *App) PostLettr() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		s := session.HandleSession(w, r, sessions, wdb)
-		notifier := notification.NewNotifier()
+		notifier := a.NewNotifier()
+		s, err := session.StartSession(w, r, a.Sessions, a.WordDb)
+		if err != nil {
+			writeErrorMessage(w, notifier, s.Language(), "msg.tooManySessions")
+			return
+		}
 
 		// b, err := io.ReadAll(r.Body)
 		// if err != nil {
@@ -56,13 +65,13 @@ func PostLettr(sessions *session.Sessions, wdb puzzle.WordDatabase, imprintUrl s
 		// }
 		// log.Printf("word: %s\nbody:\n%s", s.activeWord, b)
 
-		err := r.ParseForm()
+		err = r.ParseForm()
 		if err != nil {
 			log.Printf("error: %s", err)
 
 			w.WriteHeader(422)
-			notifier.AddError("cannot parse form data")
-			err = templates.Routes.ExecuteTemplate(w, "oob-messages", notifier.ToTemplate())
+			notifier.AddError("msg.formParseFailed")
+			err = templates.Routes.ExecuteTemplate(w, "oob-messages", notifier.ToTemplate(s.Language()))
 			if err != nil {
 				log.Printf("error t.ExecuteTemplate 'oob-messages': %s", err)
 			}
@@ -71,86 +80,218 @@ func PostLettr(sessions *session.Sessions, wdb puzzle.WordDatabase, imprintUrl s
 
 		g := s.GameState()
 		p := g.LastEvaluatedAttempt()
+		now := a.Clock.Now()
 
-		if p.IsSolved() || p.IsLoose() {
-			w.WriteHeader(204)
-			return
-		}
-
-		if p.ActiveRow() != countFilledFormRows(r.PostForm)-1 {
+		// multi board games are played via PostLettrBoards
+		if g.IsMultiBoard() {
 			w.WriteHeader(422)
-			notifier.AddError("faked rows")
-			err = templates.Routes.ExecuteTemplate(w, "oob-messages", notifier.ToTemplate())
+			notifier.AddError("msg.formParseFailed")
+			err = templates.Routes.ExecuteTemplate(w, "oob-messages", notifier.ToTemplate(s.Language()))
 			if err != nil {
 				log.Printf("error t.ExecuteTemplate 'oob-messages': %s", err)
 			}
 			return
 		}
 
-		p, err = parseForm(p, r.PostForm, g.ActiveSolutionWord(), s.Language(), wdb)
-		if err == ErrNotInWordList {
-			w.WriteHeader(422)
-			notifier.AddError("word not in word list")
-			err = templates.Routes.ExecuteTemplate(w, "oob-messages", notifier.ToTemplate())
+		// the server clock decides, a guess arriving after the deadline is lost
+		if g.IsTimedOut(now) {
+			// only the first request after the deadline ends the game
+			if g.TimeOut(now) == nil {
+				streak, solvedToday := s.RecordResult(now, false)
+				s.AddToHistory(g.Record(s.Language(), now))
+				err = a.Sessions.Update(s)
+				if err == nil {
+					a.recordResult(s.ID(), s.Nickname(), false, streak, solvedToday)
+					a.WordDb.RecordResult(s.Language(), g.SolutionWords(), false)
+				} else {
+					log.Printf("saving session failed: %s", err)
+				}
+			}
+
+			fData := a.storedLettrForm(s.Language(), s.KeyboardLayout(), g, s.PastWords(), s.SurvivalBest(), s.Pick(), now)
+			err = templates.Routes.ExecuteTemplate(w, "lettr-form", fData)
 			if err != nil {
-				log.Printf("error t.ExecuteTemplate 'oob-messages': %s", err)
+				log.Printf("error t.ExecuteTemplate '/lettr' route: %s", err)
 			}
 			return
 		}
 
-		g.SetLastEvaluatedAttempt(p)
-		s.SetGameState(*g) //todo move gamestate from pointer to copy
-		sessions.UpdateOrSet(s)
+		// the row works as version of the puzzle, a client sending a stale or
+		// replayed row (e.g. a second tab, a double submit or a game that got
+		// lost on restart) gets the stored game instead of an error
+		row, err := strconv.Atoi(r.PostForm.Get("row"))
+		if err != nil {
+			writeErrorMessage(w, notifier, s.Language(), "msg.formParseFailed")
+			return
+		}
 
-		fData := shared.TemplateDataLettr{}.New(s.Language(), p, s.GameState().LetterHints(), s.PastWords(), imprintUrl, revision, faviconPath)
-		fData.IsSolved = p.IsSolved()
-		fData.IsLoose = p.IsLoose()
+		if row != int(p.ActiveRow()) {
+			a.Sessions.UpdateOrSet(s)
 
-		err = templates.Routes.ExecuteTemplate(w, "lettr-form", fData)
+			writeResynced(w, notifier, s.Language(), a.storedLettrForm(s.Language(), s.KeyboardLayout(), g, s.PastWords(), s.SurvivalBest(), s.Pick(), now))
+			return
+		}
+
+		if err = g.Can(puzzle.MOVE_GUESS); err != nil {
+			writeErrorMessage(w, notifier, s.Language(), "msg.gameOver")
+			return
+		}
+
+		p, err = parseGuess(p, row, r.PostForm, g.ActiveSolutionWord(), s.Language(), g.IsEasy(), a.WordDb)
+		if err == ErrNotInWordList {
+			writeErrorMessage(w, notifier, s.Language(), "msg.notInWordList")
+			return
+		}
 		if err != nil {
-			log.Printf("error t.ExecuteTemplate '/lettr' route: %s", err)
+			log.Printf("error: %s", err)
+			writeErrorMessage(w, notifier, s.Language(), "msg.formParseFailed")
+			return
 		}
-	}
-}
 
-func countFilledFormRows(postPuzzleForm url.Values) uint8 {
-	isfilled := func(row []string) bool {
-		emptyButWithLen := make([]string, len(row)) // we need empty slice but with right elem length
-		return !(slices.Compare(row, emptyButWithLen) == 0)
-	}
+		err = g.Guess(p.Guesses[row], now)
+		if err != nil {
+			log.Printf("error: %s", err)
+			writeErrorMessage(w, notifier, s.Language(), "msg.gameOver")
+			return
+		}
+		s.SetGameState(*g) //todo move gamestate from pointer to copy
 
-	var count uint8 = 0
-	l := len(postPuzzleForm)
-	for i := 0; i < l; i++ {
-		guessedWord, ok := postPuzzleForm[fmt.Sprintf("r%d", i)]
-		if ok && isfilled(guessedWord) {
-			count++
+		status := g.Status()
+		solutions := g.SolutionWords()
+		var streak, solvedToday int
+		if status.IsOver() {
+			streak, solvedToday = s.RecordResult(now, status == puzzle.STATUS_WON)
+			s.AddToHistory(g.Record(s.Language(), now))
 		}
-	}
 
-	return count
-}
+		// survival: every solved word starts the next one, the first loss ends the run
+		if g.IsSurvival() && status == puzzle.STATUS_WON {
+			run := g.Survival().Solved(puzzle.SurvivalPoints(p, len(g.LetterHints())))
+			easy := g.IsEasy()
 
-func parseForm(p puzzle.Puzzle, form url.Values, solutionWord puzzle.Word, l language.Language, wdb puzzle.WordDatabase) (puzzle.Puzzle, error) {
-	for ri := range p.Guesses {
-		maybeGuessedWord, ok := form[fmt.Sprintf("r%d", ri)]
-		if !ok {
-			continue
+			s.AddPastWord(g.ActiveSolutionWord())
+			s.NewGame(a.Rand, s.Language(), a.WordDb)
+			s.GameState().SetEasy(easy)
+			s.GameState().SetSurvival(run)
+			p = puzzle.Puzzle{}
+
+			notifier.AddSuccess("msg.survival.solved", run.LastPoints)
+		} else if g.IsSurvival() && status == puzzle.STATUS_LOST {
+			if s.RecordSurvivalRun(g.Survival().Score) {
+				notifier.AddSuccess("msg.survival.personalBest", g.Survival().Score)
+			}
 		}
 
-		guessedWord, err := puzzle.SliceToWord(maybeGuessedWord)
+		err = a.Sessions.Update(s)
+		if errors.Is(err, session.ErrSessionConflict) {
+			// another instance stored a guess for this row first
+			s = session.HandleSession(w, r, a.Sessions, a.WordDb)
+			writeResynced(w, notifier, s.Language(), a.storedLettrForm(s.Language(), s.KeyboardLayout(), s.GameState(), s.PastWords(), s.SurvivalBest(), s.Pick(), now))
+			return
+		}
 		if err != nil {
-			return p, fmt.Errorf("parseForm could not create guessedWord from form input: %s", err.Error())
+			log.Printf("saving session failed: %s", err)
+		}
+
+		// results shared with other players only count once the game is saved
+		if err == nil && status.IsOver() {
+			a.recordResult(s.ID(), s.Nickname(), status == puzzle.STATUS_WON, streak, solvedToday)
+			a.WordDb.RecordResult(s.Language(), solutions, status == puzzle.STATUS_WON)
+
+			if g.ChallengeID() != "" {
+				challengeErr := a.Challenges.AddResult(g.ChallengeID(), s.ID(), p)
+				if challengeErr != nil {
+					log.Printf("error adding challenge result: %s", challengeErr)
+				}
+			}
+		}
+		if err == nil && g.IsSurvival() && status == puzzle.STATUS_LOST {
+			run := g.Survival()
+			if rank := a.Leaderboards.Survival.Add(s.ID(), s.Nickname(), run.Score, run.Words); rank > 0 {
+				notifier.AddSuccess("msg.survival.rank", rank)
+			}
+		}
+
+		fData := shared.TemplateDataLettr{}.New(s.Language(), s.KeyboardLayout(), p, s.GameState().LetterHints(), s.PastWords(), a.Config.ImprintUrl, a.Config.Revision, a.Config.FaviconPath)
+		fData.SetStatus(s.GameState().Status())
+		fData.IsEasy = s.GameState().IsEasy()
+		fData.SetMultiBoard(s.GameState().MultiBoard(), s.GameState().LetterHints())
+		fData.SetSurvival(s.GameState().Survival(), s.SurvivalBest())
+		fData.SetPick(a.WordDb, s.Pick())
+		fData.SetSpeed(s.GameState(), now)
+
+		err = templates.Routes.ExecuteTemplate(w, "lettr-form", fData)
+		if err != nil {
+			log.Printf("error t.ExecuteTemplate '/lettr' route: %s", err)
+		}
+
+		if s.GameState().IsSurvival() {
+			err = templates.Routes.ExecuteTemplate(w, "oob-messages", notifier.ToTemplate(s.Language()))
+			if err != nil {
+				log.Printf("error t.ExecuteTemplate 'oob-messages': %s", err)
+			}
 		}
+	}
+}
+
+// parseGuess evaluates the word in the "guess" fields and stores it as row
+// of p. All earlier rows are taken from the stored puzzle, never from the
+// client.
+func parseGuess(p puzzle.Puzzle, row int, form url.Values, solutionWord puzzle.Word, l language.Language, easy bool, wdb puzzle.WordDatabase) (puzzle.Puzzle, error) {
+	if row < 0 || row >= len(p.Guesses) {
+		return p, fmt.Errorf("parseGuess row %d out of range", row)
+	}
+
+	guessedWord, err := puzzle.SliceToWord(form["guess"])
+	if err != nil {
+		return p, fmt.Errorf("parseGuess could not create guessedWord from form input: %s", err.Error())
+	}
 
-		if !wdb.Exists(l, guessedWord) {
+	if easy {
+		if !wdb.ExistsFolded(l, guessedWord) {
 			return p, ErrNotInWordList
 		}
 
-		wg := puzzle.EvaluateGuessedWord(guessedWord, solutionWord)
+		p.Guesses[row] = puzzle.EvaluateFoldedGuessedWord(guessedWord, solutionWord, puzzle.FoldWord(l))
+		return p, nil
+	}
 
-		p.Guesses[ri] = wg
+	if !wdb.Exists(l, guessedWord) {
+		return p, ErrNotInWordList
 	}
 
+	p.Guesses[row] = puzzle.EvaluateGuessedWord(guessedWord, solutionWord)
+
 	return p, nil
 }
+
+// writeResynced answers a guess based on an outdated game with the stored
+// game fData.
+func writeResynced(w http.ResponseWriter, notifier notification.Notifier, l language.Language, fData shared.TemplateDataLettr) {
+	err := templates.Routes.ExecuteTemplate(w, "lettr-form", fData)
+	if err != nil {
+		log.Printf("error t.ExecuteTemplate '/lettr' route: %s", err)
+	}
+
+	notifier.AddInfo("msg.resynced")
+	err = templates.Routes.ExecuteTemplate(w, "oob-messages", notifier.ToTemplate(l))
+	if err != nil {
+		log.Printf("error t.ExecuteTemplate 'oob-messages': %s", err)
+	}
+}
+
+// storedLettrForm renders the game as stored on the server, used whenever
+// the client gets resynced instead of getting its guess evaluated.
+func (a *App) storedLettrForm(l language.Language, keyboardLayout string, g *puzzle.GameState, pastWords []puzzle.Word, survivalBest int, pick puzzle.Pick, now time.Time) shared.TemplateDataLettr {
+	p := g.LastEvaluatedAttempt()
+
+	fData := shared.TemplateDataLettr{}.New(l, keyboardLayout, p, g.LetterHints(), pastWords, a.Config.ImprintUrl, a.Config.Revision, a.Config.FaviconPath)
+	fData.SetStatus(g.Status())
+	fData.IsEasy = g.IsEasy()
+	fData.SetMultiBoard(g.MultiBoard(), g.LetterHints())
+	fData.SetSpeed(g, now)
+	fData.SetSurvival(g.Survival(), survivalBest)
+	fData.SetPick(a.WordDb, pick)
+
+	return fData
+}
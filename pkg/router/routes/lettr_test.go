@@ -9,12 +9,13 @@ import (
 	"github.com/pandorasNox/lettr/pkg/puzzle"
 )
 
-func Test_parseForm(t *testing.T) {
+func Test_parseGuess(t *testing.T) {
 	type args struct {
 		p            puzzle.Puzzle
 		form         url.Values
 		solutionWord puzzle.Word
 		language     language.Language
+		easy         bool
 		wdb          puzzle.WordDatabase
 	}
 	tests := []struct {
@@ -29,7 +30,7 @@ func Test_parseForm(t *testing.T) {
 			// args: args{puzzle{}, url.Values{}, word{'M', 'I', 'S', 'S', 'S'}},
 			args: args{
 				p:            puzzle.Puzzle{},
-				form:         url.Values{"r0": make([]string, 5)},
+				form:         url.Values{"guess": make([]string, 5)},
 				solutionWord: puzzle.Word{'M', 'I', 'S', 'S', 'S'},
 				language:     language.LANG_EN,
 				wdb: puzzle.WordDatabase{Db: map[language.Language]map[puzzle.WordCollection]map[puzzle.Word]bool{
@@ -62,7 +63,7 @@ func Test_parseForm(t *testing.T) {
 			name: "full exact match",
 			args: args{
 				p:            puzzle.Puzzle{},
-				form:         url.Values{"r0": []string{"M", "A", "T", "C", "H"}},
+				form:         url.Values{"guess": []string{"M", "A", "T", "C", "H"}},
 				solutionWord: puzzle.Word{'M', 'A', 'T', 'C', 'H'},
 				language:     language.LANG_EN,
 				wdb: puzzle.WordDatabase{Db: map[language.Language]map[puzzle.WordCollection]map[puzzle.Word]bool{
@@ -87,11 +88,82 @@ func Test_parseForm(t *testing.T) {
 			}},
 			wantErr: false,
 		},
+		{
+			name: "decomposed umlaut matches precomposed solution",
+			args: args{
+				p:            puzzle.Puzzle{},
+				form:         url.Values{"guess": []string{"B", "a\u0308", "R", "E", "N"}},
+				solutionWord: puzzle.Word{'b', 'ä', 'r', 'e', 'n'},
+				language:     language.LANG_DE,
+				wdb: puzzle.WordDatabase{Db: map[language.Language]map[puzzle.WordCollection]map[puzzle.Word]bool{
+					language.LANG_DE: {
+						puzzle.WC_ALL: {
+							puzzle.Word{'b', 'ä', 'r', 'e', 'n'}: true,
+						},
+					},
+				}},
+			},
+			want: puzzle.Puzzle{Guesses: [6]puzzle.WordGuess{
+				{
+					{Letter: 'b', Match: puzzle.MatchExact},
+					{Letter: 'ä', Match: puzzle.MatchExact},
+					{Letter: 'r', Match: puzzle.MatchExact},
+					{Letter: 'e', Match: puzzle.MatchExact},
+					{Letter: 'n', Match: puzzle.MatchExact},
+				},
+			}},
+			wantErr: false,
+		},
+		{
+			name: "missing umlaut is not in wordlist",
+			args: args{
+				p:            puzzle.Puzzle{},
+				form:         url.Values{"guess": []string{"b", "a", "r", "e", "n"}},
+				solutionWord: puzzle.Word{'b', 'ä', 'r', 'e', 'n'},
+				language:     language.LANG_DE,
+				wdb: puzzle.WordDatabase{Db: map[language.Language]map[puzzle.WordCollection]map[puzzle.Word]bool{
+					language.LANG_DE: {
+						puzzle.WC_ALL: {
+							puzzle.Word{'b', 'ä', 'r', 'e', 'n'}: true,
+						},
+					},
+				}},
+			},
+			want:    puzzle.Puzzle{},
+			wantErr: true,
+		},
+		{
+			name: "easy mode ignores missing umlaut",
+			args: args{
+				p:            puzzle.Puzzle{},
+				form:         url.Values{"guess": []string{"b", "a", "r", "e", "n"}},
+				solutionWord: puzzle.Word{'b', 'ä', 'r', 'e', 'n'},
+				language:     language.LANG_DE,
+				easy:         true,
+				wdb: puzzle.WordDatabase{Db: map[language.Language]map[puzzle.WordCollection]map[puzzle.Word]bool{
+					language.LANG_DE: {
+						puzzle.WC_ALL: {
+							puzzle.Word{'b', 'ä', 'r', 'e', 'n'}: true,
+						},
+					},
+				}},
+			},
+			want: puzzle.Puzzle{Guesses: [6]puzzle.WordGuess{
+				{
+					{Letter: 'b', Match: puzzle.MatchExact},
+					{Letter: 'a', Match: puzzle.MatchExact},
+					{Letter: 'r', Match: puzzle.MatchExact},
+					{Letter: 'e', Match: puzzle.MatchExact},
+					{Letter: 'n', Match: puzzle.MatchExact},
+				},
+			}},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got, err := parseForm(tt.args.p, tt.args.form, tt.args.solutionWord, tt.args.language, tt.args.wdb); !reflect.DeepEqual(got, tt.want) || (err != nil) != tt.wantErr {
-				t.Errorf("parseForm() = %v, %v; want %v, %v", got, err != nil, tt.want, tt.wantErr)
+			if got, err := parseGuess(tt.args.p, 0, tt.args.form, tt.args.solutionWord, tt.args.language, tt.args.easy, tt.args.wdb); !reflect.DeepEqual(got, tt.want) || (err != nil) != tt.wantErr {
+				t.Errorf("parseGuess() = %v, %v; want %v, %v", got, err != nil, tt.want, tt.wantErr)
 			}
 		})
 	}
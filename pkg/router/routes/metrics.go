@@ -3,7 +3,6 @@ package routes
 import (
 	"net/http"
 
-	"github.com/pandorasNox/lettr/pkg/server"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -13,7 +12,7 @@ type metrics struct {
 	honeyTrapped prometheus.Gauge
 }
 
-func GetMetrics(server *server.Server) http.HandlerFunc {
+func (a *App) GetMetrics() http.HandlerFunc {
 	// promhandle := promhttp.Handler()
 
 	reg := prometheus.NewRegistry()
@@ -26,7 +25,7 @@ func GetMetrics(server *server.Server) http.HandlerFunc {
 	reg.MustRegister(collectors.NewBuildInfoCollector())
 
 	return func(w http.ResponseWriter, r *http.Request) {
-		m.honeyTrapped.Set(float64(server.Metrics().HoneyTrapped()))
+		m.honeyTrapped.Set(float64(a.Server.Metrics().HoneyTrapped()))
 
 		promHandler := promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
 
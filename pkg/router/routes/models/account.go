@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/account"
+	"github.com/pandorasNox/lettr/pkg/language"
+)
+
+// TemplateDataAccount renders the login form for anonymous players and the
+// account settings otherwise.
+type TemplateDataAccount struct {
+	Language language.Language
+	Email    string
+}
+
+func (t TemplateDataAccount) IsLoggedIn() bool {
+	return t.Email != ""
+}
+
+// TemplateDataAccountConfirm renders the page a login link opens. Logging
+// in needs another click, so link scanners of mail providers don't use up
+// the token.
+type TemplateDataAccountConfirm struct {
+	Language language.Language
+	Token    string
+	Invalid  bool
+}
+
+// AccountExport is everything stored about an account, as handed out by
+// the data export.
+type AccountExport struct {
+	Email     string                `json:"email"`
+	CreatedAt time.Time             `json:"createdAt"`
+	Progress  AccountExportProgress `json:"progress"`
+}
+
+type AccountExportProgress struct {
+	PastWords    []string `json:"pastWords"`
+	SurvivalBest int      `json:"survivalBest"`
+	Nickname     string   `json:"nickname"`
+	Streak       int      `json:"streak"`
+	SolvedDay    string   `json:"solvedDay"`
+	SolvedToday  int      `json:"solvedToday"`
+}
+
+func (AccountExport) New(a account.Account) AccountExport {
+	words := []string{}
+	for _, w := range a.Progress.PastWords {
+		words = append(words, w.String())
+	}
+
+	return AccountExport{
+		Email:     a.Email,
+		CreatedAt: a.CreatedAt,
+		Progress: AccountExportProgress{
+			PastWords:    words,
+			SurvivalBest: a.Progress.SurvivalBest,
+			Nickname:     a.Progress.Nickname,
+			Streak:       a.Progress.Streak,
+			SolvedDay:    a.Progress.SolvedDay,
+			SolvedToday:  a.Progress.SolvedToday,
+		},
+	}
+}
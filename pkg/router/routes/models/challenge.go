@@ -0,0 +1,18 @@
+package models
+
+import (
+	"github.com/pandorasNox/lettr/pkg/challenge"
+	"github.com/pandorasNox/lettr/pkg/language"
+)
+
+// TemplateDataChallenge lists the challenges a session created. BaseURL is
+// prepended to the share links.
+type TemplateDataChallenge struct {
+	Language   language.Language
+	BaseURL    string
+	Challenges []challenge.Challenge
+}
+
+func (TemplateDataChallenge) New(l language.Language, baseURL string, cs []challenge.Challenge) TemplateDataChallenge {
+	return TemplateDataChallenge{Language: l, BaseURL: baseURL, Challenges: cs}
+}
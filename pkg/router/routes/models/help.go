@@ -1,8 +1,12 @@
 package models
 
-import "github.com/pandorasNox/lettr/pkg/puzzle"
+import (
+	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+)
 
 type TemplateDataHelpPage struct {
+	Language                    language.Language
 	SolutionWord                string
 	SolutionHasDublicateLetters bool
 	LetterHints                 []rune
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+)
+
+// HistoryGame is a finished game together with its position in the
+// history, which addresses it in the replay route.
+type HistoryGame struct {
+	Index int
+	puzzle.GameRecord
+}
+
+// TemplateDataHistory lists the finished games, newest first.
+type TemplateDataHistory struct {
+	Language language.Language
+	Games    []HistoryGame
+}
+
+func (TemplateDataHistory) New(l language.Language, history []puzzle.GameRecord) TemplateDataHistory {
+	games := []HistoryGame{}
+	for i, r := range history {
+		games = append(games, HistoryGame{Index: i, GameRecord: r})
+	}
+	slices.Reverse(games)
+
+	return TemplateDataHistory{Language: l, Games: games}
+}
+
+// TemplateDataHistoryReplay shows a finished game after its first Row
+// guesses.
+type TemplateDataHistoryReplay struct {
+	Language language.Language
+	Game     HistoryGame
+	Row      int
+	Boards   [][]puzzle.WordGuess
+}
+
+func (TemplateDataHistoryReplay) New(l language.Language, g HistoryGame, row int) TemplateDataHistoryReplay {
+	row = max(0, min(row, g.Rows()))
+
+	// single board games use the usual grid of six rows
+	size := len(puzzle.Puzzle{}.Guesses)
+	if len(g.Boards) > 1 {
+		size = puzzle.MultiBoardAttempts(len(g.Boards))
+	}
+
+	boards := [][]puzzle.WordGuess{}
+	for i := range g.Boards {
+		boards = append(boards, g.Replay(i, row, size))
+	}
+
+	return TemplateDataHistoryReplay{Language: l, Game: g, Row: row, Boards: boards}
+}
+
+func (t TemplateDataHistoryReplay) HasPrev() bool {
+	return t.Row > 0
+}
+
+func (t TemplateDataHistoryReplay) HasNext() bool {
+	return t.Row < t.Game.Rows()
+}
+
+func (t TemplateDataHistoryReplay) PrevRow() int {
+	return max(t.Row-1, 0)
+}
+
+func (t TemplateDataHistoryReplay) NextRow() int {
+	return min(t.Row+1, t.Game.Rows())
+}
+
+// HistoryExport is one finished game as handed out by the JSON export.
+type HistoryExport struct {
+	Language   language.Language      `json:"language"`
+	Solutions  []string               `json:"solutions"`
+	Boards     [][]HistoryExportGuess `json:"boards"`
+	Hints      []string               `json:"hints"`
+	Outcome    puzzle.Outcome         `json:"outcome"`
+	StartedAt  time.Time              `json:"startedAt"`
+	FinishedAt time.Time              `json:"finishedAt"`
+}
+
+type HistoryExportGuess struct {
+	Word    string   `json:"word"`
+	Matches []string `json:"matches"`
+}
+
+var matchNames = map[puzzle.Match]string{
+	puzzle.MatchNone:  "none",
+	puzzle.MatchVague: "vague",
+	puzzle.MatchExact: "exact",
+}
+
+func (HistoryExport) New(history []puzzle.GameRecord) []HistoryExport {
+	export := []HistoryExport{}
+	for _, r := range history {
+		e := HistoryExport{
+			Language:   r.Language,
+			Solutions:  []string{},
+			Boards:     [][]HistoryExportGuess{},
+			Hints:      []string{},
+			Outcome:    r.Outcome,
+			StartedAt:  r.StartedAt,
+			FinishedAt: r.FinishedAt,
+		}
+
+		for _, w := range r.Solutions {
+			e.Solutions = append(e.Solutions, w.String())
+		}
+		for _, h := range r.Hints {
+			e.Hints = append(e.Hints, string(h))
+		}
+
+		for _, rows := range r.Boards {
+			guesses := []HistoryExportGuess{}
+			for _, wg := range rows {
+				g := HistoryExportGuess{Matches: []string{}}
+				for _, lg := range wg {
+					g.Word += string(lg.Letter)
+					g.Matches = append(g.Matches, matchNames[lg.Match])
+				}
+				guesses = append(guesses, g)
+			}
+			e.Boards = append(e.Boards, guesses)
+		}
+
+		export = append(export, e)
+	}
+
+	return export
+}
@@ -14,9 +14,9 @@ type TemplateDataIndex struct {
 	shared.TemplateDataLettr
 }
 
-func (tdi TemplateDataIndex) New(l language.Language, p puzzle.Puzzle, letterHints []rune, pastWords []puzzle.Word, imprintUrl string, revision string, faviconPath string) TemplateDataIndex {
-	tdi.TemplateDataLettr = tdi.TemplateDataLettr.New(l, p, letterHints, pastWords, imprintUrl, revision, faviconPath)
-	tdi.JSCachePurgeTimestamp = time.Now().Unix()
+func (tdi TemplateDataIndex) New(now time.Time, l language.Language, keyboardLayout string, p puzzle.Puzzle, letterHints []rune, pastWords []puzzle.Word, imprintUrl string, revision string, faviconPath string) TemplateDataIndex {
+	tdi.TemplateDataLettr = tdi.TemplateDataLettr.New(l, keyboardLayout, p, letterHints, pastWords, imprintUrl, revision, faviconPath)
+	tdi.JSCachePurgeTimestamp = now.Unix()
 
 	return tdi
 }
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/leaderboard"
+)
+
+// TemplateDataLeaderboard renders the survival, streak and daily boards, the
+// entries of SessionID are highlighted.
+type TemplateDataLeaderboard struct {
+	Language  language.Language
+	Survival  []leaderboard.Entry
+	Streak    []leaderboard.Entry
+	Daily     []leaderboard.Entry
+	SessionID string
+	Nickname  string
+}
+
+func (TemplateDataLeaderboard) New(l language.Language, bs leaderboard.Boards, sessionID string, nickname string) TemplateDataLeaderboard {
+	return TemplateDataLeaderboard{
+		Language:  l,
+		Survival:  bs.Survival.Top(),
+		Streak:    bs.Streak.Top(),
+		Daily:     bs.Daily.Top(),
+		SessionID: sessionID,
+		Nickname:  nickname,
+	}
+}
+
+const (
+	NICKNAME_MIN_LENGTH = 3
+	NICKNAME_MAX_LENGTH = 20
+)
+
+var ErrFailedNicknameValidation = errors.New("validation failed: nickname is either to long, to short or contains forbidden characters")
+
+// ValidateNickname trims n and checks it like the message of a suggestion,
+// additionally only letters, digits, spaces, '-' and '_' are allowed. An
+// empty nickname is valid and makes the player anonymous again.
+func ValidateNickname(n string) (string, error) {
+	n = strings.TrimSpace(n)
+	if n == "" {
+		return "", nil
+	}
+
+	l := utf8.RuneCountInString(n)
+	if l < NICKNAME_MIN_LENGTH || l > NICKNAME_MAX_LENGTH {
+		return "", ErrFailedNicknameValidation
+	}
+
+	p := bluemonday.StrictPolicy()
+	if p.Sanitize(n) != n {
+		return "", ErrFailedNicknameValidation
+	}
+
+	for _, r := range n {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ' ' && r != '-' && r != '_' {
+			return "", ErrFailedNicknameValidation
+		}
+	}
+
+	return n, nil
+}
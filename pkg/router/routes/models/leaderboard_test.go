@@ -0,0 +1,34 @@
+package models
+
+import "testing"
+
+func TestValidateNickname(t *testing.T) {
+	tests := []struct {
+		name     string
+		nickname string
+		want     string
+		wantErr  error
+	}{
+		{name: "Nickname valid", nickname: "wordsmith", want: "wordsmith", wantErr: nil},
+		{name: "Nickname valid (trimmed)", nickname: "  Wörd Smith_1 ", want: "Wörd Smith_1", wantErr: nil},
+		{name: "Nickname valid (dash)", nickname: "x-ray", want: "x-ray", wantErr: nil},
+		{name: "Nickname empty (anonymous)", nickname: "   ", want: "", wantErr: nil},
+
+		{name: "Nickname invalid (to short)", nickname: "ab", wantErr: ErrFailedNicknameValidation},
+		{name: "Nickname invalid (to long)", nickname: "abcdefghijklmnopqrstu", wantErr: ErrFailedNicknameValidation},
+		{name: "Nickname invalid (html)", nickname: "<b>bold</b>", wantErr: ErrFailedNicknameValidation},
+		{name: "Nickname invalid (entity)", nickname: "a&amp;b", wantErr: ErrFailedNicknameValidation},
+		{name: "Nickname invalid (special chars: emoji's (😁))", nickname: "smile😁", wantErr: ErrFailedNicknameValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateNickname(tt.nickname)
+			if err != tt.wantErr {
+				t.Errorf("ValidateNickname() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ValidateNickname() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
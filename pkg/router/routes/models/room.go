@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/room"
+	"github.com/pandorasNox/lettr/pkg/router/routes/models/shared"
+)
+
+// RoomTimeLimits are the race durations a host can pick from.
+var RoomTimeLimits = []time.Duration{1 * time.Minute, 3 * time.Minute, 5 * time.Minute, 10 * time.Minute}
+
+type TemplateDataRoomLobby struct {
+	Language   language.Language
+	TimeLimits []int
+}
+
+func (TemplateDataRoomLobby) New(l language.Language) TemplateDataRoomLobby {
+	minutes := []int{}
+	for _, d := range RoomTimeLimits {
+		minutes = append(minutes, int(d.Minutes()))
+	}
+
+	return TemplateDataRoomLobby{Language: l, TimeLimits: minutes}
+}
+
+// TemplateDataRoom renders a room from the point of view of one session.
+// Me is the zero Player for sessions not taking part.
+type TemplateDataRoom struct {
+	Language language.Language
+	Room     room.Room
+	Me       room.Player
+	IsPlayer bool
+	IsHost   bool
+	Lettr    shared.TemplateDataLettr
+
+	TimeLimitMinutes int
+}
+
+func (TemplateDataRoom) New(l language.Language, keyboardLayout string, r room.Room, sessionID string) TemplateDataRoom {
+	me, isPlayer := r.Player(sessionID)
+
+	lettr := shared.TemplateDataLettr{}.New(r.Language, keyboardLayout, me.Puzzle, []rune{}, nil, "", "", "")
+	lettr.InRoom = true
+
+	return TemplateDataRoom{
+		Language: l,
+		Room:     r,
+		Me:       me,
+		IsPlayer: isPlayer,
+		IsHost:   r.HostID == sessionID,
+		Lettr:    lettr,
+
+		TimeLimitMinutes: int(r.TimeLimit.Minutes()),
+	}
+}
@@ -3,6 +3,7 @@ package shared
 import (
 	"slices"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/pandorasNox/lettr/pkg/language"
 	"github.com/pandorasNox/lettr/pkg/puzzle"
@@ -19,17 +20,13 @@ type Keyboard struct {
 	KeyGrid [][]keyboardKey
 }
 
-func (k *Keyboard) Init(l language.Language, lgs []puzzle.LetterGuess, letterHints []rune) {
-	k.KeyGrid = [][]keyboardKey{
-		{{"Q", false, puzzle.MatchNone, false}, {"W", false, puzzle.MatchNone, false}, {"E", false, puzzle.MatchNone, false}, {"R", false, puzzle.MatchNone, false}, {"T", false, puzzle.MatchNone, false}, {"Y", false, puzzle.MatchNone, false}, {"U", false, puzzle.MatchNone, false}, {"I", false, puzzle.MatchNone, false}, {"O", false, puzzle.MatchNone, false}, {"P", false, puzzle.MatchNone, false}, {"Delete", false, puzzle.MatchNone, false}},
-		{{"A", false, puzzle.MatchNone, false}, {"S", false, puzzle.MatchNone, false}, {"D", false, puzzle.MatchNone, false}, {"F", false, puzzle.MatchNone, false}, {"G", false, puzzle.MatchNone, false}, {"H", false, puzzle.MatchNone, false}, {"J", false, puzzle.MatchNone, false}, {"K", false, puzzle.MatchNone, false}, {"L", false, puzzle.MatchNone, false}, {"Enter", false, puzzle.MatchNone, false}},
-		{{"Z", false, puzzle.MatchNone, false}, {"X", false, puzzle.MatchNone, false}, {"C", false, puzzle.MatchNone, false}, {"V", false, puzzle.MatchNone, false}, {"B", false, puzzle.MatchNone, false}, {"N", false, puzzle.MatchNone, false}, {"M", false, puzzle.MatchNone, false}},
-	}
+func (k *Keyboard) Init(l language.Language, layoutName string, lgs []puzzle.LetterGuess, letterHints []rune) {
+	k.KeyGrid = newKeyGrid(l, layoutName)
 
 	for ri, keyboardRow := range k.KeyGrid {
 		for ki, currentKey := range keyboardRow {
 			// ensure length is one (for to rune conversion, skipping keys like "Enter" or "Delete")
-			if len(currentKey.Key) > 1 {
+			if utf8.RuneCountInString(currentKey.Key) > 1 {
 				continue
 			}
 
@@ -68,6 +65,25 @@ func (k *Keyboard) Init(l language.Language, lgs []puzzle.LetterGuess, letterHin
 	}
 }
 
+func newKeyGrid(l language.Language, layoutName string) [][]keyboardKey {
+	d, ok := language.DefaultRegistry.Lookup(l)
+	if !ok {
+		d, _ = language.DefaultRegistry.Lookup(language.LANG_EN)
+	}
+
+	grid := [][]keyboardKey{}
+	for _, row := range d.Keyboard(layoutName) {
+		keyRow := []keyboardKey{}
+		for _, key := range row {
+			keyRow = append(keyRow, keyboardKey{key, false, puzzle.MatchNone, false})
+		}
+
+		grid = append(grid, keyRow)
+	}
+
+	return grid
+}
+
 func firstRune(s string) rune {
 	for _, r := range s {
 		return r
@@ -1,33 +1,132 @@
 package shared
 
 import (
+	"time"
+
 	"github.com/pandorasNox/lettr/pkg/language"
 	"github.com/pandorasNox/lettr/pkg/puzzle"
 )
 
 type TemplateDataLettr struct {
-	Data        puzzle.Puzzle
-	IsSolved    bool
-	IsLoose     bool
-	Language    language.Language
-	Revision    string
-	FaviconPath string
-	Keyboard    Keyboard
-	PastWords   []puzzle.Word
-	ImprintUrl  string
-}
-
-func (fd TemplateDataLettr) New(l language.Language, p puzzle.Puzzle, letterHints []rune, pastWords []puzzle.Word, imprintUrl string, revision string, faviconPath string) TemplateDataLettr {
+	Data            puzzle.Puzzle
+	IsSolved        bool
+	IsLoose         bool
+	IsEasy          bool
+	InRoom          bool
+	IsSpeed         bool
+	IsTimedOut      bool
+	Remaining       time.Duration
+	SolveTime       time.Duration
+	GuessTimes      []time.Duration
+	MultiBoard      puzzle.MultiBoard
+	BoardCount      int
+	BoardModes      []int
+	IsSurvival      bool
+	Survival        puzzle.SurvivalRun
+	SurvivalBest    int
+	Collection      puzzle.WordCollection
+	Collections     []CollectionOption
+	Difficulty      puzzle.Difficulty
+	Difficulties    []puzzle.Difficulty
+	Language        language.Language
+	Revision        string
+	FaviconPath     string
+	Keyboard        Keyboard
+	KeyboardLayout  string
+	KeyboardLayouts []language.KeyboardLayout
+	AlphabetPattern string
+	PastWords       []puzzle.Word
+	ImprintUrl      string
+	Languages       []language.Definition
+}
+
+func (fd TemplateDataLettr) New(l language.Language, keyboardLayout string, p puzzle.Puzzle, letterHints []rune, pastWords []puzzle.Word, imprintUrl string, revision string, faviconPath string) TemplateDataLettr {
+	d, ok := language.DefaultRegistry.Lookup(l)
+	if !ok {
+		d, _ = language.DefaultRegistry.Lookup(language.LANG_EN)
+	}
+
+	if _, ok := language.LookupKeyboardLayout(keyboardLayout); !ok {
+		keyboardLayout = d.KeyboardLayout
+	}
+
 	kb := Keyboard{}
-	kb.Init(l, p.LetterGuesses(), letterHints)
+	kb.Init(l, keyboardLayout, p.LetterGuesses(), letterHints)
 
 	return TemplateDataLettr{
-		Data:        p,
-		Language:    l,
-		Revision:    revision,
-		FaviconPath: faviconPath,
-		Keyboard:    kb,
-		PastWords:   pastWords,
-		ImprintUrl:  imprintUrl,
+		Data:            p,
+		Language:        l,
+		Revision:        revision,
+		FaviconPath:     faviconPath,
+		Keyboard:        kb,
+		KeyboardLayout:  keyboardLayout,
+		KeyboardLayouts: language.KeyboardLayouts,
+		AlphabetPattern: d.AlphabetPattern(),
+		PastWords:       pastWords,
+		ImprintUrl:      imprintUrl,
+		Languages:       language.DefaultRegistry.Definitions(),
+		BoardCount:      1,
+		BoardModes:      puzzle.MultiBoardModes,
+	}
+}
+
+// CollectionOption is a themed word collection offered in the new game
+// picker.
+type CollectionOption struct {
+	Name puzzle.WordCollection
+	Tags []string
+}
+
+// SetPick offers the themed collections of the language and the
+// difficulties in the new game pickers, selected is the pick of the player.
+func (fd *TemplateDataLettr) SetPick(wdb puzzle.WordDatabase, selected puzzle.Pick) {
+	fd.Collection = selected.Collection
+	fd.Difficulty = selected.Difficulty
+	fd.Difficulties = puzzle.Difficulties
+	fd.Collections = []CollectionOption{}
+	for _, c := range wdb.Collections(fd.Language) {
+		fd.Collections = append(fd.Collections, CollectionOption{Name: c, Tags: wdb.CollectionTags(fd.Language, c)})
 	}
 }
+
+// SetStatus marks the game as solved or lost, abandoned games count as lost.
+func (fd *TemplateDataLettr) SetStatus(s puzzle.Status) {
+	fd.IsSolved = s == puzzle.STATUS_WON
+	fd.IsLoose = s == puzzle.STATUS_LOST || s == puzzle.STATUS_ABANDONED
+}
+
+// SetSpeed fills the timer and the solve time stats of a speed game. A timed
+// out game counts as lost.
+func (fd *TemplateDataLettr) SetSpeed(g *puzzle.GameState, now time.Time) {
+	if !g.IsSpeed() {
+		return
+	}
+
+	fd.IsSpeed = true
+	fd.IsTimedOut = g.IsTimedOut(now)
+	fd.IsLoose = fd.IsLoose || fd.IsTimedOut
+	fd.Remaining = g.Remaining(now)
+	fd.SolveTime, _ = g.SolveTime()
+	fd.GuessTimes = g.GuessTimes()
+}
+
+// SetMultiBoard switches the template to render all boards side by side with
+// a keyboard combining the matches of every board. Does nothing for games
+// on a single board.
+func (fd *TemplateDataLettr) SetMultiBoard(m puzzle.MultiBoard, letterHints []rune) {
+	if len(m.Boards) == 0 {
+		return
+	}
+
+	fd.MultiBoard = m
+	fd.BoardCount = len(m.Boards)
+	fd.Keyboard.Init(fd.Language, fd.KeyboardLayout, m.LetterGuesses(), letterHints)
+}
+
+// SetSurvival shows the running score of a survival run and the personal
+// best of the session.
+func (fd *TemplateDataLettr) SetSurvival(run puzzle.SurvivalRun, best int) {
+	fd.IsSurvival = run.Active
+	fd.Survival = run
+	fd.SurvivalBest = best
+}
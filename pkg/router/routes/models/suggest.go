@@ -2,10 +2,11 @@ package models
 
 import (
 	"errors"
-	"regexp"
 	"slices"
+	"unicode/utf8"
 
 	"github.com/microcosm-cc/bluemonday"
+	"github.com/pandorasNox/lettr/pkg/language"
 )
 
 type TemplateDataSuggest struct {
@@ -14,17 +15,22 @@ type TemplateDataSuggest struct {
 	Language                         string
 	Action                           string
 	SecurityHoneypotMessageInputName string
+	UiLanguage                       language.Language
+	Languages                        []language.Definition
 }
 
-var RegexpAllowedWordCharacters = regexp.MustCompile(`^[A-Za-zöäüÖÄÜß]{5}$`)
-
 var ErrFailedWordValidation = errors.New("validation failed: word is either to long, to short or contains forbidden characters")
 var ErrFailedMessageValidation = errors.New("validation failed: message contains invalid data")
 var ErrFailedActionValidation = errors.New("validation failed: action invalid")
 var ErrFailedLanguageValidation = errors.New("validation failed: language invalid")
 
 func (tds TemplateDataSuggest) Validate() error {
-	if !RegexpAllowedWordCharacters.Match([]byte(tds.Word)) {
+	d, ok := language.DefaultRegistry.Lookup(language.Language(tds.Language))
+	if !ok {
+		return ErrFailedLanguageValidation
+	}
+
+	if !isFiveLetterWord(d, d.Normalize(tds.Word)) && !isFiveLetterWord(d, d.Transliterate(tds.Word)) {
 		return ErrFailedWordValidation
 	}
 
@@ -38,9 +44,11 @@ func (tds TemplateDataSuggest) Validate() error {
 		return ErrFailedActionValidation
 	}
 
-	if tds.Language != "german" && tds.Language != "english" {
-		return ErrFailedLanguageValidation
-	}
-
 	return nil
 }
+
+// isFiveLetterWord reports whether the normalized s consists of five letters
+// of the alphabet of d.
+func isFiveLetterWord(d language.Definition, normalized string) bool {
+	return utf8.RuneCountInString(normalized) == 5 && d.InAlphabet(normalized)
+}
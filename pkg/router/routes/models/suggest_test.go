@@ -14,24 +14,34 @@ func TestTemplateDataSuggest_validate(t *testing.T) {
 		fields  fields
 		wantErr error
 	}{
-		{name: "Suggested word match", fields: fields{Word: "gamer", Action: "add", Language: "english", Message: "test"}, wantErr: nil},
-		{name: "Suggested word match", fields: fields{Word: "GAMER", Action: "add", Language: "english", Message: "test"}, wantErr: nil},
-		{name: "Suggested word match", fields: fields{Word: "preuß", Action: "add", Language: "english", Message: "test"}, wantErr: nil},
-		{name: "Suggested word match", fields: fields{Word: "höste", Action: "add", Language: "english", Message: "test"}, wantErr: nil},
-		{name: "Suggested word match", fields: fields{Word: "hÖste", Action: "add", Language: "english", Message: "test"}, wantErr: nil},
-		{name: "Suggested word match", fields: fields{Word: "HÖSTE", Action: "add", Language: "english", Message: "test"}, wantErr: nil},
-		{name: "Suggested word match", fields: fields{Word: "fülle", Action: "add", Language: "english", Message: "test"}, wantErr: nil},
-		{name: "Suggested word match", fields: fields{Word: "FÜLLE", Action: "add", Language: "english", Message: "test"}, wantErr: nil},
-		{name: "Suggested word match", fields: fields{Word: "größe", Action: "add", Language: "english", Message: "test"}, wantErr: nil},
-		{name: "Suggested word match", fields: fields{Word: "GRÖßE", Action: "add", Language: "english", Message: "test"}, wantErr: nil},
+		{name: "Suggested word match", fields: fields{Word: "gamer", Action: "add", Language: "en", Message: "test"}, wantErr: nil},
+		{name: "Suggested word match", fields: fields{Word: "GAMER", Action: "add", Language: "en", Message: "test"}, wantErr: nil},
+		{name: "Suggested word match", fields: fields{Word: "preuß", Action: "add", Language: "de", Message: "test"}, wantErr: nil},
+		{name: "Suggested word match", fields: fields{Word: "höste", Action: "add", Language: "de", Message: "test"}, wantErr: nil},
+		{name: "Suggested word match", fields: fields{Word: "hÖste", Action: "add", Language: "de", Message: "test"}, wantErr: nil},
+		{name: "Suggested word match", fields: fields{Word: "HÖSTE", Action: "add", Language: "de", Message: "test"}, wantErr: nil},
+		{name: "Suggested word match", fields: fields{Word: "fülle", Action: "add", Language: "de", Message: "test"}, wantErr: nil},
+		{name: "Suggested word match", fields: fields{Word: "FÜLLE", Action: "add", Language: "de", Message: "test"}, wantErr: nil},
+		{name: "Suggested word match", fields: fields{Word: "größe", Action: "add", Language: "de", Message: "test"}, wantErr: nil},
+		{name: "Suggested word match", fields: fields{Word: "GRÖßE", Action: "add", Language: "de", Message: "test"}, wantErr: nil},
+		{name: "Suggested word match (decomposed umlaut)", fields: fields{Word: "gro\u0308ße", Action: "add", Language: "de", Message: "test"}, wantErr: nil},
+		{name: "Suggested word match (uppercase sharp s)", fields: fields{Word: "GRÖẞE", Action: "add", Language: "de", Message: "test"}, wantErr: nil},
+		{name: "Suggested word match (written out umlaut)", fields: fields{Word: "Baeren", Action: "add", Language: "de", Message: "test"}, wantErr: nil},
+		{name: "Suggested word match (written out umlaut and sharp s)", fields: fields{Word: "GROESSE", Action: "add", Language: "de", Message: "test"}, wantErr: nil},
 
-		{name: "Suggested word invalid (special chars: ?)", fields: fields{Word: "?????"}, wantErr: ErrFailedWordValidation},
-		{name: "Suggested word invalid (special chars: ô)", fields: fields{Word: "grôss"}, wantErr: ErrFailedWordValidation},
-		{name: "Suggested word invalid (special chars: emoji's (😁))", fields: fields{Word: "😁,😁,😁"}, wantErr: ErrFailedWordValidation},
-		{name: "Suggested word invalid (word to short en)", fields: fields{Word: "tiny"}, wantErr: ErrFailedWordValidation},
-		{name: "Suggested word invalid (word to short de)", fields: fields{Word: "kurz"}, wantErr: ErrFailedWordValidation},
-		{name: "Suggested word invalid (word to long en)", fields: fields{Word: "toolong"}, wantErr: ErrFailedWordValidation},
-		{name: "Suggested word invalid (word to long de)", fields: fields{Word: "zulang"}, wantErr: ErrFailedWordValidation},
+		{name: "Suggested word invalid (special chars: ?)", fields: fields{Word: "?????", Language: "en"}, wantErr: ErrFailedWordValidation},
+		{name: "Suggested word invalid (special chars: ô)", fields: fields{Word: "grôss", Language: "de"}, wantErr: ErrFailedWordValidation},
+		{name: "Suggested word invalid (special chars: emoji's (😁))", fields: fields{Word: "😁,😁,😁", Language: "en"}, wantErr: ErrFailedWordValidation},
+		{name: "Suggested word invalid (word to short en)", fields: fields{Word: "tiny", Language: "en"}, wantErr: ErrFailedWordValidation},
+		{name: "Suggested word invalid (word to short de)", fields: fields{Word: "kurz", Language: "de"}, wantErr: ErrFailedWordValidation},
+		{name: "Suggested word invalid (word to long en)", fields: fields{Word: "toolong", Language: "en"}, wantErr: ErrFailedWordValidation},
+		{name: "Suggested word invalid (word to long de)", fields: fields{Word: "zulang", Language: "de"}, wantErr: ErrFailedWordValidation},
+		{name: "Suggested word invalid (umlaut not in english alphabet)", fields: fields{Word: "größe", Language: "en"}, wantErr: ErrFailedWordValidation},
+		{name: "Suggested word invalid (no transliteration in english)", fields: fields{Word: "baeren", Language: "en"}, wantErr: ErrFailedWordValidation},
+
+		{name: "Suggested language invalid (unknown)", fields: fields{Word: "gamer", Language: "xx"}, wantErr: ErrFailedLanguageValidation},
+		{name: "Suggested language invalid (empty)", fields: fields{Word: "gamer"}, wantErr: ErrFailedLanguageValidation},
+		{name: "Suggested action invalid", fields: fields{Word: "gamer", Language: "en", Action: "rename"}, wantErr: ErrFailedActionValidation},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -3,6 +3,8 @@ package routes
 import (
 	"log"
 	"net/http"
+	"slices"
+	"strconv"
 
 	"github.com/pandorasNox/lettr/pkg/language"
 	"github.com/pandorasNox/lettr/pkg/puzzle"
@@ -11,9 +13,14 @@ import (
 	"github.com/pandorasNox/lettr/pkg/session"
 )
 
-func PostNew(sessions *session.Sessions, wdb puzzle.WordDatabase, imprintUrl string, revision string, faviconPath string) http.HandlerFunc {
+func (a *App) PostNew() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		s := session.HandleSession(w, r, sessions, wdb)
+		s, err := session.StartSession(w, r, a.Sessions, a.WordDb)
+		if err != nil {
+			writeErrorMessage(w, a.NewNotifier(), s.Language(), "msg.tooManySessions")
+			return
+		}
+		s.AbandonGame(a.Clock.Now())
 
 		// handle lang switch
 		l := s.Language()
@@ -21,30 +28,67 @@ func PostNew(sessions *session.Sessions, wdb puzzle.WordDatabase, imprintUrl str
 		if maybeLang != "" {
 			l, _ = language.NewLang(maybeLang)
 			s.SetLanguage(l)
+			s.RotateToken(a.Clock.Now())
+		}
+
+		// themed collections differ per language, unknown ones fall back to
+		// the default solutions
+		pick := s.Pick()
+		if r.Form.Has("collection") {
+			pick.Collection = puzzle.WordCollection(r.FormValue("collection"))
+		}
+		if !a.WordDb.HasCollection(l, pick.Collection) {
+			pick.Collection = ""
+		}
+		if r.Form.Has("difficulty") {
+			pick.Difficulty, _ = puzzle.ParseDifficulty(r.FormValue("difficulty"))
+		}
+		s.SetPick(pick)
+
+		p := puzzle.Puzzle{}
 
+		for _, w := range s.GameState().SolutionWords() {
+			s.AddPastWord(w)
+		}
+		// survival runs are played on a single board
+		survival := r.FormValue("survival") == "on"
+		boards, _ := strconv.Atoi(r.FormValue("boards"))
+		if !survival && slices.Contains(puzzle.MultiBoardModes, boards) {
+			s.SetGameState(puzzle.NewMultiBoardGame(a.Rand, l, s.Pick(), a.WordDb, s.PastWords(), boards))
+		} else {
+			s.NewGame(a.Rand, l, a.WordDb)
+		}
+		s.GameState().SetEasy(r.FormValue("easy") == "on")
+		s.GameState().SetSurvival(puzzle.SurvivalRun{Active: survival})
+		if r.FormValue("speed") == "on" {
+			s.GameState().StartSpeed(a.Clock.Now(), a.Config.SpeedTimeLimit)
+		}
+		// saved before writing the response, stores keeping the session in
+		// the cookie can't set it afterwards
+		a.Sessions.UpdateOrSet(s)
+
+		if maybeLang != "" {
 			type TemplateDataLanguge struct {
 				Language language.Language
 			}
 			tData := TemplateDataLanguge{Language: l}
 
-			err := templates.Routes.ExecuteTemplate(w, "oob-lang-switch", tData)
+			err = templates.Routes.ExecuteTemplate(w, "oob-lang-switch", tData)
 			if err != nil {
 				log.Printf("error t.ExecuteTemplate '/new' route: %s", err)
 			}
 		}
 
-		p := puzzle.Puzzle{}
-
-		s.AddPastWord(s.GameState().ActiveSolutionWord())
-		s.NewGame(l, wdb)
-		sessions.UpdateOrSet(s)
-
-		fData := shared.TemplateDataLettr{}.New(s.Language(), p, s.GameState().LetterHints(), s.PastWords(), imprintUrl, revision, faviconPath)
-		fData.IsSolved = p.IsSolved()
-		fData.IsLoose = p.IsLoose()
+		fData := shared.TemplateDataLettr{}.New(s.Language(), s.KeyboardLayout(), p, s.GameState().LetterHints(), s.PastWords(), a.Config.ImprintUrl, a.Config.Revision, a.Config.FaviconPath)
+		fData.SetStatus(s.GameState().Status())
+		fData.IsEasy = s.GameState().IsEasy()
+		fData.SetMultiBoard(s.GameState().MultiBoard(), s.GameState().LetterHints())
+		fData.SetSpeed(s.GameState(), a.Clock.Now())
+		fData.SetSurvival(s.GameState().Survival(), s.SurvivalBest())
+		fData.SetPick(a.WordDb, s.Pick())
 
 		// w.Header().Add("HX-Refresh", "true")
-		err := templates.Routes.ExecuteTemplate(w, "lettr-form", fData)
+		err = templates.Routes.ExecuteTemplate(w, "lettr-form", fData)
 		if err != nil {
 			log.Printf("error t.ExecuteTemplate '/new' route: %s", err)
 		}
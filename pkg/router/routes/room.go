@@ -0,0 +1,256 @@
+package routes
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/bot"
+	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/room"
+	"github.com/pandorasNox/lettr/pkg/router/routes/models"
+	"github.com/pandorasNox/lettr/pkg/router/routes/templates"
+	"github.com/pandorasNox/lettr/pkg/session"
+)
+
+func (a *App) GetRooms() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, _ := session.PeekSession(w, r, a.Sessions)
+
+		err := templates.Routes.ExecuteTemplate(w, "rooms-lobby", models.TemplateDataRoomLobby{}.New(s.Language()))
+		if err != nil {
+			log.Printf("error t.ExecuteTemplate '/rooms' route: %s", err)
+		}
+	}
+}
+
+func (a *App) PostRooms() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s := session.HandleSession(w, r, a.Sessions, a.WordDb)
+		a.Sessions.UpdateOrSet(s)
+
+		minutes, err := strconv.Atoi(r.FormValue("time-limit"))
+		timeLimit := time.Duration(minutes) * time.Minute
+		if err != nil || !slices.Contains(models.RoomTimeLimits, timeLimit) {
+			writeErrorMessage(w, a.NewNotifier(), s.Language(), "msg.room.timeLimit")
+			return
+		}
+
+		rm, err := a.Rooms.Create(s.ID(), s.Language(), a.WordDb, timeLimit)
+		if err != nil {
+			log.Printf("error creating room: %s", err)
+			writeErrorMessage(w, a.NewNotifier(), s.Language(), "msg.room.failed")
+			return
+		}
+
+		a.renderRoom(w, "room", rm, s.Language(), s.KeyboardLayout(), s.ID())
+	}
+}
+
+func (a *App) PostRoomJoin() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s := session.HandleSession(w, r, a.Sessions, a.WordDb)
+		a.Sessions.UpdateOrSet(s)
+
+		rm, err := a.Rooms.Join(r.FormValue("code"), s.ID())
+		if err != nil {
+			writeErrorMessage(w, a.NewNotifier(), s.Language(), roomMessageKey(err))
+			return
+		}
+
+		a.renderRoom(w, "room", rm, s.Language(), s.KeyboardLayout(), s.ID())
+	}
+}
+
+// GetRoom shows the room to everyone, visitors only get a session once they
+// join it.
+func (a *App) GetRoom() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s := session.TransientSession(r)
+		if !bot.IsBot(r.UserAgent()) {
+			s, _ = session.PeekSession(w, r, a.Sessions)
+		}
+
+		rm, err := a.Rooms.Get(r.PathValue("code"))
+		if err != nil {
+			writeErrorMessage(w, a.NewNotifier(), s.Language(), roomMessageKey(err))
+			return
+		}
+
+		a.renderRoom(w, "room", rm, s.Language(), s.KeyboardLayout(), s.ID())
+	}
+}
+
+func (a *App) PostRoomStart() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s := session.HandleSession(w, r, a.Sessions, a.WordDb)
+		a.Sessions.UpdateOrSet(s)
+
+		rm, err := a.Rooms.Start(r.PathValue("code"), s.ID())
+		if err != nil {
+			writeErrorMessage(w, a.NewNotifier(), s.Language(), roomMessageKey(err))
+			return
+		}
+
+		a.renderRoom(w, "room-board", rm, s.Language(), s.KeyboardLayout(), s.ID())
+	}
+}
+
+func (a *App) PostRoomGuess() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s := session.HandleSession(w, r, a.Sessions, a.WordDb)
+		a.Sessions.UpdateOrSet(s)
+		code := r.PathValue("code")
+
+		rm, err := a.Rooms.Get(code)
+		if err != nil {
+			writeErrorMessage(w, a.NewNotifier(), s.Language(), roomMessageKey(err))
+			return
+		}
+
+		err = r.ParseForm()
+		if err != nil {
+			writeErrorMessage(w, a.NewNotifier(), s.Language(), "msg.formParseFailed")
+			return
+		}
+
+		guessedWord, err := puzzle.SliceToWord(r.PostForm["guess"])
+		if err != nil {
+			writeErrorMessage(w, a.NewNotifier(), s.Language(), "msg.formParseFailed")
+			return
+		}
+
+		if !a.WordDb.Exists(rm.Language, guessedWord) {
+			writeErrorMessage(w, a.NewNotifier(), s.Language(), "msg.notInWordList")
+			return
+		}
+
+		rm, err = a.Rooms.Guess(code, s.ID(), guessedWord)
+		if err != nil {
+			writeErrorMessage(w, a.NewNotifier(), s.Language(), roomMessageKey(err))
+			return
+		}
+
+		a.renderRoom(w, "room-board", rm, s.Language(), s.KeyboardLayout(), s.ID())
+	}
+}
+
+// GetRoomEvents streams the letter free progress of all players as
+// server-sent events. The "progress" event carries the progress board, the
+// "board" event the own board of the player whenever the room changes state.
+func (a *App) GetRoomEvents() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s := session.TransientSession(r)
+		if !bot.IsBot(r.UserAgent()) {
+			s, _ = session.PeekSession(w, r, a.Sessions)
+		}
+		code := r.PathValue("code")
+
+		updates, unsubscribe, err := a.Rooms.Subscribe(code)
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		defer unsubscribe()
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		lastState := room.State("")
+		for {
+			rm, err := a.Rooms.Get(code)
+			if err != nil {
+				return
+			}
+
+			td := models.TemplateDataRoom{}.New(s.Language(), s.KeyboardLayout(), rm, s.ID())
+			if rm.State != lastState && lastState != "" {
+				err = writeEvent(w, "board", "room-board", td)
+				if err != nil {
+					log.Printf("error writing 'board' event: %s", err)
+					return
+				}
+			}
+			lastState = rm.State
+
+			err = writeEvent(w, "progress", "room-progress", td)
+			if err != nil {
+				log.Printf("error writing 'progress' event: %s", err)
+				return
+			}
+			flusher.Flush()
+
+			select {
+			case <-r.Context().Done():
+				return
+			case <-updates:
+			}
+		}
+	}
+}
+
+func (a *App) renderRoom(w http.ResponseWriter, templateName string, rm room.Room, l language.Language, keyboardLayout string, sessionID string) {
+	td := models.TemplateDataRoom{}.New(l, keyboardLayout, rm, sessionID)
+
+	err := templates.Routes.ExecuteTemplate(w, templateName, td)
+	if err != nil {
+		log.Printf("error t.ExecuteTemplate '%s': %s", templateName, err)
+	}
+}
+
+// writeEvent renders the template as server-sent event, every line of the
+// output becomes its own data field.
+func writeEvent(w http.ResponseWriter, event string, templateName string, data any) error {
+	buf := bytes.Buffer{}
+	err := templates.Routes.ExecuteTemplate(&buf, templateName, data)
+	if err != nil {
+		return err
+	}
+
+	out := strings.Builder{}
+	out.WriteString(fmt.Sprintf("event: %s\n", event))
+	for _, line := range strings.Split(buf.String(), "\n") {
+		out.WriteString(fmt.Sprintf("data: %s\n", line))
+	}
+	out.WriteString("\n")
+
+	_, err = w.Write([]byte(out.String()))
+	return err
+}
+
+func roomMessageKey(err error) string {
+	switch {
+	case errors.Is(err, room.ErrRoomNotFound):
+		return "msg.room.notFound"
+	case errors.Is(err, room.ErrRoomFull):
+		return "msg.room.full"
+	case errors.Is(err, room.ErrAlreadyStarted):
+		return "msg.room.alreadyStarted"
+	case errors.Is(err, room.ErrNotRunning):
+		return "msg.room.notRunning"
+	case errors.Is(err, room.ErrNotHost):
+		return "msg.room.notHost"
+	case errors.Is(err, room.ErrNotAPlayer):
+		return "msg.room.notAPlayer"
+	case errors.Is(err, room.ErrPlayerFinished):
+		return "msg.room.finished"
+	default:
+		return "msg.formParseFailed"
+	}
+}
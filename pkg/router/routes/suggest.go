@@ -6,22 +6,24 @@ import (
 	"net/http"
 
 	"github.com/pandorasNox/lettr/pkg/github"
+	"github.com/pandorasNox/lettr/pkg/language"
 	"github.com/pandorasNox/lettr/pkg/notification"
-	"github.com/pandorasNox/lettr/pkg/puzzle"
 	"github.com/pandorasNox/lettr/pkg/router/routes/models"
 	"github.com/pandorasNox/lettr/pkg/router/routes/templates"
-	"github.com/pandorasNox/lettr/pkg/server"
 	"github.com/pandorasNox/lettr/pkg/session"
 )
 
-func GetSuggest(sessions *session.Sessions, wdb puzzle.WordDatabase) http.HandlerFunc {
+func (a *App) GetSuggest() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		s := session.HandleSession(w, r, sessions, wdb)
+		s := session.HandleSession(w, r, a.Sessions, a.WordDb)
 		s.NewSecurityHoneypotMessageInputName()
-		sessions.UpdateOrSet(s)
+		a.Sessions.UpdateOrSet(s)
 
 		err := templates.Routes.ExecuteTemplate(w, "suggest", models.TemplateDataSuggest{
 			SecurityHoneypotMessageInputName: s.SecurityHoneypotMessageInputName(),
+			Language:                         string(s.Language()),
+			UiLanguage:                       s.Language(),
+			Languages:                        language.DefaultRegistry.Definitions(),
 		})
 		if err != nil {
 			log.Printf("error t.ExecuteTemplate '/suggest' route: %s", err)
@@ -29,18 +31,18 @@ func GetSuggest(sessions *session.Sessions, wdb puzzle.WordDatabase) http.Handle
 	}
 }
 
-func PostSuggest(githubToken string, sessions *session.Sessions, wdb puzzle.WordDatabase, server *server.Server) http.HandlerFunc {
+func (a *App) PostSuggest() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		notifier := notification.NewNotifier()
-		s := session.HandleSession(w, r, sessions, wdb)
+		notifier := a.NewNotifier()
+		s := session.HandleSession(w, r, a.Sessions, a.WordDb)
 
 		err := r.ParseForm()
 		if err != nil {
 			log.Printf("error: %s", err)
 
 			w.WriteHeader(422)
-			notifier.AddError("can not parse form data")
-			err = templates.Routes.ExecuteTemplate(w, "oob-messages", notifier.ToTemplate())
+			notifier.AddError("msg.formParseFailed")
+			err = templates.Routes.ExecuteTemplate(w, "oob-messages", notifier.ToTemplate(s.Language()))
 			if err != nil {
 				log.Printf("error t.ExecuteTemplate 'oob-messages': %s", err)
 			}
@@ -58,18 +60,18 @@ func PostSuggest(githubToken string, sessions *session.Sessions, wdb puzzle.Word
 
 		isHoneypotFilled := form.Get("message") != ""
 		if isHoneypotFilled {
-			server.Metrics().IncreaseHoneyTrapped()
-			createSuccessResponse(w, &notifier)
+			a.Server.Metrics().IncreaseHoneyTrapped()
+			createSuccessResponse(w, &notifier, s.Language())
 			return
 		}
 
 		err = tds.Validate()
 		if err != nil {
-			w.WriteHeader(422)
 			w.Header().Add("HX-Reswap", "none")
+			w.WriteHeader(422)
 
-			notifier.AddError(err.Error())
-			err = templates.Routes.ExecuteTemplate(w, "oob-messages", notifier.ToTemplate())
+			notifier.AddError(validationMessageKey(err))
+			err = templates.Routes.ExecuteTemplate(w, "oob-messages", notifier.ToTemplate(s.Language()))
 			if err != nil {
 				log.Printf("error t.ExecuteTemplate 'oob-messages' route: %s", err)
 			}
@@ -78,14 +80,14 @@ func PostSuggest(githubToken string, sessions *session.Sessions, wdb puzzle.Word
 		}
 
 		err = github.CreateWordSuggestionIssue(
-			context.Background(), githubToken, tds.Word, tds.Language, tds.Action, tds.Message,
+			context.Background(), a.Config.GithubToken, tds.Word, tds.Language, tds.Action, tds.Message,
 		)
 		if err != nil {
-			w.WriteHeader(422)
 			w.Header().Add("HX-Reswap", "none")
+			w.WriteHeader(422)
 
-			notifier.AddError("Could not send suggestion.")
-			err = templates.Routes.ExecuteTemplate(w, "oob-messages", notifier.ToTemplate())
+			notifier.AddError("msg.suggestionFailed")
+			err = templates.Routes.ExecuteTemplate(w, "oob-messages", notifier.ToTemplate(s.Language()))
 			if err != nil {
 				log.Printf("error t.ExecuteTemplate 'oob-messages' route: %s", err)
 			}
@@ -93,18 +95,37 @@ func PostSuggest(githubToken string, sessions *session.Sessions, wdb puzzle.Word
 			return
 		}
 
-		createSuccessResponse(w, &notifier)
+		createSuccessResponse(w, &notifier, s.Language())
+	}
+}
+
+func validationMessageKey(err error) string {
+	switch err {
+	case models.ErrFailedWordValidation:
+		return "msg.validation.word"
+	case models.ErrFailedMessageValidation:
+		return "msg.validation.message"
+	case models.ErrFailedActionValidation:
+		return "msg.validation.action"
+	case models.ErrFailedLanguageValidation:
+		return "msg.validation.language"
+	default:
+		return err.Error()
 	}
 }
 
-func createSuccessResponse(w http.ResponseWriter, n *notification.Notifier) {
-	n.AddSuccess("Suggestion send, thank you!")
-	err := templates.Routes.ExecuteTemplate(w, "oob-messages", n.ToTemplate())
+func createSuccessResponse(w http.ResponseWriter, n *notification.Notifier, l language.Language) {
+	n.AddSuccess("msg.suggestionSent")
+	err := templates.Routes.ExecuteTemplate(w, "oob-messages", n.ToTemplate(l))
 	if err != nil {
 		log.Printf("error t.ExecuteTemplate 'oob-messages' route: %s", err)
 	}
 
-	err = templates.Routes.ExecuteTemplate(w, "suggest", models.TemplateDataSuggest{})
+	err = templates.Routes.ExecuteTemplate(w, "suggest", models.TemplateDataSuggest{
+		Language:   string(l),
+		UiLanguage: l,
+		Languages:  language.DefaultRegistry.Definitions(),
+	})
 	if err != nil {
 		log.Printf("error t.ExecuteTemplate '/suggest' route: %s", err)
 	}
@@ -7,8 +7,11 @@ import (
 	"testing"
 	"testing/fstest"
 
+	"github.com/pandorasNox/lettr/pkg/clock"
 	"github.com/pandorasNox/lettr/pkg/language"
 	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/random"
+	"github.com/pandorasNox/lettr/pkg/server"
 	"github.com/pandorasNox/lettr/pkg/session"
 )
 
@@ -45,9 +48,10 @@ cried
 		log.Fatalf("init wordDatabase failed: %s", err)
 	}
 
-	sessions := session.NewSessions()
+	sessions := session.NewSessions(clock.Real{}, random.New(1))
+	app := NewApp(&sessions, wordDb, Config{}, &server.Server{})
 
-	getSuggestHandler := GetSuggest(&sessions, wordDb)
+	getSuggestHandler := app.GetSuggest()
 
 	req := httptest.NewRequest(http.MethodGet, "/lettr", nil)
 	recorder := httptest.NewRecorder()
@@ -57,9 +61,9 @@ cried
 	// extract cookie
 	cookie := recorder.Result().Cookies()[0]
 
-	sess, err := sessions.GetById(cookie.Value)
+	sess, err := sessions.GetByCookie(cookie.Value)
 	if err != nil {
-		t.Errorf("couldn't get session by id='%s', error: %s", cookie.Value, err)
+		t.Errorf("couldn't get session by cookie='%s', error: %s", cookie.Value, err)
 	}
 
 	if sess.SecurityHoneypotMessageInputName() == "" {
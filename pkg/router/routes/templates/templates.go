@@ -2,8 +2,12 @@ package templates
 
 import (
 	"embed"
+	"fmt"
 	"html/template"
+	"time"
 
+	"github.com/pandorasNox/lettr/pkg/i18n"
+	"github.com/pandorasNox/lettr/pkg/language"
 	"github.com/pandorasNox/lettr/pkg/puzzle"
 )
 
@@ -16,6 +20,9 @@ var funcMap = template.FuncMap{
 	"IsMatchVague": puzzle.MatchVague.Is,
 	"IsMatchNone":  puzzle.MatchNone.Is,
 	"IsMatchExact": puzzle.MatchExact.Is,
+	"T":            i18n.T,
+	"LanguageName": language.DefaultRegistry.DisplayName,
+	"MinSec":       minSec,
 }
 
 // routesTemplate := template.Must(template.ParseFS(fs, "routesTemplates/index.html.tmpl", "routesTemplates/lettr-form.html.tmpl"))
@@ -26,5 +33,16 @@ var Routes = template.Must(template.New("index.html.tmpl").Funcs(funcMap).ParseF
 	"lettr-form.html.tmpl",
 	"help.html.tmpl",
 	"suggest.html.tmpl",
+	"room.html.tmpl",
+	"challenge.html.tmpl",
+	"leaderboard.html.tmpl",
+	"account.html.tmpl",
+	"history.html.tmpl",
 	"pages/test.html.tmpl",
 ))
+
+// minSec formats d the way the speed timer shows it, e.g. "2:05".
+func minSec(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
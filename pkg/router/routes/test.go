@@ -6,7 +6,6 @@ import (
 	"net/http"
 
 	"github.com/pandorasNox/lettr/pkg/router/routes/templates"
-	"github.com/pandorasNox/lettr/pkg/server"
 )
 
 type TemplateDataTestPage struct{}
@@ -22,11 +21,11 @@ func GetTestPage() http.HandlerFunc {
 	}
 }
 
-func PostIncrementHoneyTrapped(s *server.Server) http.HandlerFunc {
+func (a *App) PostIncrementHoneyTrapped() http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		before := s.Metrics().HoneyTrapped()
-		s.Metrics().IncreaseHoneyTrapped()
-		after := s.Metrics().HoneyTrapped()
+		before := a.Server.Metrics().HoneyTrapped()
+		a.Server.Metrics().IncreaseHoneyTrapped()
+		after := a.Server.Metrics().HoneyTrapped()
 
 		fmt.Fprintf(w, "before='%d'\nafter='%d'\ndone\n", before, after)
 	}
@@ -0,0 +1,110 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	COOKIE_PROFILE_PROD = "prod"
+	// COOKIE_PROFILE_DEV works on plain http://localhost, where browsers
+	// drop secure cookies
+	COOKIE_PROFILE_DEV = "dev"
+)
+
+// CookiePolicy decides the attributes of the session cookie.
+type CookiePolicy struct {
+	Name string
+	// Domain is empty for a cookie sent to the serving host only
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+	// MaxAge is the lifetime of cookie and session, extended on every
+	// request
+	MaxAge time.Duration
+}
+
+func DefaultCookiePolicy() CookiePolicy {
+	return CookiePolicy{
+		Name:     SESSION_COOKIE_NAME,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   SESSION_MAX_AGE_IN_SECONDS * time.Second,
+	}
+}
+
+func DevCookiePolicy() CookiePolicy {
+	cp := DefaultCookiePolicy()
+	cp.Secure = false
+	cp.SameSite = http.SameSiteLaxMode
+
+	return cp
+}
+
+// CookiePolicyByProfile returns the policy of a COOKIE_PROFILE_ constant.
+func CookiePolicyByProfile(profile string) (CookiePolicy, error) {
+	switch profile {
+	case COOKIE_PROFILE_PROD, "":
+		return DefaultCookiePolicy(), nil
+	case COOKIE_PROFILE_DEV:
+		return DevCookiePolicy(), nil
+	}
+
+	return CookiePolicy{}, fmt.Errorf("unknown cookie profile '%s'", profile)
+}
+
+// ParseSameSite parses the values of the SameSite cookie attribute.
+func ParseSameSite(v string) (http.SameSite, error) {
+	switch strings.ToLower(v) {
+	case "strict":
+		return http.SameSiteStrictMode, nil
+	case "lax":
+		return http.SameSiteLaxMode, nil
+	case "none":
+		return http.SameSiteNoneMode, nil
+	}
+
+	return 0, fmt.Errorf("unknown SameSite value '%s'", v)
+}
+
+// Validate rejects policies browsers would refuse or silently ignore.
+func (cp CookiePolicy) Validate() error {
+	if cp.Name == "" || strings.ContainsAny(cp.Name, "()<>@,;:\\\"/[]?={} \t") {
+		return fmt.Errorf("invalid cookie name '%s'", cp.Name)
+	}
+
+	// longer names would push sealed sessions past the size browsers accept
+	if len(cp.Name) > COOKIE_NAME_MAX_SIZE {
+		return fmt.Errorf("cookie name '%s' is longer than %d bytes", cp.Name, COOKIE_NAME_MAX_SIZE)
+	}
+
+	if cp.MaxAge < time.Second {
+		return errors.New("cookie max age must be at least one second")
+	}
+
+	if cp.SameSite == http.SameSiteNoneMode && !cp.Secure {
+		return errors.New("cookies with SameSite=None must be secure")
+	}
+
+	return nil
+}
+
+func (cp CookiePolicy) maxAgeSeconds() int {
+	return int(cp.MaxAge / time.Second)
+}
+
+func (cp CookiePolicy) cookie(value string) http.Cookie {
+	return http.Cookie{
+		Name:     cp.Name,
+		Value:    value,
+		Path:     "/",
+		Domain:   cp.Domain,
+		MaxAge:   cp.maxAgeSeconds(),
+		HttpOnly: true,
+		Secure:   cp.Secure,
+		SameSite: cp.SameSite,
+	}
+}
@@ -0,0 +1,182 @@
+package session
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// COOKIE_MAX_SIZE is the size of name and value browsers accept at least.
+const COOKIE_MAX_SIZE = 4096
+
+// COOKIE_NAME_MAX_SIZE is the longest cookie name CookiePolicy.Validate
+// accepts.
+const COOKIE_NAME_MAX_SIZE = 32
+
+// COOKIE_VALUE_MAX_SIZE leaves room in COOKIE_MAX_SIZE for a cookie name of
+// up to COOKIE_NAME_MAX_SIZE bytes and the signature of the value.
+const COOKIE_VALUE_MAX_SIZE = COOKIE_MAX_SIZE - COOKIE_NAME_MAX_SIZE - len("=") - len(".") - 43
+
+var ErrCookieTooLarge = errors.New("session does not fit into a cookie")
+
+// CookieStore keeps the whole session in the cookie, sealed with AES-GCM, so
+// the server holds no state at all. Sessions too large for a cookie lose
+// their oldest history and past words first.
+type CookieStore struct {
+	// aeads holds one cipher per secret, the first one seals
+	aeads []cipher.AEAD
+	// name is the name of the cookie policy of the Sessions using the
+	// store, SESSION_COOKIE_NAME if empty
+	name string
+}
+
+var _ Store = (*CookieStore)(nil)
+
+// NewCookieStore returns a CookieStore sealing with a key derived from the
+// first secret. Cookies sealed with any of the other secrets are still
+// accepted and sealed with the first one on their next save, so secrets can
+// be rotated without logging out every player.
+func NewCookieStore(secrets ...string) (*CookieStore, error) {
+	if len(secrets) == 0 {
+		return nil, errors.New("cookie store: no secret")
+	}
+
+	cs := &CookieStore{}
+	for _, secret := range secrets {
+		if secret == "" {
+			return nil, errors.New("cookie store: empty secret")
+		}
+
+		key := sha256.Sum256([]byte(secret))
+
+		block, err := aes.NewCipher(key[:])
+		if err != nil {
+			return nil, fmt.Errorf("cookie store: creating cipher failed: %s", err)
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("cookie store: creating gcm failed: %s", err)
+		}
+
+		cs.aeads = append(cs.aeads, aead)
+	}
+
+	return cs, nil
+}
+
+// MustNewCookieStore is like NewCookieStore but panics on error.
+func MustNewCookieStore(secrets ...string) *CookieStore {
+	cs, err := NewCookieStore(secrets...)
+	if err != nil {
+		panic(err)
+	}
+
+	return cs
+}
+
+func (cs *CookieStore) Load(value string) (session, bool) {
+	p, err := cs.open(value)
+	if err != nil {
+		return session{}, false
+	}
+
+	return p.session(), true
+}
+
+// Save returns s sealed as cookie value, sessions saved outside of a
+// request are lost.
+func (cs *CookieStore) Save(s *session) (string, error) {
+	p := newStoredSession(*s)
+
+	for {
+		value, err := cs.seal(p)
+		if err != nil {
+			return "", err
+		}
+
+		if len(value) <= COOKIE_VALUE_MAX_SIZE {
+			return value, nil
+		}
+
+		// the history is the largest part and the least important one
+		switch {
+		case len(p.History) > 0:
+			p.History = p.History[1:]
+		case len(p.PastWords) > 0:
+			p.PastWords = p.PastWords[1:]
+		default:
+			return "", ErrCookieTooLarge
+		}
+	}
+}
+
+// Remove does nothing, tokens of sessions in cookies refer to nothing.
+func (cs *CookieStore) Remove(token string) {}
+
+// RemoveExpired does nothing, expired cookies are dropped by the browser
+// and rejected by HandleSession.
+func (cs *CookieStore) RemoveExpired(now time.Time) {}
+
+func (cs *CookieStore) cookieName() string {
+	if cs.name == "" {
+		return SESSION_COOKIE_NAME
+	}
+
+	return cs.name
+}
+
+func (cs *CookieStore) seal(p storedSession) (string, error) {
+	plain, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("cookie store: marshal session failed: %s", err)
+	}
+
+	aead := cs.aeads[0]
+	nonce := make([]byte, aead.NonceSize())
+	_, err = crand.Read(nonce)
+	if err != nil {
+		return "", fmt.Errorf("cookie store: creating nonce failed: %s", err)
+	}
+
+	// the cookie name is authenticated as well, so values can't be moved
+	// between cookies sealed with the same secret
+	sealed := aead.Seal(nonce, nonce, plain, []byte(cs.cookieName()))
+
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+func (cs *CookieStore) open(value string) (storedSession, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return storedSession{}, fmt.Errorf("cookie store: decoding cookie failed: %s", err)
+	}
+
+	for _, aead := range cs.aeads {
+		if len(sealed) < aead.NonceSize() {
+			break
+		}
+
+		nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+		plain, err := aead.Open(nil, nonce, ciphertext, []byte(cs.cookieName()))
+		if err != nil {
+			continue
+		}
+
+		p := storedSession{}
+		err = json.Unmarshal(plain, &p)
+		if err != nil {
+			return storedSession{}, fmt.Errorf("cookie store: unmarshal session failed: %s", err)
+		}
+
+		return p, nil
+	}
+
+	return storedSession{}, errors.New("cookie store: cookie not sealed by any secret")
+}
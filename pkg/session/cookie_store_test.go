@@ -0,0 +1,154 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/clock"
+	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/random"
+)
+
+func TestCookieStore_HandleSession(t *testing.T) {
+	fakeClock := clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	wdb := puzzle.WordDatabase{}
+
+	cookieStoreSessions := func(secrets ...string) *Sessions {
+		ss := NewSessions(fakeClock, random.New(1))
+		ss.SetStore(MustNewCookieStore(secrets...))
+		if err := ss.SetTokenSecrets(secrets...); err != nil {
+			t.Fatal(err)
+		}
+		return &ss
+	}
+
+	requestWithCookieFrom := func(res *httptest.ResponseRecorder) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		for _, c := range res.Result().Cookies() {
+			req.AddCookie(c)
+		}
+		return req
+	}
+
+	old := cookieStoreSessions("old")
+	first := httptest.NewRecorder()
+	sess := HandleSession(first, httptest.NewRequest(http.MethodGet, "/", nil), old, wdb)
+	solution := puzzle.Word{'c', 'r', 'i', 'e', 'd'}
+	sess.SetGameState(puzzle.NewChallengeGame("", solution))
+	_ = sess.GameState().Guess(puzzle.EvaluateGuessedWord(puzzle.Word{'t', 'r', 'i', 'e', 'd'}, solution), fakeClock.Now())
+	old.UpdateOrSet(sess)
+
+	if n := len(first.Result().Cookies()); n != 1 {
+		t.Fatalf("expected exactly one session cookie, got %d", n)
+	}
+	if len(old.sessions) != 0 {
+		t.Errorf("cookie store must not keep sessions in memory, got %d", len(old.sessions))
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		got := HandleSession(httptest.NewRecorder(), requestWithCookieFrom(first), old, wdb)
+		if got.id != sess.id || got.gameState.LastEvaluatedAttempt() != sess.gameState.LastEvaluatedAttempt() {
+			t.Errorf("session not restored from cookie, got id='%s', want id='%s'", got.id, sess.id)
+		}
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		rotated := cookieStoreSessions("new", "old")
+		second := httptest.NewRecorder()
+		got := HandleSession(second, requestWithCookieFrom(first), rotated, wdb)
+		if got.id != sess.id {
+			t.Fatalf("cookie sealed with the old secret rejected after rotation")
+		}
+
+		got = HandleSession(httptest.NewRecorder(), requestWithCookieFrom(second), cookieStoreSessions("new"), wdb)
+		if got.id != sess.id {
+			t.Errorf("cookie not sealed with the new secret after rotation")
+		}
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		got := HandleSession(httptest.NewRecorder(), requestWithCookieFrom(first), cookieStoreSessions("other"), wdb)
+		if got.id == sess.id {
+			t.Errorf("cookie accepted with a wrong secret")
+		}
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		c := first.Result().Cookies()[0]
+		b := []byte(c.Value)
+		b[len(b)/2] ^= 1
+		c.Value = string(b)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(c)
+		got := HandleSession(httptest.NewRecorder(), req, old, wdb)
+		if got.id == sess.id {
+			t.Errorf("tampered cookie accepted")
+		}
+	})
+}
+
+func TestCookieStore_Save_tooLarge(t *testing.T) {
+	cs := MustNewCookieStore("secret")
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	solution := puzzle.Word{'c', 'r', 'i', 'e', 'd'}
+
+	s := session{id: "foo", expiresAt: now.Add(time.Hour), maxAgeSeconds: SESSION_MAX_AGE_IN_SECONDS, language: language.LANG_EN}
+	for i := range HISTORY_MAX_GAMES {
+		g := puzzle.NewChallengeGame("", solution)
+		for range 5 {
+			_ = g.Guess(puzzle.EvaluateGuessedWord(puzzle.Word{'t', 'r', 'i', 'e', 'd'}, solution), now)
+		}
+		s.AddToHistory(g.Record(language.LANG_EN, now.Add(time.Duration(i)*time.Minute)))
+	}
+
+	value, err := cs.Save(&s)
+	if err != nil {
+		t.Fatalf("Save() error = %s", err)
+	}
+
+	if size := len(value); size > COOKIE_VALUE_MAX_SIZE {
+		t.Fatalf("cookie value size = %d, want at most %d", size, COOKIE_VALUE_MAX_SIZE)
+	}
+
+	got, ok := cs.Load(value)
+	if !ok {
+		t.Fatalf("Load() of a trimmed session failed")
+	}
+
+	h := got.History()
+	if len(h) == 0 || len(h) >= HISTORY_MAX_GAMES {
+		t.Fatalf("history of %d games not trimmed, got %d", HISTORY_MAX_GAMES, len(h))
+	}
+	if last := s.History()[HISTORY_MAX_GAMES-1]; !h[len(h)-1].FinishedAt.Equal(last.FinishedAt) {
+		t.Errorf("trimming must drop the oldest games, newest kept finished at %s, want %s", h[len(h)-1].FinishedAt, last.FinishedAt)
+	}
+}
+
+func TestCookieStore_cookieName(t *testing.T) {
+	sealing := NewSessions(clock.Real{}, random.New(1))
+	sealing.SetStore(MustNewCookieStore("secret"))
+	policy := DefaultCookiePolicy()
+	policy.Name = "lettr_other"
+	if err := sealing.SetCookiePolicy(policy); err != nil {
+		t.Fatal(err)
+	}
+
+	s := session{id: "foo", maxAgeSeconds: SESSION_MAX_AGE_IN_SECONDS, language: language.LANG_EN}
+	value, err := sealing.store.Save(&s)
+	if err != nil {
+		t.Fatalf("Save() error = %s", err)
+	}
+
+	if _, ok := sealing.store.Load(value); !ok {
+		t.Errorf("Load() of a value sealed for the same cookie name failed")
+	}
+
+	// same secret, default cookie name
+	if _, ok := MustNewCookieStore("secret").Load(value); ok {
+		t.Errorf("Load() accepted a value sealed for another cookie name")
+	}
+}
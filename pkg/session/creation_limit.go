@@ -0,0 +1,99 @@
+package session
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// SESSION_CREATION_LIMIT sessions may be started per client address
+	// within SESSION_CREATION_WINDOW, enough for a household behind one
+	// address but not for scripts flooding the memory with sessions.
+	SESSION_CREATION_LIMIT  = 30
+	SESSION_CREATION_WINDOW = time.Hour
+)
+
+var ErrTooManySessions = errors.New("too many sessions started from this address")
+
+// creationLimit counts the sessions started per client address.
+type creationLimit struct {
+	mutex     sync.Mutex
+	max       int
+	window    time.Duration
+	startedAt map[string][]time.Time
+	sweptAt   time.Time
+}
+
+func newCreationLimit(max int, window time.Duration) *creationLimit {
+	return &creationLimit{max: max, window: window, startedAt: map[string][]time.Time{}}
+}
+
+// allow records a session started by addr at now, unless addr reached the
+// limit already. IPv6 addresses count for their whole /64 network.
+func (cl *creationLimit) allow(addr string, now time.Time) bool {
+	addr = addrGroup(addr)
+
+	cl.mutex.Lock()
+	defer cl.mutex.Unlock()
+
+	// addresses not seen for a whole window are forgotten
+	if now.Sub(cl.sweptAt) >= cl.window {
+		for a, times := range cl.startedAt {
+			if now.Sub(times[len(times)-1]) >= cl.window {
+				delete(cl.startedAt, a)
+			}
+		}
+		cl.sweptAt = now
+	}
+
+	recent := []time.Time{}
+	for _, t := range cl.startedAt[addr] {
+		if now.Sub(t) < cl.window {
+			recent = append(recent, t)
+		}
+	}
+
+	if len(recent) >= cl.max {
+		cl.startedAt[addr] = recent
+		return false
+	}
+
+	cl.startedAt[addr] = append(recent, now)
+
+	return true
+}
+
+// clientAddr is the address the request came from. Behind a proxy it is
+// the value of trustedHeader, e.g. "Fly-Client-IP" on fly.io, the header is
+// only to be trusted if the proxy overwrites values sent by clients. An
+// empty trustedHeader or a missing header falls back to the remote address.
+func clientAddr(r *http.Request, trustedHeader string) string {
+	if trustedHeader != "" {
+		if ip := r.Header.Get(trustedHeader); ip != "" {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// addrGroup is the network sessions of addr are counted for. A single IPv6
+// client usually gets a whole /64 network, so it could switch addresses for
+// every session otherwise.
+func addrGroup(addr string) string {
+	ip := net.ParseIP(addr)
+	if ip == nil || ip.To4() != nil {
+		return addr
+	}
+
+	network := net.IPNet{IP: ip.Mask(net.CIDRMask(64, 128)), Mask: net.CIDRMask(64, 128)}
+	return network.String()
+}
@@ -0,0 +1,72 @@
+package session
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCreationLimit_allow(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	cl := newCreationLimit(2, time.Hour)
+
+	if !cl.allow("a", start) || !cl.allow("a", start.Add(time.Minute)) {
+		t.Fatalf("allow() denied sessions below the limit")
+	}
+	if cl.allow("a", start.Add(2*time.Minute)) {
+		t.Errorf("allow() granted a session above the limit")
+	}
+	if !cl.allow("b", start.Add(2*time.Minute)) {
+		t.Errorf("allow() denied another address")
+	}
+
+	// the window slides, the first session drops out after an hour
+	if !cl.allow("a", start.Add(time.Hour)) {
+		t.Errorf("allow() denied a session after the window passed")
+	}
+	if cl.allow("a", start.Add(time.Hour+time.Second)) {
+		t.Errorf("allow() granted a session above the limit")
+	}
+
+	cl.allow("c", start.Add(3*time.Hour))
+	if len(cl.startedAt) != 1 {
+		t.Errorf("addresses not seen for a window must be forgotten, got %v", cl.startedAt)
+	}
+}
+
+func TestCreationLimit_allow_ipv6(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	cl := newCreationLimit(1, time.Hour)
+
+	if !cl.allow("2001:db8:1:2::1", start) {
+		t.Fatalf("allow() denied the first session")
+	}
+	if cl.allow("2001:db8:1:2:ffff::7", start) {
+		t.Errorf("allow() granted a session to another address of the same /64")
+	}
+	if !cl.allow("2001:db8:1:3::1", start) {
+		t.Errorf("allow() denied an address of another /64")
+	}
+	if !cl.allow("192.0.2.1", start) || !cl.allow("192.0.2.2", start) {
+		t.Errorf("allow() must count IPv4 addresses on their own")
+	}
+}
+
+func TestClientAddr(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "192.0.2.1:1234"
+	if got := clientAddr(r, ""); got != "192.0.2.1" {
+		t.Errorf("clientAddr() = %s, want the remote address", got)
+	}
+	if got := clientAddr(r, "Fly-Client-IP"); got != "192.0.2.1" {
+		t.Errorf("clientAddr() = %s, want the remote address without the header", got)
+	}
+
+	r.Header.Set("Fly-Client-IP", "198.51.100.7")
+	if got := clientAddr(r, ""); got != "192.0.2.1" {
+		t.Errorf("clientAddr() = %s, want the header ignored unless trusted", got)
+	}
+	if got := clientAddr(r, "Fly-Client-IP"); got != "198.51.100.7" {
+		t.Errorf("clientAddr() = %s, want the address set by the proxy", got)
+	}
+}
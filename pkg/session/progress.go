@@ -0,0 +1,143 @@
+package session
+
+import (
+	"log"
+	"slices"
+
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+)
+
+// Progress is the part of a session that belongs to the player rather than
+// to the browser. Accounts carry it from one device to the next.
+type Progress struct {
+	PastWords    []puzzle.Word
+	SurvivalBest int
+	Nickname     string
+	Streak       int
+	SolvedDay    string
+	SolvedToday  int
+	History      []puzzle.GameRecord
+}
+
+// ProgressStore keeps the progress of sessions claimed by an account. A
+// missing account (e.g. deleted on another device) unlinks the session, a
+// failed load keeps the session as it is.
+type ProgressStore interface {
+	LoadProgress(accountID string) (Progress, bool, error)
+	SaveProgress(accountID string, p Progress)
+}
+
+// Merge combines the progress of two players, the values of p win where
+// they cannot be combined.
+func (p Progress) Merge(o Progress) Progress {
+	m := Progress{
+		PastWords:    slices.Clone(p.PastWords),
+		SurvivalBest: max(p.SurvivalBest, o.SurvivalBest),
+		Nickname:     p.Nickname,
+		Streak:       max(p.Streak, o.Streak),
+		SolvedDay:    p.SolvedDay,
+		SolvedToday:  p.SolvedToday,
+		History:      slices.Clone(p.History),
+	}
+
+	for _, w := range o.PastWords {
+		if !slices.Contains(m.PastWords, w) {
+			m.PastWords = append(m.PastWords, w)
+		}
+	}
+
+	for _, r := range o.History {
+		if !slices.ContainsFunc(m.History, r.IsSame) {
+			m.History = append(m.History, r)
+		}
+	}
+	slices.SortStableFunc(m.History, func(a, b puzzle.GameRecord) int {
+		return a.FinishedAt.Compare(b.FinishedAt)
+	})
+	m.History = trimHistory(m.History)
+
+	if m.Nickname == "" {
+		m.Nickname = o.Nickname
+	}
+
+	// days are formatted as time.DateOnly, so they compare like dates
+	if o.SolvedDay > m.SolvedDay {
+		m.SolvedDay, m.SolvedToday = o.SolvedDay, o.SolvedToday
+	} else if o.SolvedDay == m.SolvedDay {
+		m.SolvedToday += o.SolvedToday
+	}
+
+	return m
+}
+
+// AccountID is the account that claimed the session, empty for anonymous
+// players.
+func (s *session) AccountID() string {
+	return s.accountID
+}
+
+func (s *session) Progress() Progress {
+	return Progress{
+		PastWords:    s.PastWords(),
+		SurvivalBest: s.survivalBest,
+		Nickname:     s.nickname,
+		Streak:       s.streak,
+		SolvedDay:    s.solvedDay,
+		SolvedToday:  s.solvedToday,
+		History:      s.History(),
+	}
+}
+
+func (s *session) SetProgress(p Progress) {
+	s.pastWords = slices.Clone(p.PastWords)
+	s.survivalBest = p.SurvivalBest
+	s.nickname = p.Nickname
+	s.streak = p.Streak
+	s.solvedDay = p.SolvedDay
+	s.solvedToday = p.SolvedToday
+	s.history = slices.Clone(p.History)
+}
+
+// Claim links the session to an account and replaces its progress with the
+// one of the account. Callers merge both beforehand.
+func (s *session) Claim(accountID string, p Progress) {
+	s.accountID = accountID
+	s.SetProgress(p)
+}
+
+// Unclaim makes the session anonymous again, it keeps its progress.
+func (s *session) Unclaim() {
+	s.accountID = ""
+}
+
+// SetProgressStore makes the sessions load the progress of claimed sessions
+// on every request and save it on every update.
+func (ss *Sessions) SetProgressStore(ps ProgressStore) {
+	ss.progress = ps
+}
+
+func (ss *Sessions) loadProgress(s *session) {
+	if ss.progress == nil || s.accountID == "" {
+		return
+	}
+
+	p, ok, err := ss.progress.LoadProgress(s.accountID)
+	if err != nil {
+		log.Printf("loading progress of account '%s' failed: %s", s.accountID, err)
+		return
+	}
+	if !ok {
+		s.Unclaim()
+		return
+	}
+
+	s.SetProgress(p)
+}
+
+func (ss *Sessions) saveProgress(s session) {
+	if ss.progress == nil || s.accountID == "" {
+		return
+	}
+
+	ss.progress.SaveProgress(s.accountID, s.Progress())
+}
@@ -0,0 +1,57 @@
+package session
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+)
+
+func TestProgress_Merge(t *testing.T) {
+	cried := puzzle.Word{'c', 'r', 'i', 'e', 'd'}
+	tried := puzzle.Word{'t', 'r', 'i', 'e', 'd'}
+	fried := puzzle.Word{'f', 'r', 'i', 'e', 'd'}
+
+	tests := []struct {
+		name string
+		p    Progress
+		o    Progress
+		want Progress
+	}{
+		{
+			name: "words are combined, best values kept",
+			p:    Progress{PastWords: []puzzle.Word{cried, tried}, SurvivalBest: 50, Nickname: "acc", Streak: 1},
+			o:    Progress{PastWords: []puzzle.Word{tried, fried}, SurvivalBest: 80, Nickname: "anon", Streak: 3},
+			want: Progress{PastWords: []puzzle.Word{cried, tried, fried}, SurvivalBest: 80, Nickname: "acc", Streak: 3},
+		},
+		{
+			name: "nickname of the other if missing",
+			p:    Progress{},
+			o:    Progress{Nickname: "anon"},
+			want: Progress{Nickname: "anon"},
+		},
+		{
+			name: "solved today adds up on the same day",
+			p:    Progress{SolvedDay: "2024-06-01", SolvedToday: 2},
+			o:    Progress{SolvedDay: "2024-06-01", SolvedToday: 1},
+			want: Progress{SolvedDay: "2024-06-01", SolvedToday: 3},
+		},
+		{
+			name: "solved today of the later day",
+			p:    Progress{SolvedDay: "2024-05-31", SolvedToday: 4},
+			o:    Progress{SolvedDay: "2024-06-01", SolvedToday: 1},
+			want: Progress{SolvedDay: "2024-06-01", SolvedToday: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.p.Merge(tt.o)
+			if len(got.PastWords) == 0 {
+				got.PastWords = nil
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Merge() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
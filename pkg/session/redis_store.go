@@ -0,0 +1,155 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/redis"
+)
+
+const (
+	REDIS_KEY_PREFIX       = "lettr:session:"
+	REDIS_TOKEN_KEY_PREFIX = "lettr:session-token:"
+)
+
+var ErrSessionConflict = errors.New("session game changed by another request")
+
+// RedisStore keeps sessions on a Redis protocol server, so several instances
+// share them. Sessions are stored under their id, so a rotated token still
+// refers to the same game, and every token maps to the id. Both keys expire
+// with the session, i.e. the max age of the cookie policy after their last
+// save.
+type RedisStore struct {
+	client *redis.Client
+}
+
+var _ Store = (*RedisStore)(nil)
+
+func NewRedisStore(c *redis.Client) *RedisStore {
+	return &RedisStore{client: c}
+}
+
+// loadScript returns the game digest and data of the session the token
+// maps to.
+//
+// KEYS[1] token key
+// ARGV[1] session key prefix
+var loadScript = redis.NewScript(`
+local id = redis.call('GET', KEYS[1])
+if not id then
+	return false
+end
+return redis.call('HMGET', ARGV[1] .. id, 'game', 'data')
+`)
+
+// saveScript stores a session unless its game was changed by another
+// request since it got loaded. Requests of the same player hitting
+// different instances, e.g. a double submitted guess or two tabs rotating
+// the token at once, would otherwise overwrite each other's rows.
+//
+// KEYS[1] session key, KEYS[2] token key
+// ARGV[1] digest of the game as loaded, ARGV[2] digest of the game to save
+// ARGV[3] session data, ARGV[4] ttl in seconds, ARGV[5] session id
+var saveScript = redis.NewScript(`
+local stored = redis.call('HGET', KEYS[1], 'game')
+if stored and stored ~= ARGV[1] and stored ~= ARGV[2] then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'game', ARGV[2], 'data', ARGV[3])
+redis.call('EXPIRE', KEYS[1], ARGV[4])
+redis.call('SET', KEYS[2], ARGV[5], 'EX', ARGV[4])
+return 1
+`)
+
+func (rs *RedisStore) Load(token string) (session, bool) {
+	reply, err := loadScript.Run(rs.client, []string{REDIS_TOKEN_KEY_PREFIX + token}, REDIS_KEY_PREFIX)
+	if err != nil {
+		return session{}, false
+	}
+
+	fields, ok := reply.([]any)
+	if !ok || len(fields) != 2 {
+		return session{}, false
+	}
+
+	game, okGame := fields[0].(string)
+	data, okData := fields[1].(string)
+	if !okGame || !okData {
+		return session{}, false
+	}
+
+	p := storedSession{}
+	err = json.Unmarshal([]byte(data), &p)
+	if err != nil {
+		return session{}, false
+	}
+
+	s := p.session()
+	s.storedGame = game
+	// another request may have rotated the token stored along with the
+	// data already, this request keeps its own until it rotates
+	s.token = token
+
+	return s, true
+}
+
+// Save stores s under its id and maps its token to it, it fails with
+// ErrSessionConflict if another request changed the game in the meantime.
+func (rs *RedisStore) Save(s *session) (string, error) {
+	data, err := json.Marshal(newStoredSession(*s))
+	if err != nil {
+		return "", fmt.Errorf("redis store: marshal session failed: %s", err)
+	}
+
+	game, err := gameDigest(s.gameState)
+	if err != nil {
+		return "", err
+	}
+
+	reply, err := saveScript.Run(
+		rs.client,
+		[]string{REDIS_KEY_PREFIX + s.id, REDIS_TOKEN_KEY_PREFIX + s.token},
+		s.storedGame, game, string(data), strconv.Itoa(s.maxAgeSeconds), s.id,
+	)
+	if err != nil {
+		return "", fmt.Errorf("redis store: saving session failed: %s", err)
+	}
+
+	if saved, _ := reply.(int64); saved != 1 {
+		return "", ErrSessionConflict
+	}
+
+	s.storedGame = game
+
+	return s.token, nil
+}
+
+// Remove drops the token, the session stays stored under its id for the
+// tokens issued since.
+func (rs *RedisStore) Remove(token string) {
+	_, err := rs.client.Do("DEL", REDIS_TOKEN_KEY_PREFIX+token)
+	if err != nil {
+		log.Printf("redis store: removing session failed: %s", err)
+	}
+}
+
+// RemoveExpired does nothing, the keys expire on their own.
+func (rs *RedisStore) RemoveExpired(now time.Time) {}
+
+func gameDigest(g puzzle.GameState) (string, error) {
+	b, err := g.MarshalBinary()
+	if err != nil {
+		return "", fmt.Errorf("redis store: marshal game failed: %s", err)
+	}
+
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,146 @@
+package session
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/pandorasNox/lettr/pkg/clock"
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/random"
+	"github.com/pandorasNox/lettr/pkg/redis"
+)
+
+func TestRedisStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	fakeClock := clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	wdb := puzzle.WordDatabase{}
+	solution := puzzle.Word{'c', 'r', 'i', 'e', 'd'}
+	tried := puzzle.EvaluateGuessedWord(puzzle.Word{'t', 'r', 'i', 'e', 'd'}, solution)
+
+	// every instance has its own Sessions and connections
+	instance := func() *Sessions {
+		client := redis.NewClient(mr.Addr())
+		t.Cleanup(func() { client.Close() })
+
+		ss := NewSessions(fakeClock, random.New(1))
+		ss.SetStore(NewRedisStore(client))
+		// instances verify the cookies signed by each other
+		if err := ss.SetTokenSecrets("secret"); err != nil {
+			t.Fatal(err)
+		}
+		return &ss
+	}
+
+	requestWithCookieFrom := func(res *httptest.ResponseRecorder) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/lettr", nil)
+		req.AddCookie(res.Result().Cookies()[0])
+		return req
+	}
+
+	first, second := instance(), instance()
+
+	res := httptest.NewRecorder()
+	sess := HandleSession(res, httptest.NewRequest(http.MethodGet, "/", nil), first, wdb)
+	sess.SetGameState(puzzle.NewChallengeGame("", solution))
+	if err := first.Update(sess); err != nil {
+		t.Fatalf("Update() error = %s", err)
+	}
+
+	t.Run("shared between instances", func(t *testing.T) {
+		got := HandleSession(httptest.NewRecorder(), requestWithCookieFrom(res), second, wdb)
+		if got.id != sess.id || got.gameState.ActiveSolutionWord() != solution {
+			t.Errorf("session not found by the second instance, got id='%s', want id='%s'", got.id, sess.id)
+		}
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		for _, key := range []string{REDIS_KEY_PREFIX + sess.id, REDIS_TOKEN_KEY_PREFIX + sess.token} {
+			if ttl := mr.TTL(key); ttl != SESSION_MAX_AGE_IN_SECONDS*time.Second {
+				t.Errorf("TTL of %s = %s, want %ds", key, ttl, SESSION_MAX_AGE_IN_SECONDS)
+			}
+		}
+	})
+
+	t.Run("concurrent guesses", func(t *testing.T) {
+		// both instances load the game before either one stored its guess
+		a := HandleSession(httptest.NewRecorder(), requestWithCookieFrom(res), first, wdb)
+		b := HandleSession(httptest.NewRecorder(), requestWithCookieFrom(res), second, wdb)
+
+		_ = a.GameState().Guess(tried, fakeClock.Now())
+		if err := first.Update(a); err != nil {
+			t.Fatalf("Update() of the first guess error = %s", err)
+		}
+		// saving the same state again, e.g. a handler saving twice, is fine
+		if err := first.Update(a); err != nil {
+			t.Fatalf("Update() of an unchanged game error = %s", err)
+		}
+
+		_ = b.GameState().Guess(puzzle.EvaluateGuessedWord(solution, solution), fakeClock.Now())
+		if err := second.Update(b); !errors.Is(err, ErrSessionConflict) {
+			t.Fatalf("Update() of a guess for a stale game error = %v, want %s", err, ErrSessionConflict)
+		}
+
+		// changes besides the game do not resurrect the stale game either
+		b = HandleSession(httptest.NewRecorder(), requestWithCookieFrom(res), second, wdb)
+		if b.gameState.LastEvaluatedAttempt().Guesses[0] != tried || b.gameState.Status() != puzzle.STATUS_IN_PROGRESS {
+			t.Errorf("stored game = %v, want the first guess only", b.gameState.LastEvaluatedAttempt())
+		}
+	})
+
+	t.Run("concurrent guesses across a token rotation", func(t *testing.T) {
+		fakeClock.Advance(SESSION_TOKEN_ROTATION)
+
+		// two tabs load the game with the same cookie before either one
+		// saved, both rotate its token
+		a, okA := first.load(requestWithCookieFrom(res))
+		b, okB := second.load(requestWithCookieFrom(res))
+		if !okA || !okB {
+			t.Fatalf("session not loaded by both instances")
+		}
+		a.RotateToken(fakeClock.Now())
+		b.RotateToken(fakeClock.Now())
+
+		resA := httptest.NewRecorder()
+		a.response = resA
+		_ = a.GameState().Guess(tried, fakeClock.Now())
+		if err := first.Update(a); err != nil {
+			t.Fatalf("Update() of the first guess error = %s", err)
+		}
+
+		_ = b.GameState().Guess(puzzle.EvaluateGuessedWord(solution, solution), fakeClock.Now())
+		if err := second.Update(b); !errors.Is(err, ErrSessionConflict) {
+			t.Fatalf("Update() of a guess for a stale game error = %v, want %s", err, ErrSessionConflict)
+		}
+
+		got := HandleSession(httptest.NewRecorder(), requestWithCookieFrom(resA), second, wdb)
+		if got.id != sess.id || got.gameState.Status() != puzzle.STATUS_IN_PROGRESS {
+			t.Errorf("session after the rotation = '%s' %v, want '%s' in progress", got.id, got.gameState.Status(), sess.id)
+		}
+		res = resA
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		mr.FastForward(SESSION_MAX_AGE_IN_SECONDS * time.Second)
+
+		got := HandleSession(httptest.NewRecorder(), requestWithCookieFrom(res), second, wdb)
+		if got.id == sess.id {
+			t.Errorf("expected a new session after the key expired, got id='%s'", got.id)
+		}
+	})
+}
+
+func TestRedisStore_Load_corrupted(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rs := NewRedisStore(redis.NewClient(mr.Addr()))
+
+	mr.Set(REDIS_TOKEN_KEY_PREFIX+"foo", "foo")
+	mr.HSet(REDIS_KEY_PREFIX+"foo", "game", "digest", "data", `{"id":"foo","game":{"version":99}}`)
+
+	if _, ok := rs.Load("foo"); ok {
+		t.Errorf("Load() of a corrupted session succeeded")
+	}
+}
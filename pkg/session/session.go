@@ -1,21 +1,25 @@
 package session
 
 import (
-	"fmt"
 	"log"
-	"math/rand"
 	"net/http"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/pandorasNox/lettr/pkg/i18n"
 	"github.com/pandorasNox/lettr/pkg/language"
 	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/random"
 )
 
 const SESSION_COOKIE_NAME = "session"
 const SESSION_MAX_AGE_IN_SECONDS = 24 * 60 * 60
 
+// HISTORY_MAX_GAMES is the number of finished games kept, older ones drop out.
+const HISTORY_MAX_GAMES = 100
+
 // type handleSess func(w http.ResponseWriter, req *http.Request, sessions *sessions, wdb puzzle.WordDatabase) Session
 
 type session struct {
@@ -27,6 +31,33 @@ type session struct {
 	gameState                        puzzle.GameState
 	pastWords                        []puzzle.Word
 	securityHoneypotMessageInputName string
+	keyboardLayout                   string
+	pick                             puzzle.Pick
+	survivalBest                     int
+	nickname                         string
+	streak                           int
+	solvedDay                        string
+	solvedToday                      int
+	accountID                        string
+	history                          []puzzle.GameRecord
+	// response belongs to the request the session was loaded for, stores
+	// keeping sessions in cookies write to it
+	response http.ResponseWriter
+	// storedGame is the digest of the game state last loaded from or saved
+	// to a shared store, see RedisStore
+	storedGame string
+	// transient sessions are shown but never saved, see PeekSession
+	transient bool
+	// token is the secret the cookie refers to the session by, unlike the id
+	// it changes, see RotateToken
+	token         string
+	tokenIssuedAt time.Time
+	// previousToken is removed from the store on the next save
+	previousToken string
+}
+
+func (s *session) ID() string {
+	return s.id
 }
 
 func (s *session) AddPastWord(w puzzle.Word) {
@@ -45,8 +76,104 @@ func (s *session) SetLanguage(l language.Language) {
 	s.language = l
 }
 
-func (s *session) NewGame(l language.Language, wdb puzzle.WordDatabase) {
-	s.gameState = puzzle.NewGame(l, wdb, s.PastWords())
+// KeyboardLayout is the layout the player picked, empty if they never did.
+func (s *session) KeyboardLayout() string {
+	return s.keyboardLayout
+}
+
+func (s *session) SetKeyboardLayout(name string) {
+	s.keyboardLayout = name
+}
+
+// Pick is the themed word collection and the difficulty the player picks
+// solutions by, the zero value for the default solutions of any difficulty.
+func (s *session) Pick() puzzle.Pick {
+	return s.pick
+}
+
+func (s *session) SetPick(p puzzle.Pick) {
+	s.pick = p
+}
+
+func (s *session) NewGame(rnd random.Rand, l language.Language, wdb puzzle.WordDatabase) {
+	s.gameState = puzzle.NewGame(rnd, l, s.pick, wdb, s.PastWords())
+}
+
+// SurvivalBest is the personal best score of all survival runs.
+func (s *session) SurvivalBest() int {
+	return s.survivalBest
+}
+
+// RecordSurvivalRun reports whether score is a new personal best.
+func (s *session) RecordSurvivalRun(score int) bool {
+	if score <= s.survivalBest {
+		return false
+	}
+
+	s.survivalBest = score
+	return true
+}
+
+// Nickname is shown on leaderboards, players without one stay anonymous.
+func (s *session) Nickname() string {
+	return s.nickname
+}
+
+func (s *session) SetNickname(nickname string) {
+	s.nickname = nickname
+}
+
+// RecordResult counts a finished game towards the win streak and the puzzles
+// solved on the day of now. It returns both counts.
+func (s *session) RecordResult(now time.Time, solved bool) (streak int, solvedToday int) {
+	if !solved {
+		s.streak = 0
+		return s.streak, s.solvedToday
+	}
+
+	day := now.UTC().Format(time.DateOnly)
+	if s.solvedDay != day {
+		s.solvedDay = day
+		s.solvedToday = 0
+	}
+
+	s.streak++
+	s.solvedToday++
+
+	return s.streak, s.solvedToday
+}
+
+// AddToHistory archives a finished game.
+func (s *session) AddToHistory(r puzzle.GameRecord) {
+	s.history = trimHistory(append(slices.Clip(s.history), r))
+}
+
+// AbandonGame gives up the current game before it gets replaced. Games
+// with at least one guess are kept in the history.
+func (s *session) AbandonGame(now time.Time) {
+	if s.gameState.Status() != puzzle.STATUS_IN_PROGRESS {
+		return
+	}
+
+	if err := s.gameState.Abandon(now); err != nil {
+		log.Printf("AbandonGame: %s", err)
+		return
+	}
+
+	s.AddToHistory(s.gameState.Record(s.language, now))
+}
+
+// History returns the finished games, oldest first.
+func (s *session) History() []puzzle.GameRecord {
+	return slices.Clone(s.history)
+}
+
+func trimHistory(h []puzzle.GameRecord) []puzzle.GameRecord {
+	if len(h) > HISTORY_MAX_GAMES {
+		return h[len(h)-HISTORY_MAX_GAMES:]
+	}
+
+	return h
 }
 
 func (s *session) GameState() *puzzle.GameState {
@@ -62,7 +189,8 @@ func (s *session) SecurityHoneypotMessageInputName() string {
 }
 
 func (s *session) NewSecurityHoneypotMessageInputName() {
-	name, err := randomString(42)
+	// the name must not be guessable by bots, so it comes from crypto/rand
+	name, err := random.SecureString(42)
 	if err != nil {
 		log.Printf("NewSecurityHoneypotMessageInputName: failed generating random string: %s", err)
 		name = "123456789"
@@ -71,78 +199,127 @@ func (s *session) NewSecurityHoneypotMessageInputName() {
 	s.securityHoneypotMessageInputName = name
 }
 
-func randomString(length int) (string, error) {
-	b := make([]byte, length+2)
-	randsource := rand.NewSource(time.Now().UnixNano())
-	randgenerator := rand.New(randsource)
-	_, err := randgenerator.Read(b)
+// HandleSession returns the session of req, players without one get a new
+// session. See StartSession for players starting too many sessions.
+func HandleSession(w http.ResponseWriter, req *http.Request, sessions *Sessions, wdb puzzle.WordDatabase) session {
+	sess, err := StartSession(w, req, sessions, wdb)
 	if err != nil {
-		return "", fmt.Errorf("randomString: read from rand failed: %s", err)
+		log.Printf("HandleSession: %s", err)
 	}
 
-	return fmt.Sprintf("%x", b)[2 : length+2], nil
+	return sess
 }
 
-func HandleSession(w http.ResponseWriter, req *http.Request, sessions *Sessions, wdb puzzle.WordDatabase) session {
-	var err error
-	var sess session
-
-	cookie, err := req.Cookie(SESSION_COOKIE_NAME)
-	if err != nil {
-		return newSession(w, sessions, wdb)
+// StartSession returns the session of req or starts a new one. Addresses
+// which started too many sessions recently get ErrTooManySessions along with
+// a transient session.
+func StartSession(w http.ResponseWriter, req *http.Request, sessions *Sessions, wdb puzzle.WordDatabase) (session, error) {
+	sess, ok := loadSession(w, req, sessions)
+	if ok {
+		return sess, nil
 	}
 
-	if cookie == nil {
-		return newSession(w, sessions, wdb)
+	return newSession(w, req, sessions, wdb)
+}
+
+// PeekSession returns the session of req for pages which only show state.
+// Players without a session get a transient one with an empty game, so
+// crawlers and visitors who never play leave nothing behind. Existing
+// sessions are saved with their refreshed lifetime and token, so pages only
+// showing state need no further save.
+func PeekSession(w http.ResponseWriter, req *http.Request, sessions *Sessions) (session, bool) {
+	sess, ok := loadSession(w, req, sessions)
+	if ok {
+		return sess, true
 	}
 
-	sid := cookie.Value
-	i := slices.IndexFunc(sessions.sessions, func(s session) bool {
-		return s.id == sid
-	})
-	if i == -1 {
-		return newSession(w, sessions, wdb)
+	return TransientSession(req), false
+}
+
+func loadSession(w http.ResponseWriter, req *http.Request, sessions *Sessions) (session, bool) {
+	sess, ok := sessions.load(req)
+	if !ok || sessions.now().After(sess.expiresAt) {
+		return session{}, false
 	}
 
-	sess = sessions.sessions[i]
+	sessions.loadProgress(&sess)
 
-	c := ConstructCookie(sess)
-	http.SetCookie(w, &c)
+	now := sessions.now()
+	if now.Sub(sess.tokenIssuedAt) >= SESSION_TOKEN_ROTATION {
+		sess.RotateToken(now)
+	}
 
-	sess.expiresAt = generateSessionLifetime()
-	sessions.sessions[i] = sess
+	sess.maxAgeSeconds = sessions.cookiePolicy().maxAgeSeconds()
+	sess.expiresAt = generateSessionLifetime(now, sess.maxAgeSeconds)
+	sess.response = w
+	err := sessions.save(&sess)
+	if err != nil {
+		log.Printf("saving session failed: %s", err)
+	}
 
-	return sess
+	return sess, true
 }
 
-func newSession(w http.ResponseWriter, sessions *Sessions, wdb puzzle.WordDatabase) session {
-	sess := generateSession(language.LANG_EN, wdb)
-	sessions.sessions = append(sessions.sessions, sess)
-	c := ConstructCookie(sess)
-	http.SetCookie(w, &c)
+func newSession(w http.ResponseWriter, req *http.Request, sessions *Sessions, wdb puzzle.WordDatabase) (session, error) {
+	if !sessions.allowNew(req) {
+		return TransientSession(req), ErrTooManySessions
+	}
 
-	return sess
+	// first visit, so pick the language the browser asks for
+	l := i18n.Negotiate(req.Header.Get("Accept-Language"))
+
+	sess := generateSession(sessions.now(), sessions.rand(), l, wdb, sessions.cookiePolicy().maxAgeSeconds())
+	sess.response = w
+	err := sessions.save(&sess)
+	if err != nil {
+		log.Printf("saving session failed: %s", err)
+	}
+
+	return sess, nil
 }
 
-func ConstructCookie(s session) http.Cookie {
-	return http.Cookie{
-		Name:     SESSION_COOKIE_NAME,
-		Value:    s.id,
-		Path:     "/",
-		MaxAge:   s.maxAgeSeconds,
-		HttpOnly: true,
-		Secure:   true,
-		SameSite: http.SameSiteStrictMode,
+// TransientSession is never saved, it only carries the language the browser
+// asks for.
+func TransientSession(req *http.Request) session {
+	return session{
+		language:  i18n.Negotiate(req.Header.Get("Accept-Language")),
+		pastWords: []puzzle.Word{},
+		transient: true,
 	}
 }
 
-func generateSession(lang language.Language, wdb puzzle.WordDatabase) session { //todo: pass it by ref not by copy?
-	id := uuid.NewString()
-	expiresAt := generateSessionLifetime()
+// IsTransient reports whether the session is a stand-in which is never
+// saved, see PeekSession.
+func (s *session) IsTransient() bool {
+	return s.transient
+}
+
+// setCookie replaces a cookie of the same name set earlier in the response,
+// so saving a session several times sends it only once.
+func setCookie(w http.ResponseWriter, c *http.Cookie) {
+	w.Header()["Set-Cookie"] = slices.DeleteFunc(w.Header()["Set-Cookie"], func(v string) bool {
+		return strings.HasPrefix(v, c.Name+"=")
+	})
+
+	http.SetCookie(w, c)
+}
+
+func generateSession(now time.Time, rnd random.Rand, lang language.Language, wdb puzzle.WordDatabase, maxAgeSeconds int) session { //todo: pass it by ref not by copy?
+	id := uuid.NewString() // uuid v4 is read from crypto/rand
+	expiresAt := generateSessionLifetime(now, maxAgeSeconds)
 
-	return session{id, expiresAt, SESSION_MAX_AGE_IN_SECONDS, lang, puzzle.NewGame(lang, wdb, []puzzle.Word{}), []puzzle.Word{}, ""}
+	return session{
+		id:            id,
+		expiresAt:     expiresAt,
+		maxAgeSeconds: maxAgeSeconds,
+		language:      lang,
+		gameState:     puzzle.NewGame(rnd, lang, puzzle.Pick{}, wdb, []puzzle.Word{}),
+		pastWords:     []puzzle.Word{},
+		token:         uuid.NewString(),
+		tokenIssuedAt: now,
+	}
 }
 
-func generateSessionLifetime() time.Time {
-	return time.Now().Add(SESSION_MAX_AGE_IN_SECONDS * time.Second) // todo: 24 hour, sec now only for testing
+func generateSessionLifetime(now time.Time, maxAgeSeconds int) time.Time {
+	return now.Add(time.Duration(maxAgeSeconds) * time.Second)
 }
@@ -10,29 +10,24 @@ import (
 
 	"github.com/agiledragon/gomonkey/v2"
 	"github.com/google/uuid"
+	"github.com/pandorasNox/lettr/pkg/clock"
 	"github.com/pandorasNox/lettr/pkg/language"
 	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/random"
 )
 
-func Test_ConstructCookie(t *testing.T) {
-	fixedUuid := "9566c74d-1003-4c4d-bbbb-0407d1e2c649"
-	expireDate := time.Date(2024, 02, 27, 0, 0, 0, 0, time.Now().Location())
-
-	type args struct {
-		s session
-	}
+func TestCookiePolicy_cookie(t *testing.T) {
 	tests := []struct {
-		name string
-		args args
-		want http.Cookie
+		name    string
+		profile string
+		want    http.Cookie
 	}{
-		// add test cases here
 		{
-			"test_name",
-			args{session{fixedUuid, expireDate, SESSION_MAX_AGE_IN_SECONDS, language.LANG_EN, puzzle.NewGame(language.LANG_EN, puzzle.WordDatabase{}, []puzzle.Word{}), []puzzle.Word{}, ""}},
+			"prod",
+			COOKIE_PROFILE_PROD,
 			http.Cookie{
 				Name:     SESSION_COOKIE_NAME,
-				Value:    fixedUuid,
+				Value:    "foo.sig",
 				Path:     "/",
 				MaxAge:   SESSION_MAX_AGE_IN_SECONDS,
 				HttpOnly: true,
@@ -40,11 +35,69 @@ func Test_ConstructCookie(t *testing.T) {
 				SameSite: http.SameSiteStrictMode,
 			},
 		},
+		{
+			"dev",
+			COOKIE_PROFILE_DEV,
+			http.Cookie{
+				Name:     SESSION_COOKIE_NAME,
+				Value:    "foo.sig",
+				Path:     "/",
+				MaxAge:   SESSION_MAX_AGE_IN_SECONDS,
+				HttpOnly: true,
+				Secure:   false,
+				SameSite: http.SameSiteLaxMode,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := ConstructCookie(tt.args.s); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("constructCookie() = %v, want %v", got, tt.want)
+			cp, err := CookiePolicyByProfile(tt.profile)
+			if err != nil {
+				t.Fatalf("CookiePolicyByProfile() error = %s", err)
+			}
+			if got := cp.cookie("foo.sig"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cookie() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := CookiePolicyByProfile("staging"); err == nil {
+		t.Errorf("CookiePolicyByProfile() of an unknown profile succeeded")
+	}
+}
+
+func TestCookiePolicy_Validate(t *testing.T) {
+	none := DefaultCookiePolicy()
+	none.SameSite, _ = ParseSameSite("None")
+	insecureNone := none
+	insecureNone.Secure = false
+	badName := DefaultCookiePolicy()
+	badName.Name = "lettr session"
+	longName := DefaultCookiePolicy()
+	longName.Name = strings.Repeat("x", COOKIE_NAME_MAX_SIZE+1)
+	maxName := DefaultCookiePolicy()
+	maxName.Name = strings.Repeat("x", COOKIE_NAME_MAX_SIZE)
+	short := DefaultCookiePolicy()
+	short.MaxAge = time.Millisecond
+
+	tests := []struct {
+		name    string
+		cp      CookiePolicy
+		wantErr bool
+	}{
+		{"default", DefaultCookiePolicy(), false},
+		{"dev", DevCookiePolicy(), false},
+		{"SameSite=None secure", none, false},
+		{"SameSite=None insecure", insecureNone, true},
+		{"invalid name", badName, true},
+		{"longest name", maxName, false},
+		{"name too long for sealed sessions", longName, true},
+		{"max age below a second", short, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cp.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
@@ -58,12 +111,7 @@ func Test_HandleSession(t *testing.T) {
 		wdb      puzzle.WordDatabase
 	}
 
-	// monkey patch time.Now
-	patchFnTime := func() time.Time {
-		return time.Unix(1615256178, 0)
-	}
-	patchesTime := gomonkey.ApplyFunc(time.Now, patchFnTime)
-	defer patchesTime.Reset()
+	fakeClock := clock.NewFake(time.Unix(1615256178, 0))
 	// monkey patch uuid.NewString
 	patches := gomonkey.ApplyFuncReturn(uuid.NewString, "12345678-abcd-1234-abcd-ab1234567890")
 	defer patches.Reset()
@@ -76,6 +124,8 @@ func Test_HandleSession(t *testing.T) {
 		},
 	}}
 
+	w := httptest.NewRecorder()
+
 	tests := []struct {
 		name string
 		args args
@@ -85,22 +135,27 @@ func Test_HandleSession(t *testing.T) {
 		{
 			"test handleSession is generating new session if no cookie is set",
 			args{
-				httptest.NewRecorder(),
+				w,
 				httptest.NewRequest("get", "/", strings.NewReader("Hello, Reader!")),
-				&Sessions{},
+				&Sessions{clock: fakeClock, rnd: random.New(1)},
 				mockWordDatabase,
 			},
 			session{
 				id:            "12345678-abcd-1234-abcd-ab1234567890",
+				token:         "12345678-abcd-1234-abcd-ab1234567890",
+				tokenIssuedAt: time.Unix(1615256178, 0),
 				expiresAt:     time.Unix(1615256178, 0).Add(SESSION_MAX_AGE_IN_SECONDS * time.Second),
 				maxAgeSeconds: 86400,
 				language:      language.LANG_EN,
 				gameState: puzzle.NewGame(
+					random.New(1),
 					language.LANG_EN,
+					puzzle.Pick{},
 					mockWordDatabase,
 					[]puzzle.Word{},
 				),
 				pastWords: []puzzle.Word{},
+				response:  w,
 			},
 		},
 		// {
@@ -136,6 +191,51 @@ func Test_HandleSession(t *testing.T) {
 	// })
 }
 
+func Test_HandleSession_expiry(t *testing.T) {
+	fakeClock := clock.NewFake(time.Unix(1615256178, 0))
+	sessions := NewSessions(fakeClock, random.New(1))
+	wdb := puzzle.WordDatabase{}
+
+	requestWithCookieFrom := func(res *httptest.ResponseRecorder) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(res.Result().Cookies()[0])
+		return req
+	}
+
+	first := httptest.NewRecorder()
+	sess := HandleSession(first, httptest.NewRequest(http.MethodGet, "/", nil), &sessions, wdb)
+
+	fakeClock.Advance(SESSION_MAX_AGE_IN_SECONDS*time.Second - time.Second)
+	second := httptest.NewRecorder()
+	got := HandleSession(second, requestWithCookieFrom(first), &sessions, wdb)
+	if got.id != sess.id {
+		t.Fatalf("session expired too early, got id='%s', want id='%s'", got.id, sess.id)
+	}
+	if want := fakeClock.Now().Add(SESSION_MAX_AGE_IN_SECONDS * time.Second); !got.expiresAt.Equal(want) {
+		t.Errorf("session lifetime not extended, got expiresAt='%s', want '%s'", got.expiresAt, want)
+	}
+
+	fakeClock.Advance(SESSION_MAX_AGE_IN_SECONDS*time.Second + time.Second)
+	got = HandleSession(httptest.NewRecorder(), requestWithCookieFrom(second), &sessions, wdb)
+	if got.id == sess.id {
+		t.Errorf("expected a new session after expiry, got the expired one with id='%s'", got.id)
+	}
+}
+
+func TestSession_NewSecurityHoneypotMessageInputName(t *testing.T) {
+	s := session{}
+	s.NewSecurityHoneypotMessageInputName()
+	first := s.SecurityHoneypotMessageInputName()
+	s.NewSecurityHoneypotMessageInputName()
+
+	if len(first) != 42 {
+		t.Errorf("expected honeypot name of length 42, got '%s'", first)
+	}
+	if first == s.SecurityHoneypotMessageInputName() {
+		t.Errorf("expected a fresh honeypot name, got '%s' twice", first)
+	}
+}
+
 func TestSessions_UpdateOrSet(t *testing.T) {
 	type args struct {
 		sess session
@@ -158,15 +258,15 @@ func TestSessions_UpdateOrSet(t *testing.T) {
 		},
 		{
 			"update session",
-			&Sessions{[]session{{id: "foo", maxAgeSeconds: 1}}},
+			&Sessions{sessions: []session{{id: "foo", maxAgeSeconds: 1}}},
 			args{session{id: "foo", maxAgeSeconds: 2}},
-			Sessions{[]session{{id: "foo", maxAgeSeconds: 2}}},
+			Sessions{sessions: []session{{id: "foo", maxAgeSeconds: 2}}},
 		},
 		{
 			"update session changes only correct session",
-			&Sessions{[]session{{id: "foo"}, {id: "bar"}, {id: "baz", maxAgeSeconds: 1}, {id: "foobar"}}},
+			&Sessions{sessions: []session{{id: "foo"}, {id: "bar"}, {id: "baz", maxAgeSeconds: 1}, {id: "foobar"}}},
 			args{session{id: "baz", maxAgeSeconds: 2}},
-			Sessions{[]session{{id: "foo"}, {id: "bar"}, {id: "baz", maxAgeSeconds: 2}, {id: "foobar"}}},
+			Sessions{sessions: []session{{id: "foo"}, {id: "bar"}, {id: "baz", maxAgeSeconds: 2}, {id: "foobar"}}},
 		},
 	}
 	for _, tt := range tests {
@@ -178,3 +278,27 @@ func TestSessions_UpdateOrSet(t *testing.T) {
 		})
 	}
 }
+
+func TestSession_RecordResult(t *testing.T) {
+	day := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	s := session{}
+
+	type result struct{ streak, solvedToday int }
+	steps := []struct {
+		name   string
+		now    time.Time
+		solved bool
+		want   result
+	}{
+		{name: "first win", now: day, solved: true, want: result{1, 1}},
+		{name: "second win", now: day.Add(time.Hour), solved: true, want: result{2, 2}},
+		{name: "loss resets the streak only", now: day.Add(2 * time.Hour), solved: false, want: result{0, 2}},
+		{name: "win on the next day", now: day.Add(24 * time.Hour), solved: true, want: result{1, 1}},
+	}
+	for _, st := range steps {
+		streak, solvedToday := s.RecordResult(st.now, st.solved)
+		if got := (result{streak, solvedToday}); got != st.want {
+			t.Errorf("%s: RecordResult() = %+v, want %+v", st.name, got, st.want)
+		}
+	}
+}
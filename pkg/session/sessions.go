@@ -1,9 +1,15 @@
 package session
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"net/http"
 	"slices"
 	"time"
+
+	"github.com/pandorasNox/lettr/pkg/clock"
+	"github.com/pandorasNox/lettr/pkg/random"
 )
 
 type ISessions interface {
@@ -12,19 +18,156 @@ type ISessions interface {
 	RemoveExpiredSessions()
 }
 
+// Sessions keeps the sessions in memory unless a Store is set.
 type Sessions struct {
 	sessions []session
+	clock    clock.Clock
+	rnd      random.Rand
+	progress ProgressStore
+	store    Store
+	// limit caps the sessions started per address, none for a zero value
+	// Sessions
+	limit *creationLimit
+	// proxyHeader holds the client address set by a trusted proxy, none
+	// by default
+	proxyHeader string
+	policy      CookiePolicy
+	signer      *tokenSigner
+}
+
+// Store keeps sessions outside of the process memory, e.g. in the cookie
+// itself or in a database shared by several instances.
+type Store interface {
+	// Load returns the session value refers to, value is the verified
+	// cookie value.
+	Load(value string) (session, bool)
+	// Save persists s and returns the cookie value referring to it. It may
+	// update the bookkeeping of s.
+	Save(s *session) (string, error)
+	// Remove drops the session stored under the token, e.g. after the
+	// token rotated.
+	Remove(token string)
+	// RemoveExpired drops the sessions expired at now, stores expiring
+	// sessions on their own do nothing.
+	RemoveExpired(now time.Time)
 }
 
 // ensure interface implementation
 // var _ Sessioner = Sessions{}
 var _ ISessions = (*Sessions)(nil)
 
-func NewSessions() Sessions {
-	return Sessions{}
+func NewSessions(c clock.Clock, rnd random.Rand) Sessions {
+	return Sessions{
+		clock:  c,
+		rnd:    rnd,
+		limit:  newCreationLimit(SESSION_CREATION_LIMIT, SESSION_CREATION_WINDOW),
+		policy: DefaultCookiePolicy(),
+		signer: randomTokenSigner(),
+	}
+}
+
+// now falls back to the real clock for a zero value Sessions.
+func (ss *Sessions) now() time.Time {
+	if ss.clock == nil {
+		return time.Now()
+	}
+
+	return ss.clock.Now()
+}
+
+// rand falls back to a time seeded Rand for a zero value Sessions.
+func (ss *Sessions) rand() random.Rand {
+	if ss.rnd == nil {
+		ss.rnd = random.New(time.Now().UnixNano())
+	}
+
+	return ss.rnd
+}
+
+// cookiePolicy falls back to the default policy for a zero value Sessions.
+func (ss *Sessions) cookiePolicy() CookiePolicy {
+	if ss.policy == (CookiePolicy{}) {
+		return DefaultCookiePolicy()
+	}
+
+	return ss.policy
+}
+
+// SetCookiePolicy sets the attributes of the session cookie.
+func (ss *Sessions) SetCookiePolicy(cp CookiePolicy) error {
+	err := cp.Validate()
+	if err != nil {
+		return err
+	}
+
+	ss.policy = cp
+	ss.bindCookieName()
+	return nil
+}
+
+// tokenSigner falls back to a random key for a zero value Sessions.
+func (ss *Sessions) tokenSigner() *tokenSigner {
+	if ss.signer == nil {
+		ss.signer = randomTokenSigner()
+	}
+
+	return ss.signer
+}
+
+// SetTokenSecrets makes the session cookies signed with a key derived from
+// the first secret and accepted if signed with any of them. Several
+// instances sharing a Store have to share the secrets as well.
+func (ss *Sessions) SetTokenSecrets(secrets ...string) error {
+	signer, err := newTokenSigner(secrets...)
+	if err != nil {
+		return err
+	}
+
+	ss.signer = signer
+	return nil
+}
+
+// SetCreationLimit allows max sessions started per address within window.
+func (ss *Sessions) SetCreationLimit(max int, window time.Duration) {
+	ss.limit = newCreationLimit(max, window)
+}
+
+// SetTrustedProxyHeader makes the creation limit count sessions by the
+// client address the proxy in front sets in the header name, instead of by
+// the remote address. Only set it if the proxy overwrites values sent by
+// clients, e.g. "Fly-Client-IP" on fly.io.
+func (ss *Sessions) SetTrustedProxyHeader(name string) {
+	ss.proxyHeader = name
+}
+
+// allowNew reports whether the address of r may start another session.
+func (ss *Sessions) allowNew(r *http.Request) bool {
+	if ss.limit == nil {
+		return true
+	}
+
+	return ss.limit.allow(clientAddr(r, ss.proxyHeader), ss.now())
+}
+
+// SetStore makes the sessions kept by st instead of the memory.
+func (ss *Sessions) SetStore(st Store) {
+	ss.store = st
+	ss.bindCookieName()
+}
+
+// bindCookieName makes a CookieStore seal the sessions for the cookie name
+// of the policy, so renaming the cookie invalidates the sealed values.
+func (ss *Sessions) bindCookieName() {
+	if cs, ok := ss.store.(*CookieStore); ok {
+		cs.name = ss.cookiePolicy().Name
+	}
 }
 
 func (ss *Sessions) String() string {
+	if ss.store != nil {
+		return fmt.Sprintf("sessions kept by %T\n", ss.store)
+	}
+
 	out := ""
 	for _, s := range ss.sessions {
 		out = out + s.id + " " + s.expiresAt.String() + "\n"
@@ -33,7 +176,38 @@ func (ss *Sessions) String() string {
 	return out
 }
 
+// GetByCookie looks up the session kept in memory a session cookie value
+// refers to.
+func (ss *Sessions) GetByCookie(value string) (session, error) {
+	token, err := ss.tokenSigner().verify(value)
+	if err != nil {
+		return session{}, err
+	}
+
+	return ss.getByToken(token)
+}
+
+func (ss *Sessions) getByToken(token string) (session, error) {
+	if ss.store != nil {
+		return session{}, fmt.Errorf("sessions kept by %T can't be looked up", ss.store)
+	}
+
+	i := slices.IndexFunc(ss.sessions, func(s session) bool {
+		return s.token == token
+	})
+	if i == -1 {
+		return session{}, errors.New("no session found for token")
+	}
+
+	return ss.sessions[i], nil
+}
+
+// GetById looks up a session kept in memory.
 func (s *Sessions) GetById(sid string) (session, error) {
+	if s.store != nil {
+		return session{}, fmt.Errorf("sessions kept by %T can't be looked up by id", s.store)
+	}
+
 	i := slices.IndexFunc(s.sessions, func(s session) bool {
 		return s.id == sid
 	})
@@ -45,6 +219,86 @@ func (s *Sessions) GetById(sid string) (session, error) {
 }
 
 func (ss *Sessions) UpdateOrSet(sess session) {
+	err := ss.Update(sess)
+	if err != nil {
+		log.Printf("saving session failed: %s", err)
+	}
+}
+
+// Update is UpdateOrSet for requests that need to know whether their
+// changes got saved, stores shared by several instances reject a game
+// changed by another request in the meantime with ErrSessionConflict. The
+// account progress is only saved along with the session.
+func (ss *Sessions) Update(sess session) error {
+	err := ss.save(&sess)
+	if err != nil {
+		return err
+	}
+
+	ss.saveProgress(sess)
+	return nil
+}
+
+// load returns the session the cookie of r refers to, expired or not.
+func (ss *Sessions) load(r *http.Request) (session, bool) {
+	cookie, err := r.Cookie(ss.cookiePolicy().Name)
+	if err != nil {
+		return session{}, false
+	}
+
+	value, err := ss.tokenSigner().verify(cookie.Value)
+	if err != nil {
+		return session{}, false
+	}
+
+	if ss.store != nil {
+		return ss.store.Load(value)
+	}
+
+	sess, err := ss.getByToken(value)
+	if err != nil {
+		return session{}, false
+	}
+
+	return sess, true
+}
+
+// save persists sess and sets its cookie on the response of sess.
+func (ss *Sessions) save(sess *session) error {
+	if sess.transient {
+		return nil
+	}
+
+	value := sess.token
+	if ss.store != nil {
+		v, err := ss.store.Save(sess)
+		if err != nil {
+			return err
+		}
+		value = v
+
+		if sess.previousToken != "" {
+			ss.store.Remove(sess.previousToken)
+		}
+	} else {
+		ss.keep(*sess)
+	}
+	sess.previousToken = ""
+
+	if sess.response != nil {
+		c := ss.cookiePolicy().cookie(ss.tokenSigner().sign(value))
+		setCookie(sess.response, &c)
+	}
+
+	return nil
+}
+
+// keep stores sess in memory, replacing the session of the same id.
+func (ss *Sessions) keep(sess session) {
+	// the response is only valid during its request
+	sess.response = nil
+	sess.previousToken = ""
+
 	index := slices.IndexFunc((ss.sessions), func(s session) bool {
 		return s.id == sess.id
 	})
@@ -57,7 +311,12 @@ func (ss *Sessions) UpdateOrSet(sess session) {
 }
 
 func (ss *Sessions) RemoveExpiredSessions() {
-	now := time.Now()
+	now := ss.now()
+	if ss.store != nil {
+		ss.store.RemoveExpired(now)
+		return
+	}
+
 	ss.sessions = slices.DeleteFunc(ss.sessions, func(s session) bool {
 		return now.After(s.expiresAt)
 	})
@@ -4,10 +4,12 @@ import (
 	"reflect"
 	"testing"
 	"time"
+
+	"github.com/pandorasNox/lettr/pkg/clock"
 )
 
 func TestSessions_RemoveExpiredSessions(t *testing.T) {
-	now := time.Now()
+	now := time.Unix(1615256178, 0)
 	tests := []struct {
 		name           string
 		sessionsBefore []session
@@ -65,6 +67,7 @@ func TestSessions_RemoveExpiredSessions(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ss := &Sessions{
 				sessions: tt.sessionsBefore,
+				clock:    clock.NewFake(now),
 			}
 			ss.RemoveExpiredSessions()
 
@@ -0,0 +1,79 @@
+package session
+
+import (
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/language"
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+)
+
+// storedSession is a session as kept by the stores outside of the process
+// memory.
+type storedSession struct {
+	ID             string                `json:"id"`
+	ExpiresAt      time.Time             `json:"expiresAt"`
+	MaxAgeSeconds  int                   `json:"maxAge"`
+	Language       language.Language     `json:"lang"`
+	GameState      puzzle.GameState      `json:"game"`
+	PastWords      []puzzle.Word         `json:"pastWords"`
+	HoneypotName   string                `json:"honeypot"`
+	KeyboardLayout string                `json:"keyboard,omitempty"`
+	Collection     puzzle.WordCollection `json:"collection,omitempty"`
+	Difficulty     puzzle.Difficulty     `json:"difficulty,omitempty"`
+	SurvivalBest   int                   `json:"survivalBest,omitempty"`
+	Nickname       string                `json:"nickname,omitempty"`
+	Streak         int                   `json:"streak,omitempty"`
+	SolvedDay      string                `json:"solvedDay,omitempty"`
+	SolvedToday    int                   `json:"solvedToday,omitempty"`
+	AccountID      string                `json:"accountId,omitempty"`
+	History        []puzzle.GameRecord   `json:"history,omitempty"`
+	Token          string                `json:"token"`
+	TokenIssuedAt  time.Time             `json:"tokenIssuedAt"`
+}
+
+func newStoredSession(s session) storedSession {
+	return storedSession{
+		ID:             s.id,
+		ExpiresAt:      s.expiresAt,
+		MaxAgeSeconds:  s.maxAgeSeconds,
+		Language:       s.language,
+		GameState:      s.gameState,
+		PastWords:      s.PastWords(),
+		HoneypotName:   s.securityHoneypotMessageInputName,
+		KeyboardLayout: s.keyboardLayout,
+		Collection:     s.pick.Collection,
+		Difficulty:     s.pick.Difficulty,
+		SurvivalBest:   s.survivalBest,
+		Nickname:       s.nickname,
+		Streak:         s.streak,
+		SolvedDay:      s.solvedDay,
+		SolvedToday:    s.solvedToday,
+		AccountID:      s.accountID,
+		History:        s.History(),
+		Token:          s.token,
+		TokenIssuedAt:  s.tokenIssuedAt,
+	}
+}
+
+func (p storedSession) session() session {
+	return session{
+		id:                               p.ID,
+		expiresAt:                        p.ExpiresAt,
+		maxAgeSeconds:                    p.MaxAgeSeconds,
+		language:                         p.Language,
+		gameState:                        p.GameState,
+		pastWords:                        p.PastWords,
+		securityHoneypotMessageInputName: p.HoneypotName,
+		keyboardLayout:                   p.KeyboardLayout,
+		pick:                             puzzle.Pick{Collection: p.Collection, Difficulty: p.Difficulty},
+		survivalBest:                     p.SurvivalBest,
+		nickname:                         p.Nickname,
+		streak:                           p.Streak,
+		solvedDay:                        p.SolvedDay,
+		solvedToday:                      p.SolvedToday,
+		accountID:                        p.AccountID,
+		history:                          p.History,
+		token:                            p.Token,
+		tokenIssuedAt:                    p.TokenIssuedAt,
+	}
+}
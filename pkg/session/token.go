@@ -0,0 +1,102 @@
+package session
+
+import (
+	"crypto/hmac"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SESSION_TOKEN_ROTATION is the age after which the token of a session is
+// replaced, so a leaked cookie is of use for a short time only.
+const SESSION_TOKEN_ROTATION = time.Hour
+
+var ErrInvalidSignature = errors.New("session cookie signature invalid")
+
+// tokenSigner signs the cookie values with HMAC-SHA256, so tampered cookies
+// are rejected before any store is asked. The first key signs, all keys
+// verify.
+type tokenSigner struct {
+	keys [][]byte
+}
+
+func newTokenSigner(secrets ...string) (*tokenSigner, error) {
+	if len(secrets) == 0 {
+		return nil, errors.New("token signer: no secret")
+	}
+
+	ts := &tokenSigner{}
+	for _, secret := range secrets {
+		if secret == "" {
+			return nil, errors.New("token signer: empty secret")
+		}
+
+		// the secrets may seal cookies as well, see CookieStore, so the
+		// signing keys are derived differently
+		key := sha256.Sum256([]byte("session token:" + secret))
+		ts.keys = append(ts.keys, key[:])
+	}
+
+	return ts, nil
+}
+
+// randomTokenSigner signs with a key only known to this process, cookies
+// don't survive restarts.
+func randomTokenSigner() *tokenSigner {
+	key := make([]byte, sha256.Size)
+	_, err := crand.Read(key)
+	if err != nil {
+		panic(err)
+	}
+
+	return &tokenSigner{keys: [][]byte{key}}
+}
+
+func (ts *tokenSigner) sign(value string) string {
+	return value + "." + base64.RawURLEncoding.EncodeToString(mac(ts.keys[0], value))
+}
+
+// verify returns the value signed, values are free of dots.
+func (ts *tokenSigner) verify(signed string) (string, error) {
+	value, sig, ok := strings.Cut(signed, ".")
+	if !ok {
+		return "", ErrInvalidSignature
+	}
+
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+
+	for _, key := range ts.keys {
+		if hmac.Equal(got, mac(key, value)) {
+			return value, nil
+		}
+	}
+
+	return "", ErrInvalidSignature
+}
+
+func mac(key []byte, value string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(value))
+
+	return h.Sum(nil)
+}
+
+// RotateToken replaces the token the cookie refers to the session by, e.g.
+// when the session gains access to an account. The id of the session stays.
+func (s *session) RotateToken(now time.Time) {
+	// the first token is the one stores still keep
+	if s.previousToken == "" {
+		s.previousToken = s.token
+	}
+
+	s.token = uuid.NewString()
+	s.tokenIssuedAt = now
+}
@@ -0,0 +1,126 @@
+package session
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pandorasNox/lettr/pkg/clock"
+	"github.com/pandorasNox/lettr/pkg/puzzle"
+	"github.com/pandorasNox/lettr/pkg/random"
+)
+
+func TestTokenSigner(t *testing.T) {
+	old, err := newTokenSigner("old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := newTokenSigner("new", "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signed := old.sign("foo")
+	if got, err := old.verify(signed); err != nil || got != "foo" {
+		t.Errorf("verify() = '%s', %v; want 'foo'", got, err)
+	}
+	if got, err := rotated.verify(signed); err != nil || got != "foo" {
+		t.Errorf("verify() after rotation = '%s', %v; want 'foo'", got, err)
+	}
+	if _, err := old.verify(rotated.sign("foo")); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("verify() of a value signed with an unknown secret error = %v, want %s", err, ErrInvalidSignature)
+	}
+
+	for _, tampered := range []string{"foo", "bar" + signed[len("foo"):], signed + "x", signed[:len(signed)-1] + "!", ""} {
+		if _, err := old.verify(tampered); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("verify('%s') error = %v, want %s", tampered, err, ErrInvalidSignature)
+		}
+	}
+
+	if _, err := newTokenSigner(); err == nil {
+		t.Errorf("newTokenSigner() without secrets succeeded")
+	}
+}
+
+func TestSessions_tokenRotation(t *testing.T) {
+	fakeClock := clock.NewFake(time.Unix(1615256178, 0))
+	sessions := NewSessions(fakeClock, random.New(1))
+	wdb := puzzle.WordDatabase{}
+
+	requestWithCookie := func(value string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: SESSION_COOKIE_NAME, Value: value})
+		return req
+	}
+
+	first := httptest.NewRecorder()
+	sess := HandleSession(first, httptest.NewRequest(http.MethodGet, "/", nil), &sessions, wdb)
+	cookie := first.Result().Cookies()[0].Value
+
+	t.Run("unsigned token rejected", func(t *testing.T) {
+		got := HandleSession(httptest.NewRecorder(), requestWithCookie(sess.token), &sessions, wdb)
+		if got.id == sess.id {
+			t.Errorf("cookie without signature accepted")
+		}
+	})
+
+	t.Run("periodic", func(t *testing.T) {
+		fakeClock.Advance(SESSION_TOKEN_ROTATION)
+
+		res := httptest.NewRecorder()
+		got := HandleSession(res, requestWithCookie(cookie), &sessions, wdb)
+		if got.id != sess.id {
+			t.Fatalf("session lost on token rotation, got id='%s', want id='%s'", got.id, sess.id)
+		}
+		if got.token == sess.token {
+			t.Fatalf("token not rotated after %s", SESSION_TOKEN_ROTATION)
+		}
+
+		if _, err := sessions.GetByCookie(cookie); err == nil {
+			t.Errorf("cookie of the rotated token still accepted")
+		}
+		if _, err := sessions.GetByCookie(res.Result().Cookies()[0].Value); err != nil {
+			t.Errorf("cookie of the new token rejected: %s", err)
+		}
+	})
+}
+
+// countingStore counts the saves of the wrapped Store.
+type countingStore struct {
+	Store
+	saves int
+}
+
+func (cs *countingStore) Save(s *session) (string, error) {
+	cs.saves++
+	return cs.Store.Save(s)
+}
+
+func TestPeekSession_savesOnce(t *testing.T) {
+	fakeClock := clock.NewFake(time.Unix(1615256178, 0))
+	sessions := NewSessions(fakeClock, random.New(1))
+	store := &countingStore{Store: MustNewCookieStore("secret")}
+	sessions.SetStore(store)
+
+	first := httptest.NewRecorder()
+	sess := HandleSession(first, httptest.NewRequest(http.MethodGet, "/", nil), &sessions, puzzle.WordDatabase{})
+
+	fakeClock.Advance(SESSION_TOKEN_ROTATION)
+	store.saves = 0
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(first.Result().Cookies()[0])
+	res := httptest.NewRecorder()
+	got, ok := PeekSession(res, req, &sessions)
+	if !ok || got.id != sess.id {
+		t.Fatalf("PeekSession() = %q, %t, want %q, true", got.id, ok, sess.id)
+	}
+	if store.saves != 1 {
+		t.Errorf("PeekSession() saved %d times, want once", store.saves)
+	}
+	if len(res.Result().Cookies()) != 1 || got.token == sess.token {
+		t.Errorf("PeekSession() did not send the rotated token")
+	}
+}
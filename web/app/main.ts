@@ -24,6 +24,8 @@ interface CustomHtmxEvent<T = any> extends Event {
 
             themeButtonToggleHandler();
             initKeyListener(state);
+            initSpeedTimer();
+            document.addEventListener('htmx:afterSettle', () => {initSpeedTimer()}, false);
             document.addEventListener('htmx:afterSettle', (event: CustomHtmxEvent) => {reset(state, event)}, false);
             document.addEventListener('htmx:afterSettle', (event: CustomHtmxEvent) => {onErrorMsg(event)}, false);
             document.addEventListener('htmx:afterSettle', (event: CustomHtmxEvent) => {onMessages(event)}, false);
@@ -55,6 +57,12 @@ interface CustomHtmxEvent<T = any> extends Event {
             return;
         }
 
+        // live updates pushed via server-sent events must not wipe what the player is typing
+        const target = event.target as Element | null
+        if (target?.hasAttribute('sse-swap') && target.querySelector('.focusable') === null) {
+            return;
+        }
+
         state.letters = [];
         state.inputs = document.querySelectorAll(".focusable");
     }
@@ -92,6 +100,36 @@ interface CustomHtmxEvent<T = any> extends Event {
         }, 5000);
     }
 
+    let speedTimerID: number | undefined;
+
+    // counts down the remaining time the server rendered, once it runs out
+    // the game is reloaded and the server declares it lost
+    function initSpeedTimer(): void {
+        window.clearInterval(speedTimerID);
+
+        const timer = document.getElementById('speed-timer');
+        if (timer === null) {
+            return
+        }
+
+        // remember the deadline on the element, so unrelated swaps don't reset it
+        if (timer.dataset.deadline === undefined) {
+            timer.dataset.deadline = String(Date.now() + Number(timer.dataset.remaining ?? 0) * 1000);
+        }
+        const deadline = Number(timer.dataset.deadline);
+
+        speedTimerID = window.setInterval(function() {
+            const remaining = Math.max(0, Math.ceil((deadline - Date.now()) / 1000));
+            const seconds = remaining % 60;
+            timer.textContent = Math.floor(remaining / 60) + ':' + (seconds < 10 ? '0' : '') + seconds;
+
+            if (remaining === 0) {
+                window.clearInterval(speedTimerID);
+                htmx.ajax('GET', '/lettr', {target: '#lettr-container'});
+            }
+        }, 1000);
+    }
+
     function initalThemeHandler() {
         // On page load or when changing themes, best to add inline in `head` to avoid FOUC
         if (localStorage.getItem('color-theme') === 'dark' || (!('color-theme' in localStorage) && window.matchMedia('(prefers-color-scheme: dark)').matches)) {
@@ -152,8 +190,9 @@ interface CustomHtmxEvent<T = any> extends Event {
 
     function initKeyListener(state: State): void {
         document.addEventListener('keyup', (e: KeyboardEvent) => {
-            const isSingleKey = e.key.length === 1
-            const isInAllowedKeyRange = (65 <= e.key.charCodeAt(0) && e.key.charCodeAt(0) <= 122)
+            const isSingleKey = [...e.key].length === 1
+            // any letter, the server validates against the alphabet of the active language
+            const isInAllowedKeyRange = e.key.toLowerCase() !== e.key.toUpperCase()
             const inputRowIsFillable = state.letters.length < state.inputs.length
             if (isSingleKey && isInAllowedKeyRange && inputRowIsFillable) {
                 state.letters.push(e.key);